@@ -16,6 +16,7 @@ write_test_status() {
     
     cat > "$filename" << EOF
 {
+    "schema_version": 1,
     "test_type": "$test_type",
     "target": "$target",
     "status": "$status",
@@ -0,0 +1,186 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// Annotation formats for -annotation-format
+const (
+	AnnotationJSON    = "json"    // A TestEvent
+	AnnotationGrafana = "grafana" // A Grafana HTTP API annotation, for POST /api/annotations
+)
+
+// TestEvent is a chaos test starting or ending
+type TestEvent struct {
+	Event    string     `json:"event"` // "start" or "end"
+	Type     string     `json:"type"`
+	Target   string     `json:"target"`
+	Instance string     `json:"instance,omitempty"` // The monitored LocalStack, from -target
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end,omitempty"` // Set on "end" events
+	Details  string     `json:"details,omitempty"`
+}
+
+// GrafanaAnnotation is a Grafana HTTP API annotation. An end event covers
+// the test's whole run as a region.
+type GrafanaAnnotation struct {
+	Time    int64    `json:"time"` // Unix milliseconds
+	TimeEnd int64    `json:"timeEnd,omitempty"`
+	Tags    []string `json:"tags"`
+	Text    string   `json:"text"`
+}
+
+// Annotations posts an event to a webhook whenever a chaos test starts or
+// ends, e.g. to annotate Grafana dashboards. Only transitions are posted; a
+// test that stays active between refreshes sends nothing.
+type Annotations struct {
+	url    string
+	format string
+	client *http.Client
+	queue  chan []byte // Encoded events, posted in order
+
+	// Tests a start was sent for, by instance, then type and target
+	active map[string]map[string]models.ActiveChaosTest
+}
+
+// annotationQueueSize is how many events can wait to be posted before new
+// ones are dropped
+const annotationQueueSize = 64
+
+// ValidAnnotationFormat reports whether format is a known -annotation-format
+func ValidAnnotationFormat(format string) bool {
+	return format == AnnotationJSON || format == AnnotationGrafana
+}
+
+// NewAnnotations returns an annotator posting to url in format until ctx
+// is cancelled
+func NewAnnotations(ctx context.Context, url, format string, client *http.Client) *Annotations {
+	a := &Annotations{
+		url:    url,
+		format: format,
+		client: client,
+		queue:  make(chan []byte, annotationQueueSize),
+		active: make(map[string]map[string]models.ActiveChaosTest),
+	}
+	go a.run(ctx)
+	return a
+}
+
+// Update compares the active tests of instance with those seen last time
+// and posts a start event for each new test and an end event for each one
+// that has completed or disappeared. It returns the events posted.
+func (a *Annotations) Update(instance string, tests []models.ActiveChaosTest, now time.Time) []TestEvent {
+	events := TestTransitions(a.active[instance], tests, now)
+
+	active := make(map[string]models.ActiveChaosTest)
+	for _, test := range tests {
+		if test.Status != "completed" {
+			active[test.Type+"|"+test.Target] = test
+		}
+	}
+	a.active[instance] = active
+
+	for _, event := range events {
+		event.Instance = instance
+		body, err := json.Marshal(a.payload(event))
+		if err != nil {
+			continue
+		}
+		select {
+		case a.queue <- body:
+		default:
+		}
+	}
+	return events
+}
+
+// TestTransitions returns start events for the tests not in prev, keyed by
+// type and target, and end events for those in prev that have completed or
+// are gone, in a stable order
+func TestTransitions(prev map[string]models.ActiveChaosTest, tests []models.ActiveChaosTest, now time.Time) []TestEvent {
+	var events []TestEvent
+	seen := make(map[string]bool)
+	for _, test := range tests {
+		key := test.Type + "|" + test.Target
+		if test.Status == "completed" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, ok := prev[key]; !ok {
+			events = append(events, TestEvent{Event: "start", Type: test.Type, Target: test.Target, Start: test.StartTime, Details: test.Details})
+		}
+	}
+	var ended []string
+	for key := range prev {
+		if !seen[key] {
+			ended = append(ended, key)
+		}
+	}
+	sort.Strings(ended)
+	for _, key := range ended {
+		test := prev[key]
+		end := now
+		events = append(events, TestEvent{Event: "end", Type: test.Type, Target: test.Target, Start: test.StartTime, End: &end, Details: test.Details})
+	}
+	return events
+}
+
+// payload encodes an event in the configured format
+func (a *Annotations) payload(event TestEvent) any {
+	if a.format != AnnotationGrafana {
+		return event
+	}
+	return GrafanaAnnotationFor(event)
+}
+
+// GrafanaAnnotationFor converts an event to a Grafana annotation tagged
+// "chaos" and the test type. A start marks a point; an end marks the
+// region from start to end.
+func GrafanaAnnotationFor(event TestEvent) GrafanaAnnotation {
+	annotation := GrafanaAnnotation{
+		Time: event.Start.UnixMilli(),
+		Tags: []string{"chaos", event.Type},
+		Text: fmt.Sprintf("Chaos test started: %s on %s", event.Type, event.Target),
+	}
+	if event.Instance != "" {
+		annotation.Tags = append(annotation.Tags, event.Instance)
+	}
+	if event.End != nil {
+		annotation.TimeEnd = event.End.UnixMilli()
+		annotation.Text = fmt.Sprintf("Chaos test: %s on %s (%s)", event.Type, event.Target, event.End.Sub(event.Start).Round(time.Second))
+	}
+	return annotation
+}
+
+// run posts queued events until ctx is cancelled
+func (a *Annotations) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case body := <-a.queue:
+			a.post(ctx, body)
+		}
+	}
+}
+
+func (a *Annotations) post(ctx context.Context, body []byte) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
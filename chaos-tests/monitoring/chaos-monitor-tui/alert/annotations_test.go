@@ -0,0 +1,137 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// annotationServer collects the bodies posted to it
+func annotationServer(t *testing.T) (*httptest.Server, chan []byte) {
+	t.Helper()
+	bodies := make(chan []byte, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("%s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	t.Cleanup(server.Close)
+	return server, bodies
+}
+
+// receive waits for n posted bodies, then checks no more arrive
+func receive(t *testing.T, bodies chan []byte, n int) [][]byte {
+	t.Helper()
+	var got [][]byte
+	for len(got) < n {
+		select {
+		case body := <-bodies:
+			got = append(got, body)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("posted %d events, want %d", len(got), n)
+		}
+	}
+	select {
+	case body := <-bodies:
+		t.Errorf("unexpected event %s", body)
+	case <-time.After(50 * time.Millisecond):
+	}
+	return got
+}
+
+func TestAnnotationsOneStartOneEnd(t *testing.T) {
+	server, bodies := annotationServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := NewAnnotations(ctx, server.URL, AnnotationJSON, server.Client())
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	running := models.ActiveChaosTest{Type: "region-failure", Target: "us-east-1", Status: "running", StartTime: start, Details: "from status file"}
+	completed := running
+	completed.Status = "completed"
+	// A lifecycle seen across refreshes: not yet started, running for three
+	// refreshes, reported completed, then gone
+	ticks := [][]models.ActiveChaosTest{
+		nil,
+		{running},
+		{running, running},
+		{running},
+		{completed},
+		nil,
+		nil,
+	}
+	var sent []string
+	for i, tests := range ticks {
+		for _, event := range a.Update("local", tests, start.Add(time.Duration(i)*time.Minute)) {
+			sent = append(sent, event.Event)
+		}
+	}
+	if !slices.Equal(sent, []string{"start", "end"}) {
+		t.Fatalf("events = %v, want one start and one end", sent)
+	}
+
+	got := receive(t, bodies, 2)
+	var started, ended TestEvent
+	if err := json.Unmarshal(got[0], &started); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(got[1], &ended); err != nil {
+		t.Fatal(err)
+	}
+	if started.Event != "start" || started.Type != "region-failure" || started.Target != "us-east-1" ||
+		started.Instance != "local" || !started.Start.Equal(start) || started.End != nil {
+		t.Errorf("start = %+v", started)
+	}
+	if ended.Event != "end" || ended.Type != "region-failure" || !ended.Start.Equal(start) ||
+		ended.End == nil || !ended.End.Equal(start.Add(4*time.Minute)) {
+		t.Errorf("end = %+v", ended)
+	}
+
+	// Another instance's tests are tracked separately
+	if events := a.Update("other", []models.ActiveChaosTest{running}, start); len(events) != 1 || events[0].Event != "start" {
+		t.Errorf("other instance: %+v", events)
+	}
+	if events := a.Update("local", nil, start); len(events) != 0 {
+		t.Errorf("local after the other instance: %+v", events)
+	}
+	receive(t, bodies, 1)
+}
+
+func TestGrafanaAnnotation(t *testing.T) {
+	server, bodies := annotationServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a := NewAnnotations(ctx, server.URL, AnnotationGrafana, server.Client())
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	test := models.ActiveChaosTest{Type: "service-outage", Target: "s3", Status: "running", StartTime: start}
+	a.Update("local", []models.ActiveChaosTest{test}, start)
+	a.Update("local", nil, start.Add(90*time.Second))
+
+	got := receive(t, bodies, 2)
+	var point, region GrafanaAnnotation
+	if err := json.Unmarshal(got[0], &point); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(got[1], &region); err != nil {
+		t.Fatal(err)
+	}
+	wantTags := []string{"chaos", "service-outage", "local"}
+	if point.Time != start.UnixMilli() || point.TimeEnd != 0 || !slices.Equal(point.Tags, wantTags) ||
+		point.Text != "Chaos test started: service-outage on s3" {
+		t.Errorf("start = %+v", point)
+	}
+	if region.Time != start.UnixMilli() || region.TimeEnd != start.Add(90*time.Second).UnixMilli() ||
+		region.Text != "Chaos test: service-outage on s3 (1m30s)" {
+		t.Errorf("end = %+v", region)
+	}
+}
@@ -0,0 +1,153 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// emailTimeout bounds a whole SMTP conversation
+const emailTimeout = 30 * time.Second
+
+// EmailConfig says where and how to send alert emails
+type EmailConfig struct {
+	Addr     string // SMTP server as host:port
+	From     string
+	To       []string
+	Username string // Authenticates with PLAIN when set
+	Password string
+}
+
+// Email sends one message per batch of alerts over SMTP, upgrading the
+// connection with STARTTLS when the server offers it
+type Email struct {
+	cfg    EmailConfig
+	logger *slog.Logger
+	queue  chan []byte // Formatted messages, sent in order
+}
+
+// emailQueueSize is how many messages can wait to be sent before new ones
+// are dropped
+const emailQueueSize = 16
+
+// NewEmail returns a sink sending through cfg until ctx is cancelled,
+// logging failures to logger
+func NewEmail(ctx context.Context, cfg EmailConfig, logger *slog.Logger) *Email {
+	e := &Email{cfg: cfg, logger: logger, queue: make(chan []byte, emailQueueSize)}
+	go e.run(ctx)
+	return e
+}
+
+// Send implements Sink. The message is queued for a background sender so a
+// slow server doesn't hold up the refresh. Failures, and messages dropped
+// when the queue is full, are logged.
+func (e *Email) Send(ctx context.Context, alerts []Alert, state *models.MonitorState) {
+	select {
+	case e.queue <- EmailMessage(e.cfg.From, e.cfg.To, alerts, state, time.Now()):
+	default:
+		e.logger.Warn("alert dropped", "sink", "email", "alerts", len(alerts), "reason", "queue full")
+	}
+}
+
+// run sends queued messages until ctx is cancelled
+func (e *Email) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-e.queue:
+			if err := e.send(msg); err != nil {
+				e.logger.Warn("alert send failed", "sink", "email", "error", err)
+			}
+		}
+	}
+}
+
+// send delivers msg. It follows smtp.SendMail, but with a deadline so a
+// stalled server can't block later messages.
+func (e *Email) send(msg []byte) error {
+	host, _, err := net.SplitHostPort(e.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("tcp", e.cfg.Addr, emailTimeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(emailTimeout))
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if e.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range e.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// EmailMessage formats alerts as a plain-text email, headers included
+func EmailMessage(from string, to []string, alerts []Alert, state *models.MonitorState, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: Chaos Monitor: %s\r\n", alertSummary(alerts))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+
+	for _, a := range alerts {
+		if a.Kind == "maintenance" {
+			fmt.Fprintf(&b, "%s\r\n\r\n", a.Message())
+			continue
+		}
+		fmt.Fprintf(&b, "%s\r\n", a.Message())
+		fmt.Fprintf(&b, "  Affected %s: %s\r\n", a.Kind, a.Name)
+		fmt.Fprintf(&b, "  Status: %s\r\n", a.Status)
+		fmt.Fprintf(&b, "  Availability: %.1f%%\r\n\r\n", a.Availability)
+	}
+
+	fmt.Fprintf(&b, "Chaos intensity: %.0f/100\r\n", state.ChaosIntensity)
+	b.WriteString("Active tests:\r\n")
+	for _, line := range strings.Split(activeTestSummary(state), "\n") {
+		fmt.Fprintf(&b, "  %s\r\n", line)
+	}
+	return b.Bytes()
+}
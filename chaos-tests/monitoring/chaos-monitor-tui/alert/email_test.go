@@ -0,0 +1,143 @@
+package alert
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// smtpSession is what a stub server was told during one conversation
+type smtpSession struct {
+	commands []string
+	data     string
+}
+
+// stubSMTP accepts one conversation on a loopback port, advertising AUTH
+// PLAIN but not STARTTLS, and reports it once the client quits
+func stubSMTP(t *testing.T) (addr string, session <-chan smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	done := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		var s smtpSession
+		text.PrintfLine("220 stub ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			s.commands = append(s.commands, line)
+			verb, _, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				text.PrintfLine("250-stub")
+				text.PrintfLine("250 AUTH PLAIN")
+			case "AUTH":
+				text.PrintfLine("235 authenticated")
+			case "DATA":
+				text.PrintfLine("354 go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				s.data = string(data)
+				text.PrintfLine("250 queued")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				done <- s
+				return
+			default:
+				text.PrintfLine("250 ok")
+			}
+		}
+	}()
+	return listener.Addr().String(), done
+}
+
+func TestEmailSend(t *testing.T) {
+	addr, session := stubSMTP(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	email := NewEmail(ctx, EmailConfig{
+		Addr:     addr,
+		From:     "monitor@example.com",
+		To:       []string{"oncall@example.com", "sre@example.com"},
+		Username: "monitor",
+		Password: "secret",
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	state := &models.MonitorState{ChaosIntensity: 55, ActiveTests: []models.ActiveChaosTest{{Type: "region-failure", Target: "us-east-1"}}}
+	email.Send(ctx, []Alert{{Key: "service|S3", Kind: "service", Name: "S3", Status: "service_outage", Checks: 3, Availability: 75}}, state)
+
+	var s smtpSession
+	select {
+	case s = <-session:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message delivered")
+	}
+
+	var verbs []string
+	for _, command := range s.commands {
+		verb, _, _ := strings.Cut(command, " ")
+		verbs = append(verbs, verb)
+	}
+	if got := strings.Join(verbs, " "); got != "EHLO AUTH MAIL RCPT RCPT DATA QUIT" {
+		t.Errorf("commands = %q", s.commands)
+	}
+	for _, want := range []string{"MAIL FROM:<monitor@example.com>", "RCPT TO:<oncall@example.com>", "RCPT TO:<sre@example.com>"} {
+		if !strings.Contains(strings.Join(s.commands, "\n"), want) {
+			t.Errorf("no %q in %q", want, s.commands)
+		}
+	}
+	for _, want := range []string{
+		"From: monitor@example.com\n",
+		"To: oncall@example.com, sre@example.com\n",
+		"Subject: Chaos Monitor: 1 down\n",
+		"S3 has been service_outage for 3 consecutive checks\n",
+		"  Availability: 75.0%\n",
+		"Chaos intensity: 55/100\n",
+	} {
+		if !strings.Contains(s.data, want) {
+			t.Errorf("message lacks %q:\n%s", want, s.data)
+		}
+	}
+}
+
+func TestEmailLogsFailures(t *testing.T) {
+	// Nothing listens on a closed listener's address
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, logged := newTestLogger()
+	email := NewEmail(ctx, EmailConfig{Addr: addr, From: "monitor@example.com", To: []string{"oncall@example.com"}}, logger)
+	email.Send(ctx, []Alert{{Key: "service|S3", Kind: "service", Name: "S3", Status: "service_outage", Checks: 3}}, &models.MonitorState{})
+
+	waitForLog(t, logged, "alert send failed", 1)
+	if !strings.Contains(logged.String(), "sink=email") || !strings.Contains(logged.String(), "connection refused") {
+		t.Errorf("logged %s", logged.String())
+	}
+}
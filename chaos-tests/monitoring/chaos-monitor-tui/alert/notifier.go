@@ -0,0 +1,238 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"chaos-monitor-tui/models"
+
+	"github.com/gen2brain/beeep"
+)
+
+// Alert is an endpoint or service whose failure just became sustained, or
+// that recovered after alerting
+type Alert struct {
+	Key          string // "endpoint|<name>", "service|<label>", "budget|<service>" or "maintenance"
+	Kind         string // "endpoint", "service", "budget" or "maintenance"
+	Name         string
+	Status       string
+	Checks       int     // Consecutive failing checks so far; alerts suppressed for "maintenance"
+	Availability float64 // Availability percentage over the session
+	Recovered    bool
+}
+
+// Message describes the alert for notifications
+func (a Alert) Message() string {
+	if a.Kind == "maintenance" {
+		return fmt.Sprintf("Maintenance mode ended, %d alerts suppressed", a.Checks)
+	}
+	if a.Kind == "budget" {
+		if a.Recovered {
+			return fmt.Sprintf("%s error budget has recovered (%s)", a.Name, a.Status)
+		}
+		return fmt.Sprintf("%s is burning its error budget (%s)", a.Name, a.Status)
+	}
+	if a.Recovered {
+		return fmt.Sprintf("%s has recovered (%s)", a.Name, a.Status)
+	}
+	return fmt.Sprintf("%s has been %s for %d consecutive checks", a.Name, a.Status, a.Checks)
+}
+
+// Sink delivers alerts somewhere. Implementations must not block the
+// refresh for long; network sinks send in the background.
+type Sink interface {
+	Send(ctx context.Context, alerts []Alert, state *models.MonitorState)
+}
+
+// Notifier raises an alert once a row has failed for threshold consecutive
+// checks. Each outage episode alerts once; when the row passes again a
+// recovery is raised and the alert re-arms.
+type Notifier struct {
+	threshold int
+	sinks     []Sink
+
+	failing map[string]int  // Consecutive failing checks per row
+	alerted map[string]bool // Rows that already alerted in this episode
+
+	// Error budget alerting; disabled unless the SLO is enabled
+	slo             models.SLO
+	budgetThreshold float64 // Remaining budget percentage to alert below
+
+	// Maintenance mode: alerts are tracked but not delivered
+	suppressed bool
+	held       int // Alerts not delivered since maintenance mode started
+}
+
+// NewNotifier creates a notifier delivering to sinks
+func NewNotifier(threshold int, sinks ...Sink) *Notifier {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Notifier{
+		threshold: threshold,
+		sinks:     sinks,
+		failing:   make(map[string]int),
+		alerted:   make(map[string]bool),
+	}
+}
+
+// AlertOnBudget also raises an alert as soon as a service's remaining error
+// budget under slo falls below threshold percent, and a recovery once it's
+// back above
+func (n *Notifier) AlertOnBudget(slo models.SLO, threshold float64) {
+	n.slo = slo
+	n.budgetThreshold = threshold
+}
+
+// Observe updates the failure counts from a tick and returns the rows that
+// crossed the threshold or recovered on it
+func (n *Notifier) Observe(state *models.MonitorState) []Alert {
+	var alerts []Alert
+	seen := make(map[string]bool)
+
+	check := func(a Alert, ok bool, threshold int) {
+		seen[a.Key] = true
+		if ok {
+			if n.alerted[a.Key] {
+				a.Recovered = true
+				alerts = append(alerts, a)
+			}
+			// Re-arm for the next episode
+			delete(n.failing, a.Key)
+			delete(n.alerted, a.Key)
+			return
+		}
+		n.failing[a.Key]++
+		if n.failing[a.Key] >= threshold && !n.alerted[a.Key] {
+			n.alerted[a.Key] = true
+			a.Checks = n.failing[a.Key]
+			alerts = append(alerts, a)
+		}
+	}
+
+	for _, endpoint := range state.NginxEndpoints {
+		a := Alert{Key: "endpoint|" + endpoint.Name, Kind: "endpoint", Name: endpoint.Name, Status: endpoint.Status}
+		if stats, ok := state.Stats.NginxStats[endpoint.Name]; ok {
+			a.Availability = stats.SuccessRate
+		}
+		check(a, endpoint.Available(), n.threshold)
+	}
+	for _, service := range state.AWSServices {
+		// Nothing was checked without docker, so it can't be an outage
+		if service.Status == "unavailable" {
+			continue
+		}
+		a := Alert{Key: "service|" + service.Label(), Kind: "service", Name: service.Label(), Status: service.Status}
+		if stats, ok := state.Stats.ServiceStats[service.Name]; ok {
+			a.Availability = stats.AvailabilityPct
+		}
+		check(a, service.Status == "healthy", n.threshold)
+	}
+
+	if n.slo.Enabled() {
+		elapsed := state.LastUpdate.Sub(state.Stats.StartTime)
+		names := make([]string, 0, len(state.Stats.ServiceStats))
+		for name := range state.Stats.ServiceStats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			stats := state.Stats.ServiceStats[name]
+			remaining := n.slo.BudgetRemaining(stats.AvailabilityPct, elapsed)
+			a := Alert{
+				Key:          "budget|" + name,
+				Kind:         "budget",
+				Name:         name,
+				Status:       fmt.Sprintf("%.0f%% of budget left", remaining),
+				Availability: stats.AvailabilityPct,
+			}
+			// The budget is cumulative, so one reading below the line is enough
+			check(a, remaining >= n.budgetThreshold, 1)
+		}
+	}
+
+	// Forget rows that are no longer monitored
+	for key := range n.failing {
+		if !seen[key] {
+			delete(n.failing, key)
+			delete(n.alerted, key)
+		}
+	}
+
+	return alerts
+}
+
+// Update observes a tick and delivers any resulting alerts to every sink,
+// or only counts them in maintenance mode
+func (n *Notifier) Update(ctx context.Context, state *models.MonitorState) {
+	alerts := n.Observe(state)
+	if len(alerts) == 0 {
+		return
+	}
+	if n.suppressed {
+		n.held += len(alerts)
+		return
+	}
+	n.send(ctx, alerts, state)
+}
+
+// SetSuppressed turns maintenance mode on or off. Outages are still
+// tracked while it's on, so one that started during maintenance doesn't
+// alert again afterwards. Turning it off sends a single summary with the
+// number of alerts held back.
+func (n *Notifier) SetSuppressed(ctx context.Context, state *models.MonitorState, suppressed bool) {
+	if suppressed == n.suppressed {
+		return
+	}
+	n.suppressed = suppressed
+	if suppressed {
+		n.held = 0
+		return
+	}
+	n.send(ctx, []Alert{{
+		Key:       "maintenance",
+		Kind:      "maintenance",
+		Name:      "Maintenance mode",
+		Status:    "ended",
+		Checks:    n.held,
+		Recovered: true,
+	}}, state)
+}
+
+// Held is the number of alerts not delivered since maintenance mode started
+func (n *Notifier) Held() int {
+	return n.held
+}
+
+func (n *Notifier) send(ctx context.Context, alerts []Alert, state *models.MonitorState) {
+	for _, sink := range n.sinks {
+		sink.Send(ctx, alerts, state)
+	}
+}
+
+// Bell rings the terminal bell for new outages and, when Desktop is set,
+// sends a desktop notification for each
+type Bell struct {
+	W       io.Writer
+	Desktop bool
+}
+
+// Send implements Sink
+func (b Bell) Send(ctx context.Context, alerts []Alert, state *models.MonitorState) {
+	rung := false
+	for _, a := range alerts {
+		if a.Recovered {
+			continue
+		}
+		if !rung {
+			fmt.Fprint(b.W, "\a")
+			rung = true
+		}
+		if b.Desktop {
+			// notify-send and friends can be slow; don't hold up the tick
+			go beeep.Notify("Chaos Monitor: "+a.Name+" down", a.Message(), "")
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package alert
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestBudgetAlertBoundary(t *testing.T) {
+	// A 50% target over two hours allows an hour of unavailability, so
+	// after an hour each availability point is a point of budget
+	n := NewNotifier(3)
+	n.AlertOnBudget(models.SLO{Target: 50, Window: 2 * time.Hour}, 50)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	observe := func(availability float64) []Alert {
+		state := models.MonitorState{
+			LastUpdate: start.Add(time.Hour),
+			Stats: models.Statistics{
+				StartTime:    start,
+				ServiceStats: map[string]*models.ServiceStats{"S3": {AvailabilityPct: availability}},
+			},
+		}
+		return n.Observe(&state)
+	}
+
+	if alerts := observe(50); len(alerts) != 0 {
+		t.Errorf("exactly at the threshold alerted: %+v", alerts)
+	}
+	alerts := observe(49)
+	if len(alerts) != 1 || alerts[0].Kind != "budget" || alerts[0].Recovered || alerts[0].Status != "49% of budget left" {
+		t.Fatalf("below the threshold: %+v", alerts)
+	}
+	if alerts := observe(40); len(alerts) != 0 {
+		t.Errorf("alerted again in the same episode: %+v", alerts)
+	}
+	alerts = observe(50)
+	if len(alerts) != 1 || !alerts[0].Recovered {
+		t.Errorf("back at the threshold: %+v", alerts)
+	}
+}
+
+// syncBuffer collects the log output of a sink's background sender
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newTestLogger returns a logger writing to a syncBuffer
+func newTestLogger() (*slog.Logger, *syncBuffer) {
+	logged := &syncBuffer{}
+	return slog.New(slog.NewTextHandler(logged, nil)), logged
+}
+
+// waitForLog waits until want has been logged n times, failing the test
+// if it isn't within a few seconds
+func waitForLog(t *testing.T, logged *syncBuffer, want string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Count(logged.String(), want) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("logged %q %d times, want %d:\n%s", want, strings.Count(logged.String(), want), n, logged.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,158 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// PagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyEvent is an Events API v2 request body. Payload is omitted for
+// resolve events, which only need the dedup key.
+type PagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"` // "trigger" or "resolve"
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *PagerDutyPayload `json:"payload,omitempty"`
+}
+
+// PagerDutyPayload describes a triggered incident
+type PagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"` // "critical", "error", "warning" or "info"
+	Timestamp     string            `json:"timestamp"`
+	Component     string            `json:"component"`
+	Class         string            `json:"class"`
+	CustomDetails map[string]string `json:"custom_details"`
+}
+
+// PagerDuty sends alerts to the PagerDuty Events API v2. Each row keeps the
+// same dedup key across outage episodes, so a repeated trigger updates the
+// open incident rather than opening another, and a recovery resolves it.
+type PagerDuty struct {
+	routingKey string
+	url        string
+	client     *http.Client
+	logger     *slog.Logger
+	queue      chan []byte // Encoded events, posted in order
+}
+
+// pagerDutyQueueSize is how many events can wait to be posted before new
+// ones are dropped
+const pagerDutyQueueSize = 64
+
+// NewPagerDuty returns a sink posting to url (PagerDutyEventsURL when
+// empty) until ctx is cancelled, logging failures to logger
+func NewPagerDuty(ctx context.Context, routingKey, url string, client *http.Client, logger *slog.Logger) *PagerDuty {
+	if url == "" {
+		url = PagerDutyEventsURL
+	}
+	p := &PagerDuty{routingKey: routingKey, url: url, client: client, logger: logger, queue: make(chan []byte, pagerDutyQueueSize)}
+	go p.run(ctx)
+	return p
+}
+
+// Send implements Sink. Events are queued for a single background poster,
+// so a slow API doesn't hold up the refresh and a resolve can't overtake
+// its trigger. Failures, and events dropped when the queue is full, are
+// logged.
+func (p *PagerDuty) Send(ctx context.Context, alerts []Alert, state *models.MonitorState) {
+	for _, a := range alerts {
+		// A maintenance summary isn't an incident
+		if a.Kind == "maintenance" {
+			continue
+		}
+		body, err := json.Marshal(PagerDutyEventFor(p.routingKey, a, state))
+		if err != nil {
+			continue
+		}
+		select {
+		case p.queue <- body:
+		default:
+			p.logger.Warn("alert dropped", "sink", "pagerduty", "key", a.Key, "reason", "queue full")
+		}
+	}
+}
+
+// run posts queued events until ctx is cancelled
+func (p *PagerDuty) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case body := <-p.queue:
+			if err := p.post(ctx, body); err != nil {
+				p.logger.Warn("alert send failed", "sink", "pagerduty", "error", err)
+			}
+		}
+	}
+}
+
+func (p *PagerDuty) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("events API returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// PagerDutyEventFor builds the trigger or resolve event for an alert
+func PagerDutyEventFor(routingKey string, a Alert, state *models.MonitorState) PagerDutyEvent {
+	event := PagerDutyEvent{
+		RoutingKey:  routingKey,
+		EventAction: "trigger",
+		DedupKey:    "chaos-monitor/" + a.Key,
+	}
+	if a.Recovered {
+		event.EventAction = "resolve"
+		return event
+	}
+	event.Payload = &PagerDutyPayload{
+		Summary:   "Chaos Monitor: " + a.Message(),
+		Source:    "chaos-monitor-tui",
+		Severity:  PagerDutySeverity(a, state.ChaosIntensity),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Component: a.Name,
+		Class:     a.Kind,
+		CustomDetails: map[string]string{
+			"status":          a.Status,
+			"availability":    fmt.Sprintf("%.1f%%", a.Availability),
+			"chaos_intensity": fmt.Sprintf("%.0f/100", state.ChaosIntensity),
+			"active_tests":    activeTestSummary(state),
+		},
+	}
+	return event
+}
+
+// PagerDutySeverity maps an alert to a PagerDuty severity: critical when
+// the row is mostly down or the chaos is intense, warning when it's only
+// degraded, and error otherwise
+func PagerDutySeverity(a Alert, intensity float64) string {
+	switch {
+	case a.Availability < 50 || intensity >= 75:
+		return "critical"
+	case a.Status == "throttled" || a.Status == "timeout":
+		return "warning"
+	default:
+		return "error"
+	}
+}
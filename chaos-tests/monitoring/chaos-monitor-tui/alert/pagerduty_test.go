@@ -0,0 +1,138 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestPagerDutyTriggerAndResolve(t *testing.T) {
+	events := make(chan PagerDutyEvent, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("%s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		var event PagerDutyEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("%v in %s", err, body)
+		}
+		events <- event
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pd := NewPagerDuty(ctx, "routing-key", server.URL, server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	state := &models.MonitorState{ChaosIntensity: 40, ActiveTests: []models.ActiveChaosTest{{Type: "service-outage", Target: "s3"}}}
+	down := Alert{Key: "service|S3", Kind: "service", Name: "S3", Status: "service_outage", Checks: 3, Availability: 80}
+	pd.Send(ctx, []Alert{down}, state)
+	// Still down a few checks later, and a maintenance summary that isn't an incident
+	down.Checks = 6
+	pd.Send(ctx, []Alert{down, {Key: "maintenance", Kind: "maintenance", Checks: 2}}, state)
+	pd.Send(ctx, []Alert{{Key: "service|S3", Kind: "service", Name: "S3", Status: "healthy", Recovered: true, Availability: 85}}, state)
+
+	var got []PagerDutyEvent
+	for len(got) < 3 {
+		select {
+		case event := <-events:
+			got = append(got, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("posted %d events, want 3: %+v", len(got), got)
+		}
+	}
+	select {
+	case event := <-events:
+		t.Errorf("unexpected event %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for i, action := range []string{"trigger", "trigger", "resolve"} {
+		if got[i].EventAction != action || got[i].RoutingKey != "routing-key" || got[i].DedupKey != "chaos-monitor/service|S3" {
+			t.Errorf("event %d = %+v, want a %s for the same incident", i, got[i], action)
+		}
+	}
+	payload := got[1].Payload
+	if payload == nil {
+		t.Fatal("trigger has no payload")
+	}
+	if payload.Summary != "Chaos Monitor: S3 has been service_outage for 6 consecutive checks" ||
+		payload.Source != "chaos-monitor-tui" || payload.Severity != "error" ||
+		payload.Component != "S3" || payload.Class != "service" {
+		t.Errorf("payload = %+v", payload)
+	}
+	if _, err := time.Parse(time.RFC3339, payload.Timestamp); err != nil {
+		t.Errorf("timestamp: %v", err)
+	}
+	if d := payload.CustomDetails; d["availability"] != "80.0%" || d["chaos_intensity"] != "40/100" || d["status"] != "service_outage" {
+		t.Errorf("custom details = %v", d)
+	}
+	if got[2].Payload != nil {
+		t.Errorf("resolve has a payload: %+v", got[2].Payload)
+	}
+}
+
+func TestPagerDutySeverity(t *testing.T) {
+	tests := []struct {
+		alert     Alert
+		intensity float64
+		want      string
+	}{
+		{Alert{Status: "failed", Availability: 40}, 0, "critical"},
+		{Alert{Status: "throttled", Availability: 95}, 80, "critical"},
+		{Alert{Status: "throttled", Availability: 95}, 10, "warning"},
+		{Alert{Status: "timeout", Availability: 60}, 10, "warning"},
+		{Alert{Status: "failed", Availability: 60}, 10, "error"},
+	}
+	for _, tt := range tests {
+		if got := PagerDutySeverity(tt.alert, tt.intensity); got != tt.want {
+			t.Errorf("PagerDutySeverity(%+v, %v) = %q, want %q", tt.alert, tt.intensity, got, tt.want)
+		}
+	}
+}
+
+func TestPagerDutyLogsFailures(t *testing.T) {
+	received := make(chan struct{}, pagerDutyQueueSize+1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, logged := newTestLogger()
+	pd := NewPagerDuty(ctx, "routing-key", server.URL, server.Client(), logger)
+
+	// With the first event in flight, the queue takes pagerDutyQueueSize
+	// more and drops the rest
+	down := Alert{Key: "service|S3", Kind: "service", Name: "S3", Status: "service_outage", Checks: 3}
+	state := &models.MonitorState{}
+	pd.Send(ctx, []Alert{down}, state)
+	<-received
+	for i := 0; i < pagerDutyQueueSize+2; i++ {
+		pd.Send(ctx, []Alert{down}, state)
+	}
+	if n := strings.Count(logged.String(), "alert dropped"); n != 2 {
+		t.Errorf("logged %d drops, want 2:\n%s", n, logged.String())
+	}
+
+	// A rejected event is a failure, not a delivery
+	close(release)
+	waitForLog(t, logged, "alert send failed", pagerDutyQueueSize+1)
+	if !strings.Contains(logged.String(), "sink=pagerduty") || !strings.Contains(logged.String(), "returned 400") {
+		t.Errorf("logged %s", logged.String())
+	}
+}
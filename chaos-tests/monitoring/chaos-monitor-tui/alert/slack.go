@@ -0,0 +1,195 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// Slack attachment colors
+const (
+	slackRed    = "#d00000"
+	slackYellow = "#e8a317"
+	slackGreen  = "#2eb67d"
+)
+
+// SlackMessage is an incoming-webhook payload
+type SlackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []SlackAttachment `json:"attachments"`
+}
+
+// SlackAttachment is a message attachment, shown with a color bar
+type SlackAttachment struct {
+	Color    string       `json:"color"`
+	Fallback string       `json:"fallback"`
+	Title    string       `json:"title"`
+	Text     string       `json:"text,omitempty"`
+	Fields   []SlackField `json:"fields"`
+	Ts       int64        `json:"ts"`
+}
+
+// SlackField is a title/value pair in an attachment
+type SlackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// Slack posts alerts to a Slack incoming webhook
+type Slack struct {
+	url    string
+	client *http.Client
+	logger *slog.Logger
+	queue  chan []byte // Encoded messages, posted in order
+}
+
+// slackQueueSize is how many messages can wait to be posted before new
+// ones are dropped
+const slackQueueSize = 16
+
+// NewSlack returns a sink posting to the webhook url until ctx is
+// cancelled, logging failures to logger
+func NewSlack(ctx context.Context, url string, client *http.Client, logger *slog.Logger) *Slack {
+	s := &Slack{url: url, client: client, logger: logger, queue: make(chan []byte, slackQueueSize)}
+	go s.run(ctx)
+	return s
+}
+
+// Send implements Sink. Messages are queued for a single background poster,
+// so a slow webhook neither holds up the refresh nor piles up goroutines,
+// and a recovery can't overtake its outage. Failures, and messages dropped
+// when the queue is full, are logged.
+func (s *Slack) Send(ctx context.Context, alerts []Alert, state *models.MonitorState) {
+	body, err := json.Marshal(SlackPayload(alerts, state))
+	if err != nil {
+		return
+	}
+	select {
+	case s.queue <- body:
+	default:
+		s.logger.Warn("alert dropped", "sink", "slack", "alerts", len(alerts), "reason", "queue full")
+	}
+}
+
+// run posts queued messages until ctx is cancelled
+func (s *Slack) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case body := <-s.queue:
+			if err := s.post(ctx, body); err != nil {
+				s.logger.Warn("alert send failed", "sink", "slack", "error", err)
+			}
+		}
+	}
+}
+
+func (s *Slack) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SlackPayload formats alerts as a Slack message with one attachment per
+// alert, colored by severity
+func SlackPayload(alerts []Alert, state *models.MonitorState) SlackMessage {
+	msg := SlackMessage{Text: "Chaos Monitor: " + alertSummary(alerts)}
+	tests := activeTestSummary(state)
+	for _, a := range alerts {
+		attachment := SlackAttachment{
+			Color:    slackColor(a),
+			Fallback: a.Message(),
+			Title:    a.Message(),
+			Ts:       time.Now().Unix(),
+			Fields: []SlackField{
+				{Title: "Affected " + a.Kind, Value: a.Name, Short: true},
+				{Title: "Status", Value: a.Status, Short: true},
+				{Title: "Availability", Value: fmt.Sprintf("%.1f%%", a.Availability), Short: true},
+				{Title: "Active tests", Value: tests, Short: false},
+			},
+		}
+		if a.Kind == "maintenance" {
+			// Only the message applies to a maintenance summary
+			attachment.Fields = nil
+		}
+		msg.Attachments = append(msg.Attachments, attachment)
+	}
+	return msg
+}
+
+// alertSummary counts the alerts, e.g. "2 down, 1 recovered". A
+// maintenance summary, which is always sent alone, is described as is.
+func alertSummary(alerts []Alert) string {
+	down, recovered := 0, 0
+	for _, a := range alerts {
+		if a.Kind == "maintenance" {
+			return a.Message()
+		}
+		if a.Recovered {
+			recovered++
+		} else {
+			down++
+		}
+	}
+	var summary []string
+	if down > 0 {
+		summary = append(summary, fmt.Sprintf("%d down", down))
+	}
+	if recovered > 0 {
+		summary = append(summary, fmt.Sprintf("%d recovered", recovered))
+	}
+	return strings.Join(summary, ", ")
+}
+
+// slackColor is green for recoveries, yellow for degradation and red for
+// outages
+func slackColor(a Alert) string {
+	switch {
+	case a.Recovered:
+		return slackGreen
+	case a.Status == "throttled" || a.Status == "timeout":
+		return slackYellow
+	default:
+		return slackRed
+	}
+}
+
+// activeTestSummary lists the detected chaos tests, one per line
+func activeTestSummary(state *models.MonitorState) string {
+	if len(state.ActiveTests) == 0 {
+		return "none detected"
+	}
+	var lines []string
+	for _, test := range state.ActiveTests {
+		line := test.Type
+		if test.Target != "" {
+			line += " → " + test.Target
+		}
+		if test.Details != "" {
+			line += " (" + test.Details + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
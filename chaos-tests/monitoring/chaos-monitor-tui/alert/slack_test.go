@@ -0,0 +1,162 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestSlackPayloadSchema(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("%s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer server.Close()
+
+	state := &models.MonitorState{ActiveTests: []models.ActiveChaosTest{{Type: "region-failure", Target: "us-east-1", Details: "PID 42"}}}
+	alerts := []Alert{
+		{Key: "endpoint|Main Site", Kind: "endpoint", Name: "Main Site", Status: "failed", Checks: 3, Availability: 87.5},
+		{Key: "service|S3", Kind: "service", Name: "S3", Status: "throttled", Checks: 3, Availability: 90},
+		{Key: "service|SQS", Kind: "service", Name: "SQS", Status: "healthy", Recovered: true, Availability: 99},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	NewSlack(ctx, server.URL, server.Client(), slog.New(slog.NewTextHandler(io.Discard, nil))).Send(ctx, alerts, state)
+
+	var body []byte
+	select {
+	case body = <-bodies:
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing posted")
+	}
+
+	// Decode loosely so the field names and types Slack expects are checked
+	var msg struct {
+		Text        string `json:"text"`
+		Attachments []struct {
+			Color    string `json:"color"`
+			Fallback string `json:"fallback"`
+			Title    string `json:"title"`
+			Ts       int64  `json:"ts"`
+			Fields   []struct {
+				Title string `json:"title"`
+				Value string `json:"value"`
+				Short bool   `json:"short"`
+			} `json:"fields"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatalf("%v in %s", err, body)
+	}
+	if msg.Text != "Chaos Monitor: 2 down, 1 recovered" || len(msg.Attachments) != 3 {
+		t.Fatalf("payload = %s", body)
+	}
+
+	wantColors := []string{slackRed, slackYellow, slackGreen}
+	for i, attachment := range msg.Attachments {
+		if attachment.Color != wantColors[i] || attachment.Title != alerts[i].Message() || attachment.Fallback != attachment.Title || attachment.Ts == 0 {
+			t.Errorf("attachment %d = %+v", i, attachment)
+		}
+		if len(attachment.Fields) != 4 {
+			t.Errorf("attachment %d fields = %+v", i, attachment.Fields)
+			continue
+		}
+		if f := attachment.Fields[0]; f.Title != "Affected "+alerts[i].Kind || f.Value != alerts[i].Name || !f.Short {
+			t.Errorf("attachment %d affected field = %+v", i, f)
+		}
+		if f := attachment.Fields[3]; f.Title != "Active tests" || f.Value != "region-failure → us-east-1 (PID 42)" || f.Short {
+			t.Errorf("attachment %d tests field = %+v", i, f)
+		}
+	}
+	if f := msg.Attachments[0].Fields[2]; f.Value != "87.5%" {
+		t.Errorf("availability field = %+v", f)
+	}
+
+	// A maintenance summary is just the message
+	summary := SlackPayload([]Alert{{Key: "maintenance", Kind: "maintenance", Checks: 4, Recovered: true}}, state)
+	if summary.Text != "Chaos Monitor: Maintenance mode ended, 4 alerts suppressed" || len(summary.Attachments) != 1 || summary.Attachments[0].Fields != nil {
+		t.Errorf("maintenance payload = %+v", summary)
+	}
+}
+
+func TestSlackLogsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, logged := newTestLogger()
+	NewSlack(ctx, server.URL, server.Client(), logger).Send(ctx, []Alert{{Key: "service|S3", Kind: "service", Name: "S3", Status: "service_outage", Checks: 3}}, &models.MonitorState{})
+
+	waitForLog(t, logged, "alert send failed", 1)
+	if !strings.Contains(logged.String(), "sink=slack") || !strings.Contains(logged.String(), "returned 400") {
+		t.Errorf("logged %s", logged.String())
+	}
+}
+
+func TestSlackQueue(t *testing.T) {
+	texts := make(chan string, slackQueueSize+1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg SlackMessage
+		json.NewDecoder(r.Body).Decode(&msg)
+		texts <- msg.Attachments[0].Title
+		<-release
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger, logged := newTestLogger()
+	slack := NewSlack(ctx, server.URL, server.Client(), logger)
+	send := func(checks int) {
+		slack.Send(ctx, []Alert{{Key: "service|S3", Kind: "service", Name: "S3", Status: "service_outage", Checks: checks}}, &models.MonitorState{})
+	}
+
+	// While the first message is in flight the rest wait in the queue,
+	// and once it's full new ones are dropped
+	send(1)
+	first := <-texts
+	for checks := 2; checks <= slackQueueSize+2; checks++ {
+		send(checks)
+	}
+	select {
+	case text := <-texts:
+		t.Fatalf("%q posted while another post was in flight", text)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if n := strings.Count(logged.String(), "alert dropped"); n != 1 {
+		t.Errorf("logged %d drops, want 1:\n%s", n, logged.String())
+	}
+
+	close(release)
+	got := []string{first}
+	for len(got) < slackQueueSize+1 {
+		select {
+		case text := <-texts:
+			got = append(got, text)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("posted %d messages, want %d", len(got), slackQueueSize+1)
+		}
+	}
+	for i, text := range got {
+		if want := fmt.Sprintf("S3 has been service_outage for %d consecutive checks", i+1); text != want {
+			t.Errorf("message %d = %q, want %q", i, text, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// apiServer serves the latest monitor state over HTTP. Handlers run on
+// their own goroutines, so they read the state the model publishes to the
+// shared store rather than the model's own.
+type apiServer struct {
+	server *http.Server
+	store  *stateStore
+}
+
+// healthResponse is the body returned by GET /healthz
+type healthResponse struct {
+	Status      string    `json:"status"` // "ok", or "starting" before the first refresh
+	LastUpdate  time.Time `json:"last_update"`
+	UpdateCount int       `json:"update_count"`
+}
+
+// newAPIServer listens on addr and starts serving in the background, so a
+// bad address is reported before the dashboard starts
+func newAPIServer(addr string, store *stateStore) (*apiServer, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	api := &apiServer{store: store}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", api.handleState)
+	mux.HandleFunc("/tests", api.handleTests)
+	mux.HandleFunc("/healthz", api.handleHealth)
+	api.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	// Serve only fails once the listener is closed, which shutdown does
+	go api.server.Serve(ln)
+	return api, nil
+}
+
+func (a *apiServer) handleState(w http.ResponseWriter, r *http.Request) {
+	state := a.store.snapshot()
+	writeJSON(w, r, &state)
+}
+
+func (a *apiServer) handleTests(w http.ResponseWriter, r *http.Request) {
+	tests := a.store.snapshot().ActiveTests
+	if tests == nil {
+		tests = []models.ActiveChaosTest{}
+	}
+	writeJSON(w, r, tests)
+}
+
+func (a *apiServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	state := a.store.snapshot()
+	resp := healthResponse{Status: "starting"}
+	if state.UpdateCount > 0 {
+		resp = healthResponse{Status: "ok", LastUpdate: state.LastUpdate, UpdateCount: state.UpdateCount}
+	}
+	writeJSON(w, r, resp)
+}
+
+// writeJSON encodes v as the response body, allowing only GET and HEAD
+func writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// shutdown stops the server, waiting briefly for in-flight requests
+func (a *apiServer) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return a.server.Shutdown(ctx)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// getJSON calls handler with a GET and decodes its JSON response into v
+func getJSON(t *testing.T, handler http.HandlerFunc, v any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAPIResponses(t *testing.T) {
+	store := newStateStore()
+	api := &apiServer{store: store}
+
+	// Before the first refresh
+	var health map[string]any
+	getJSON(t, api.handleHealth, &health)
+	if health["status"] != "starting" || health["update_count"] != 0.0 {
+		t.Errorf("/healthz before a refresh = %v", health)
+	}
+	var tests []any
+	getJSON(t, api.handleTests, &tests)
+	if tests == nil || len(tests) != 0 {
+		t.Errorf("/tests before a refresh = %v, want []", tests)
+	}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	state := newTestModel(t).state
+	state.LastUpdate = now
+	state.UpdateCount = 3
+	state.NginxEndpoints = []models.EndpointStatus{{Name: "Main Site", Status: "ok"}}
+	state.ActiveTests = []models.ActiveChaosTest{{Type: "region-failure", Target: "us-east-1", Status: "active", StartTime: now}}
+	store.publish(&state)
+
+	getJSON(t, api.handleHealth, &health)
+	if health["status"] != "ok" || health["update_count"] != 3.0 || health["last_update"] != "2024-05-01T12:00:00Z" {
+		t.Errorf("/healthz = %v", health)
+	}
+
+	var active []map[string]any
+	getJSON(t, api.handleTests, &active)
+	if len(active) != 1 || active[0]["type"] != "region-failure" || active[0]["target"] != "us-east-1" {
+		t.Errorf("/tests = %v", active)
+	}
+
+	var full map[string]json.RawMessage
+	getJSON(t, api.handleState, &full)
+	for _, key := range []string{"chaos_api_faults", "nginx_endpoints", "aws_services", "stats", "last_update", "update_count", "active_tests", "localstack"} {
+		if _, ok := full[key]; !ok {
+			t.Errorf("/state has no %q", key)
+		}
+	}
+	var endpoints []models.EndpointStatus
+	if err := json.Unmarshal(full["nginx_endpoints"], &endpoints); err != nil || len(endpoints) != 1 || endpoints[0].Name != "Main Site" {
+		t.Errorf("/state nginx_endpoints = %s", full["nginx_endpoints"])
+	}
+
+	rec := httptest.NewRecorder()
+	api.handleState(rec, httptest.NewRequest(http.MethodPost, "/state", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("POST /state: %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
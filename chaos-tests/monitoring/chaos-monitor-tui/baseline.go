@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"chaos-monitor-tui/models"
+)
+
+// loadBaseline reads the statistics of an earlier run to compare against.
+// path is a JSON snapshot, from -once -json or 'Y', or a -record file, of
+// which the last state is used.
+func loadBaseline(path string) (*models.Statistics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var state models.MonitorState
+	if err := json.Unmarshal(data, &state); err != nil || !hasStats(&state.Stats) {
+		session, err := loadReplay(path, 1)
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither a JSON snapshot nor a -record file", path)
+		}
+		state = session.frames[len(session.frames)-1].State
+	}
+	if !hasStats(&state.Stats) {
+		return nil, fmt.Errorf("no statistics in %s", path)
+	}
+	return &state.Stats, nil
+}
+
+// hasStats reports whether any endpoint or service was checked
+func hasStats(stats *models.Statistics) bool {
+	return len(stats.NginxStats) > 0 || len(stats.ServiceStats) > 0
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestLoadBaseline(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	var state models.MonitorState
+	state.Stats.NginxStats = map[string]*models.EndpointStats{"Main Site": {TotalChecks: 10, SuccessRate: 90, ResponseTimeEMA: 0.25}}
+	data, err := json.Marshal(&state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := loadBaseline(write("snapshot.json", data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stats.NginxStats["Main Site"]; got == nil || got.SuccessRate != 90 || got.ResponseTimeEMA != 0.25 {
+		t.Errorf("baseline = %+v", stats.NginxStats)
+	}
+
+	for name, data := range map[string]string{
+		"empty.json":   `{"stats":{}}`,
+		"garbage.json": `not json`,
+	} {
+		if _, err := loadBaseline(write(name, []byte(data))); err == nil {
+			t.Errorf("%s loaded", name)
+		}
+	}
+	if _, err := loadBaseline(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("missing file: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"chaos-monitor-tui/models"
+)
+
+// maxPayloadExcerpt is how much of an unexpected Chaos API body is quoted
+// in the warning
+const maxPayloadExcerpt = 60
+
+// parseChaosFaults decodes a /_localstack/chaos/faults body. LocalStack
+// returns an array, but a wrapped {"faults": [...]} or a single fault
+// object are accepted too.
+func parseChaosFaults(body []byte) ([]models.ChaosAPIFault, error) {
+	var faults []models.ChaosAPIFault
+	if err := json.Unmarshal(body, &faults); err == nil {
+		return faults, nil
+	}
+
+	var wrapped struct {
+		Faults *[]models.ChaosAPIFault `json:"faults"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil && wrapped.Faults != nil {
+		return *wrapped.Faults, nil
+	}
+
+	var single models.ChaosAPIFault
+	if err := json.Unmarshal(body, &single); err == nil && single != (models.ChaosAPIFault{}) {
+		return []models.ChaosAPIFault{single}, nil
+	}
+	return nil, unexpectedPayload("faults", body)
+}
+
+// parseChaosEffects decodes a /_localstack/chaos/effects body. LocalStack
+// reports one global effect as an object; an array or a wrapped
+// {"effects": [...]} are accepted too. An object with no latency or
+// probability set, or an empty one, means no effect is active.
+func parseChaosEffects(body []byte) ([]models.ChaosAPIEffect, error) {
+	var effects []models.ChaosAPIEffect
+	if err := json.Unmarshal(body, &effects); err == nil {
+		return effects, nil
+	}
+
+	var wrapped struct {
+		Effects *[]models.ChaosAPIEffect `json:"effects"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil && wrapped.Effects != nil {
+		return *wrapped.Effects, nil
+	}
+
+	// A single effect always carries a latency; an empty object means none
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil {
+		if _, ok := fields["latency"]; ok || len(fields) == 0 {
+			var single models.ChaosAPIEffect
+			if err := json.Unmarshal(body, &single); err == nil {
+				if single.Latency > 0 || single.Probability > 0 {
+					return []models.ChaosAPIEffect{single}, nil
+				}
+				return nil, nil
+			}
+		}
+	}
+	return nil, unexpectedPayload("effects", body)
+}
+
+// unexpectedPayload describes a body none of the known shapes matched,
+// quoting its start on one line
+func unexpectedPayload(kind string, body []byte) error {
+	excerpt := strings.Join(strings.Fields(string(body)), " ")
+	if len(excerpt) > maxPayloadExcerpt {
+		excerpt = excerpt[:maxPayloadExcerpt] + "…"
+	}
+	return fmt.Errorf("%s returned %s", kind, excerpt)
+}
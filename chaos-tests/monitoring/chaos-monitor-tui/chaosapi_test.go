@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestParseChaosEffects(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []models.ChaosAPIEffect
+		wantErr string
+	}{
+		{"array", `[{"latency":500,"jitter":50},{"latency":100,"service":"s3"}]`,
+			[]models.ChaosAPIEffect{{Latency: 500, Jitter: 50}, {Latency: 100, Service: "s3"}}, ""},
+		{"empty array", `[]`, []models.ChaosAPIEffect{}, ""},
+		{"wrapped", `{"effects":[{"latency":250,"region":"us-east-1"}]}`,
+			[]models.ChaosAPIEffect{{Latency: 250, Region: "us-east-1"}}, ""},
+		{"single object", `{"latency":1000,"probability":0.1}`,
+			[]models.ChaosAPIEffect{{Latency: 1000, Probability: 0.1}}, ""},
+		{"object with nothing set", `{"latency":0}`, nil, ""},
+		{"empty object", `{}`, nil, ""},
+		{"unknown object", `{"status":"error","message":"chaos plugin not loaded"}`, nil,
+			`effects returned {"status":"error","message":"chaos plugin not loaded"}`},
+		{"html", "<html>\n  <body>Bad Gateway</body>\n</html>", nil, "effects returned <html> <body>Bad Gateway</body> </html>"},
+		{"number", `42`, nil, "effects returned 42"},
+		{"long garbage", strings.Repeat("x", 100), nil, "effects returned " + strings.Repeat("x", maxPayloadExcerpt) + "…"},
+	}
+	for _, tt := range tests {
+		got, err := parseChaosEffects([]byte(tt.body))
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(got) != len(tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: effect %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseChaosFaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    int
+		wantErr bool
+	}{
+		{"array", `[{"service":"s3","region":"us-east-1"},{"service":"sqs"}]`, 2, false},
+		{"empty array", `[]`, 0, false},
+		{"wrapped", `{"faults":[{"service":"s3"}]}`, 1, false},
+		{"single object", `{"service":"dynamodb","probability":0.5}`, 1, false},
+		{"unknown object", `{"status":"error"}`, 0, true},
+		{"garbage", `Internal Server Error`, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseChaosFaults([]byte(tt.body))
+		if (err != nil) != tt.wantErr || len(got) != tt.want {
+			t.Errorf("%s: got %+v, %v", tt.name, got, err)
+		}
+	}
+}
+
+func TestUpdateChaosAPIStatusKeepsLastGood(t *testing.T) {
+	var mu sync.Mutex
+	effects := `[{"latency":500}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/_localstack/chaos/faults":
+			w.Write([]byte(`[{"service":"s3","region":"us-east-1"}]`))
+		case "/_localstack/chaos/effects":
+			w.Write([]byte(effects))
+		}
+	}))
+	defer server.Close()
+
+	m := newTestModel(t)
+	m.cfg.targets = []target{{name: "local", baseURL: server.URL}}
+	m.updateChaosAPIStatus()
+	if len(m.state.ChaosAPIFaults) != 1 || len(m.state.ChaosAPIEffects) != 1 || m.state.ChaosAPIWarning != "" {
+		t.Fatalf("faults %+v, effects %+v, warning %q", m.state.ChaosAPIFaults, m.state.ChaosAPIEffects, m.state.ChaosAPIWarning)
+	}
+
+	mu.Lock()
+	effects = `not json`
+	mu.Unlock()
+	m.updateChaosAPIStatus()
+	if len(m.state.ChaosAPIEffects) != 1 || m.state.ChaosAPIEffects[0].Latency != 500 {
+		t.Errorf("effects after a bad body = %+v", m.state.ChaosAPIEffects)
+	}
+	if m.state.ChaosAPIWarning != "effects returned not json" {
+		t.Errorf("warning = %q", m.state.ChaosAPIWarning)
+	}
+
+	mu.Lock()
+	effects = `{}`
+	mu.Unlock()
+	m.updateChaosAPIStatus()
+	if len(m.state.ChaosAPIEffects) != 0 || m.state.ChaosAPIWarning != "" {
+		t.Errorf("after clearing: effects %+v, warning %q", m.state.ChaosAPIEffects, m.state.ChaosAPIWarning)
+	}
+}
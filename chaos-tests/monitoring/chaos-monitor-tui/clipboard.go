@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/atotto/clipboard"
+)
+
+// noticeDuration is how long a confirmation stays in the title bar
+const noticeDuration = 3 * time.Second
+
+// copyToClipboard copies the dashboard as plain text, or the state as JSON,
+// and reports the outcome in the title bar. Headless servers usually have
+// no clipboard, which is reported rather than treated as fatal.
+func (m *model) copyToClipboard(asJSON bool) {
+	what := "dashboard"
+	text := ansiPattern.ReplaceAllString(m.View(), "")
+	if asJSON {
+		what = "JSON snapshot"
+		body, err := json.MarshalIndent(m.state, "", "  ")
+		if err != nil {
+			m.setNotice("Copy failed: " + err.Error())
+			return
+		}
+		text = string(body)
+	}
+
+	if clipboard.Unsupported {
+		m.setNotice("No clipboard available")
+		return
+	}
+	if err := clipboard.WriteAll(text); err != nil {
+		m.setNotice("No clipboard available: " + err.Error())
+		return
+	}
+	m.setNotice("✓ Copied " + what + " to clipboard")
+}
+
+// setNotice shows a short message in the title bar
+func (m *model) setNotice(notice string) {
+	m.notice = notice
+	m.noticeUntil = time.Now().Add(noticeDuration)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseLatencyBuckets(t *testing.T) {
+	buckets, err := parseDurations(" 10ms, 100ms,1s ")
+	if err != nil || !slices.Equal(buckets, []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second}) {
+		t.Errorf("parseDurations = %v, %v", buckets, err)
+	}
+	for _, spec := range []string{"", "100ms,10ms", "10ms,10ms", "10ms,fast"} {
+		if _, err := parseDurations(spec); err == nil {
+			t.Errorf("parseDurations(%q) accepted", spec)
+		}
+	}
+}
+
+func TestSMTPPasswordFromEnv(t *testing.T) {
+	t.Setenv("SMTP_PASSWORD", "hunter2")
+	cfg, err := parseArgs(t)
+	if err != nil || cfg.email.Password != "hunter2" {
+		t.Errorf("without -smtp-password: password = %q, %v", cfg.email.Password, err)
+	}
+	if def := flag.CommandLine.Lookup("smtp-password").DefValue; def != "" {
+		t.Errorf("-smtp-password default = %q, want it kept out of -help", def)
+	}
+	cfg, err = parseArgs(t, "-smtp-password", "flag")
+	if err != nil || cfg.email.Password != "flag" {
+		t.Errorf("with -smtp-password: password = %q, %v", cfg.email.Password, err)
+	}
+}
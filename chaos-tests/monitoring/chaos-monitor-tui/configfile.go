@@ -0,0 +1,329 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is the layout of a -config file, in YAML or JSON. Each setting
+// means the same as the flag noted beside it, and a flag given on the
+// command line overrides the file. Durations are strings like "10s". The
+// run modes (-once, -json, -validate, -doctor, -version) are left to flags.
+type fileConfig struct {
+	Targets  []string `yaml:"targets"`  // -target, 'name=http://host:4566[,nginx URL]'
+	Services []string `yaml:"services"` // -services
+	Regions  []string `yaml:"regions"`  // -regions
+	Theme    string   `yaml:"theme"`    // -theme
+	Control  *bool    `yaml:"control"`  // -control
+	Proxy    string   `yaml:"proxy"`    // -proxy
+
+	Endpoints  endpointsFileConfig  `yaml:"endpoints"`
+	Intervals  intervalsFileConfig  `yaml:"intervals"`
+	Thresholds thresholdsFileConfig `yaml:"thresholds"`
+	Alerts     alertsFileConfig     `yaml:"alerts"`
+	Tests      testsFileConfig      `yaml:"tests"`
+	Outputs    outputsFileConfig    `yaml:"outputs"`
+	TLS        tlsFileConfig        `yaml:"tls"`
+	AWS        awsFileConfig        `yaml:"aws"`
+}
+
+// endpointsFileConfig holds the endpoint list and per-endpoint settings,
+// keyed by endpoint name
+type endpointsFileConfig struct {
+	HTTP            []string            `yaml:"http"`              // -endpoint, 'Name=http://host/path'
+	File            string              `yaml:"file"`              // -endpoints
+	GRPC            map[string]string   `yaml:"grpc"`              // -grpc-endpoint
+	ExpectBody      map[string]string   `yaml:"expect_body"`       // -expect-body
+	ExpectBodyRegex map[string]string   `yaml:"expect_body_regex"` // -expect-body-regex
+	ExpectStatus    map[string][]int    `yaml:"expect_status"`     // -expect-status
+	RegionContent   map[string]string   `yaml:"region_content"`    // -region-content
+	Timeouts        map[string]string   `yaml:"timeouts"`          // -timeout
+	Families        map[string]string   `yaml:"families"`          // -family
+	Timing          map[string]string   `yaml:"timing"`            // -timing
+	Groups          map[string][]string `yaml:"groups"`            // -group
+	Headers         map[string][]string `yaml:"headers"`           // -header, 'Header: value'
+	Retries         *int                `yaml:"retries"`           // -retries
+	NetworkProbes   *bool               `yaml:"network_probes"`    // -network-probes
+}
+
+type intervalsFileConfig struct {
+	StartupTimeout string   `yaml:"startup_timeout"` // -startup-timeout
+	Highlight      string   `yaml:"highlight"`       // -highlight
+	Jitter         *float64 `yaml:"jitter"`          // -jitter
+	EMAAlpha       *float64 `yaml:"ema_alpha"`       // -ema-alpha
+	HistorySize    *int     `yaml:"history_size"`    // -history-size
+	LatencyBuckets []string `yaml:"latency_buckets"` // -latency-buckets
+}
+
+type thresholdsFileConfig struct {
+	AvailHigh    *float64           `yaml:"avail_high"`    // -avail-high
+	AvailMed     *float64           `yaml:"avail_med"`     // -avail-med
+	LatencyWarn  string             `yaml:"latency_warn"`  // -latency-warn
+	LatencyError string             `yaml:"latency_error"` // -latency-error
+	SlowWarn     string             `yaml:"slow_warn"`     // -slow-warn
+	SlowError    string             `yaml:"slow_error"`    // -slow-error
+	SLOTarget    *float64           `yaml:"slo_target"`    // -slo-target
+	SLOWindow    string             `yaml:"slo_window"`    // -slo-window
+	Weights      map[string]float64 `yaml:"weights"`       // -weight, per endpoint or service
+}
+
+type alertsFileConfig struct {
+	Notify            *bool    `yaml:"notify"`             // -notify
+	NotifyAfter       *int     `yaml:"notify_after"`       // -notify-after
+	NotifyDesktop     *bool    `yaml:"notify_desktop"`     // -notify-desktop
+	BudgetAlert       *float64 `yaml:"budget_alert"`       // -budget-alert
+	QuietHours        string   `yaml:"quiet_hours"`        // -quiet-hours
+	SlackWebhook      string   `yaml:"slack_webhook"`      // -slack-webhook
+	PagerDutyKey      string   `yaml:"pagerduty_key"`      // -pagerduty-key
+	AnnotationWebhook string   `yaml:"annotation_webhook"` // -annotation-webhook
+	AnnotationFormat  string   `yaml:"annotation_format"`  // -annotation-format
+	SMTPHost          string   `yaml:"smtp_host"`          // -smtp-host
+	SMTPFrom          string   `yaml:"smtp_from"`          // -smtp-from
+	SMTPTo            []string `yaml:"smtp_to"`            // -smtp-to
+	SMTPUser          string   `yaml:"smtp_user"`          // -smtp-user
+	SMTPPassword      string   `yaml:"smtp_password"`      // -smtp-password
+}
+
+type testsFileConfig struct {
+	StatusURL      string            `yaml:"status_url"`      // -status-url
+	StatusStdin    *bool             `yaml:"status_stdin"`    // -status-stdin
+	StaleAfter     string            `yaml:"stale_after"`     // -stale-after
+	Prune          *bool             `yaml:"prune"`           // -prune
+	TestTypes      map[string]string `yaml:"test_types"`      // -test-type
+	Classify       []string          `yaml:"classify"`        // -classify, 'keyword=failure_type', in order
+	CascadeWindow  string            `yaml:"cascade_window"`  // -cascade-window
+	CascadeMin     *int              `yaml:"cascade_min"`     // -cascade-min
+	ThrottleChecks *int              `yaml:"throttle_checks"` // -throttle-checks
+}
+
+type outputsFileConfig struct {
+	OTLPEndpoint string   `yaml:"otlp_endpoint"` // -otlp-endpoint
+	APIAddr      string   `yaml:"api_addr"`      // -api-addr
+	WSAddr       string   `yaml:"ws_addr"`       // -ws-addr
+	CSVOut       string   `yaml:"csv_out"`       // -csv-out
+	Report       string   `yaml:"report"`        // -report
+	Baseline     string   `yaml:"baseline"`      // -baseline
+	Record       string   `yaml:"record"`        // -record
+	Replay       string   `yaml:"replay"`        // -replay
+	ReplaySpeed  *float64 `yaml:"replay_speed"`  // -replay-speed
+	LogFile      string   `yaml:"log_file"`      // -log-file
+	LogFormat    string   `yaml:"log_format"`    // -log-format
+	LogLevel     string   `yaml:"log_level"`     // -log-level
+}
+
+type tlsFileConfig struct {
+	InsecureSkipVerify *bool  `yaml:"insecure_skip_verify"` // -insecure-skip-verify
+	CACert             string `yaml:"ca_cert"`              // -ca-cert
+}
+
+type awsFileConfig struct {
+	Profile string `yaml:"profile"` // -aws-profile
+	Env     *bool  `yaml:"env"`     // -aws-env
+}
+
+// fileSetting is one flag value from a config file, with the key it came
+// from for error messages
+type fileSetting struct {
+	key, flag, value string
+}
+
+// fileSettings collects the flag values a config file sets
+type fileSettings []fileSetting
+
+func (s *fileSettings) str(key, flagName, value string) {
+	if value != "" {
+		*s = append(*s, fileSetting{key, flagName, value})
+	}
+}
+
+func (s *fileSettings) boolean(key, flagName string, value *bool) {
+	if value != nil {
+		s.str(key, flagName, strconv.FormatBool(*value))
+	}
+}
+
+func (s *fileSettings) integer(key, flagName string, value *int) {
+	if value != nil {
+		s.str(key, flagName, strconv.Itoa(*value))
+	}
+}
+
+func (s *fileSettings) float(key, flagName string, value *float64) {
+	if value != nil {
+		s.str(key, flagName, strconv.FormatFloat(*value, 'g', -1, 64))
+	}
+}
+
+// list sets a repeatable flag once per value
+func (s *fileSettings) list(key, flagName string, values []string) {
+	for _, value := range values {
+		*s = append(*s, fileSetting{key, flagName, value})
+	}
+}
+
+// pairs sets a repeatable 'name=value' flag once per entry, by name
+func (s *fileSettings) pairs(key, flagName string, values map[string]string) {
+	for _, name := range sortedKeys(values) {
+		*s = append(*s, fileSetting{key + "." + name, flagName, name + "=" + values[name]})
+	}
+}
+
+// settings lists the flag values the file sets. Lists keep the file's
+// order, which matters for -classify and the endpoint order.
+func (c *fileConfig) settings() fileSettings {
+	var s fileSettings
+	s.list("targets", "target", c.Targets)
+	s.str("services", "services", strings.Join(c.Services, ","))
+	s.str("regions", "regions", strings.Join(c.Regions, ","))
+	s.str("theme", "theme", c.Theme)
+	s.boolean("control", "control", c.Control)
+	s.str("proxy", "proxy", c.Proxy)
+
+	e := c.Endpoints
+	s.list("endpoints.http", "endpoint", e.HTTP)
+	s.str("endpoints.file", "endpoints", e.File)
+	s.pairs("endpoints.grpc", "grpc-endpoint", e.GRPC)
+	s.pairs("endpoints.expect_body", "expect-body", e.ExpectBody)
+	s.pairs("endpoints.expect_body_regex", "expect-body-regex", e.ExpectBodyRegex)
+	for _, name := range sortedKeys(e.ExpectStatus) {
+		s.str("endpoints.expect_status."+name, "expect-status", name+"="+formatStatusCodes(e.ExpectStatus[name]))
+	}
+	s.pairs("endpoints.region_content", "region-content", e.RegionContent)
+	s.pairs("endpoints.timeouts", "timeout", e.Timeouts)
+	s.pairs("endpoints.families", "family", e.Families)
+	s.pairs("endpoints.timing", "timing", e.Timing)
+	for _, name := range sortedKeys(e.Groups) {
+		s.str("endpoints.groups."+name, "group", name+"="+strings.Join(e.Groups[name], ","))
+	}
+	for _, name := range sortedKeys(e.Headers) {
+		for _, header := range e.Headers[name] {
+			s.str("endpoints.headers."+name, "header", name+"="+header)
+		}
+	}
+	s.integer("endpoints.retries", "retries", e.Retries)
+	s.boolean("endpoints.network_probes", "network-probes", e.NetworkProbes)
+
+	i := c.Intervals
+	s.str("intervals.startup_timeout", "startup-timeout", i.StartupTimeout)
+	s.str("intervals.highlight", "highlight", i.Highlight)
+	s.float("intervals.jitter", "jitter", i.Jitter)
+	s.float("intervals.ema_alpha", "ema-alpha", i.EMAAlpha)
+	s.integer("intervals.history_size", "history-size", i.HistorySize)
+	s.str("intervals.latency_buckets", "latency-buckets", strings.Join(i.LatencyBuckets, ","))
+
+	t := c.Thresholds
+	s.float("thresholds.avail_high", "avail-high", t.AvailHigh)
+	s.float("thresholds.avail_med", "avail-med", t.AvailMed)
+	s.str("thresholds.latency_warn", "latency-warn", t.LatencyWarn)
+	s.str("thresholds.latency_error", "latency-error", t.LatencyError)
+	s.str("thresholds.slow_warn", "slow-warn", t.SlowWarn)
+	s.str("thresholds.slow_error", "slow-error", t.SlowError)
+	s.float("thresholds.slo_target", "slo-target", t.SLOTarget)
+	s.str("thresholds.slo_window", "slo-window", t.SLOWindow)
+	for _, name := range sortedKeys(t.Weights) {
+		s.str("thresholds.weights."+name, "weight", name+"="+strconv.FormatFloat(t.Weights[name], 'g', -1, 64))
+	}
+
+	a := c.Alerts
+	s.boolean("alerts.notify", "notify", a.Notify)
+	s.integer("alerts.notify_after", "notify-after", a.NotifyAfter)
+	s.boolean("alerts.notify_desktop", "notify-desktop", a.NotifyDesktop)
+	s.float("alerts.budget_alert", "budget-alert", a.BudgetAlert)
+	s.str("alerts.quiet_hours", "quiet-hours", a.QuietHours)
+	s.str("alerts.slack_webhook", "slack-webhook", a.SlackWebhook)
+	s.str("alerts.pagerduty_key", "pagerduty-key", a.PagerDutyKey)
+	s.str("alerts.annotation_webhook", "annotation-webhook", a.AnnotationWebhook)
+	s.str("alerts.annotation_format", "annotation-format", a.AnnotationFormat)
+	s.str("alerts.smtp_host", "smtp-host", a.SMTPHost)
+	s.str("alerts.smtp_from", "smtp-from", a.SMTPFrom)
+	s.str("alerts.smtp_to", "smtp-to", strings.Join(a.SMTPTo, ","))
+	s.str("alerts.smtp_user", "smtp-user", a.SMTPUser)
+	s.str("alerts.smtp_password", "smtp-password", a.SMTPPassword)
+
+	ts := c.Tests
+	s.str("tests.status_url", "status-url", ts.StatusURL)
+	s.boolean("tests.status_stdin", "status-stdin", ts.StatusStdin)
+	s.str("tests.stale_after", "stale-after", ts.StaleAfter)
+	s.boolean("tests.prune", "prune", ts.Prune)
+	s.pairs("tests.test_types", "test-type", ts.TestTypes)
+	s.list("tests.classify", "classify", ts.Classify)
+	s.str("tests.cascade_window", "cascade-window", ts.CascadeWindow)
+	s.integer("tests.cascade_min", "cascade-min", ts.CascadeMin)
+	s.integer("tests.throttle_checks", "throttle-checks", ts.ThrottleChecks)
+
+	o := c.Outputs
+	s.str("outputs.otlp_endpoint", "otlp-endpoint", o.OTLPEndpoint)
+	s.str("outputs.api_addr", "api-addr", o.APIAddr)
+	s.str("outputs.ws_addr", "ws-addr", o.WSAddr)
+	s.str("outputs.csv_out", "csv-out", o.CSVOut)
+	s.str("outputs.report", "report", o.Report)
+	s.str("outputs.baseline", "baseline", o.Baseline)
+	s.str("outputs.record", "record", o.Record)
+	s.str("outputs.replay", "replay", o.Replay)
+	s.float("outputs.replay_speed", "replay-speed", o.ReplaySpeed)
+	s.str("outputs.log_file", "log-file", o.LogFile)
+	s.str("outputs.log_format", "log-format", o.LogFormat)
+	s.str("outputs.log_level", "log-level", o.LogLevel)
+
+	s.boolean("tls.insecure_skip_verify", "insecure-skip-verify", c.TLS.InsecureSkipVerify)
+	s.str("tls.ca_cert", "ca-cert", c.TLS.CACert)
+
+	s.str("aws.profile", "aws-profile", c.AWS.Profile)
+	s.boolean("aws.env", "aws-env", c.AWS.Env)
+	return s
+}
+
+// loadFileConfig reads a YAML or JSON config file. Unknown keys are
+// rejected so a typo doesn't silently fall back to a default.
+func loadFileConfig(r io.Reader) (*fileConfig, error) {
+	var c fileConfig
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		// Report every bad key on one line, e.g. "line 3: field foo not found in type main.alertsFileConfig"
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			return nil, errors.New(strings.Join(typeErr.Errors, "; "))
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// applyConfigFile sets the flags from a -config file, skipping any given on
+// the command line so those take precedence. It runs after flag.Parse and
+// before the flag values are validated, so a file value is checked like
+// the flag it stands for.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("-config: %w", err)
+	}
+	defer f.Close()
+
+	c, err := loadFileConfig(f)
+	if err != nil {
+		return fmt.Errorf("-config %s: %v", path, err)
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	for _, setting := range c.settings() {
+		if explicit[setting.flag] {
+			continue
+		}
+		if err := fs.Set(setting.flag, setting.value); err != nil {
+			return fmt.Errorf("-config %s: %s: invalid value %q: %v", path, setting.key, setting.value, err)
+		}
+	}
+	return nil
+}
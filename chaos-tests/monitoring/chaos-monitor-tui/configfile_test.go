@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/ui"
+)
+
+// parseArgs runs parseFlags on a fresh flag set as if given args on the
+// command line
+func parseArgs(t *testing.T, args ...string) (config, error) {
+	t.Helper()
+	savedArgs, savedFlags := os.Args, flag.CommandLine
+	os.Args = append([]string{"chaos-monitor-tui"}, args...)
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	t.Cleanup(func() {
+		os.Args, flag.CommandLine = savedArgs, savedFlags
+		ui.SetTheme("default")
+		ui.SetThresholds(ui.DefaultThresholds)
+	})
+	return parseFlags()
+}
+
+// writeConfig writes a -config file and returns its path
+func writeConfig(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const sampleYAML = `
+regions: [us-east-1, eu-west-1]
+endpoints:
+  http:
+    - Home=http://localhost:8080/
+  expect_status:
+    Home: [200, 301]
+  timing:
+    Home: body
+  timeouts:
+    Home: 2s
+  retries: 2
+intervals:
+  ema_alpha: 0.5
+alerts:
+  notify_after: 5
+tests:
+  stale_after: 10m
+  cascade_min: 4
+`
+
+const sampleJSON = `{
+  "regions": ["us-east-1", "eu-west-1"],
+  "endpoints": {
+    "http": ["Home=http://localhost:8080/"],
+    "expect_status": {"Home": [200, 301]},
+    "timing": {"Home": "body"},
+    "timeouts": {"Home": "2s"},
+    "retries": 2
+  },
+  "intervals": {"ema_alpha": 0.5},
+  "alerts": {"notify_after": 5},
+  "tests": {"stale_after": "10m", "cascade_min": 4}
+}`
+
+func TestConfigFile(t *testing.T) {
+	yamlCfg, err := parseArgs(t, "-config", writeConfig(t, "monitor.yaml", sampleYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(yamlCfg.regions, []string{"us-east-1", "eu-west-1"}) {
+		t.Errorf("regions = %v", yamlCfg.regions)
+	}
+	if len(yamlCfg.userEndpoints) != 1 || yamlCfg.userEndpoints[0].name != "Home" {
+		t.Errorf("endpoints = %+v", yamlCfg.userEndpoints)
+	}
+	if !reflect.DeepEqual(yamlCfg.expectStatus["Home"], []int{200, 301}) {
+		t.Errorf("expect status = %v", yamlCfg.expectStatus)
+	}
+	if yamlCfg.timing["Home"] != timingBody || yamlCfg.timeouts["Home"] != 2*time.Second {
+		t.Errorf("timing = %v, timeouts = %v", yamlCfg.timing, yamlCfg.timeouts)
+	}
+	if yamlCfg.retries != 2 || yamlCfg.emaAlpha != 0.5 || yamlCfg.notifyAfter != 5 {
+		t.Errorf("retries = %d, EMA alpha = %v, notify after = %d", yamlCfg.retries, yamlCfg.emaAlpha, yamlCfg.notifyAfter)
+	}
+	if yamlCfg.staleAfter != 10*time.Minute || yamlCfg.cascade.MinAffected != 4 {
+		t.Errorf("stale after = %v, cascade min = %d", yamlCfg.staleAfter, yamlCfg.cascade.MinAffected)
+	}
+
+	// The same settings in JSON load identically
+	jsonCfg, err := parseArgs(t, "-config", writeConfig(t, "monitor.json", sampleJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(jsonCfg, yamlCfg) {
+		t.Errorf("JSON config = %+v\nYAML config = %+v", jsonCfg, yamlCfg)
+	}
+
+	// Flags override the file, whichever side of -config they're on
+	cfg, err := parseArgs(t, "-retries", "0", "-config", writeConfig(t, "monitor.yaml", sampleYAML), "-regions", "ap-south-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.retries != 0 || !reflect.DeepEqual(cfg.regions, []string{"ap-south-1"}) {
+		t.Errorf("flags overridden by the file: retries = %d, regions = %v", cfg.retries, cfg.regions)
+	}
+	if cfg.emaAlpha != 0.5 {
+		t.Errorf("EMA alpha = %v, want 0.5 from the file", cfg.emaAlpha)
+	}
+}
+
+func TestConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		want []string
+	}{
+		{"regions: [us-east-1]\nalerts:\n  notify_afterr: 5\n", []string{"line 3", "notify_afterr"}},
+		{"intervals:\n  ema_alpha: 0\n", []string{"-ema-alpha must be in (0, 1]"}},
+		{"endpoints:\n  retries: many\n", []string{"line 2", "many"}},
+		{"tests:\n  stale_after: soon\n", []string{"tests.stale_after", `invalid value "soon"`}},
+		{"endpoints:\n  timing:\n    Home: always\n", []string{"invalid -timing for Home"}},
+	}
+	for _, tt := range tests {
+		_, err := parseArgs(t, "-config", writeConfig(t, "monitor.yaml", tt.data))
+		if err == nil {
+			t.Errorf("%q: no error", tt.data)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%q: error %q doesn't mention %q", tt.data, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chaos-monitor-tui/models"
+	"chaos-monitor-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// controlResultMsg reports the outcome of a Chaos API control action
+type controlResultMsg struct {
+	message string
+	err     error
+}
+
+// faultForm holds the in-progress input for a new fault
+type faultForm struct {
+	fields []ui.FormField
+	focus  int
+}
+
+func newFaultForm() *faultForm {
+	return &faultForm{
+		fields: []ui.FormField{
+			{Label: "Service", Value: "s3"},
+			{Label: "Region", Value: "us-east-1"},
+			{Label: "Probability", Value: "1.0"},
+		},
+	}
+}
+
+// toFault validates the form input and builds the fault to add
+func (f *faultForm) toFault() (models.ChaosAPIFault, error) {
+	var fault models.ChaosAPIFault
+
+	fault.Service = strings.TrimSpace(f.fields[0].Value)
+	fault.Region = strings.TrimSpace(f.fields[1].Value)
+	if fault.Service == "" || fault.Region == "" {
+		return fault, fmt.Errorf("service and region are required")
+	}
+
+	probability, err := strconv.ParseFloat(strings.TrimSpace(f.fields[2].Value), 64)
+	if err != nil || probability < 0 || probability > 1 {
+		return fault, fmt.Errorf("probability must be a number between 0 and 1")
+	}
+	fault.Probability = probability
+	fault.Error.StatusCode = http.StatusServiceUnavailable
+	fault.Error.Code = "ServiceUnavailable"
+
+	return fault, nil
+}
+
+// updateForm handles key presses while the add-fault form is open
+func (m model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := m.form
+	field := &form.fields[form.focus]
+
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.form = nil
+	case tea.KeyTab, tea.KeyDown:
+		form.focus = (form.focus + 1) % len(form.fields)
+	case tea.KeyShiftTab, tea.KeyUp:
+		form.focus = (form.focus + len(form.fields) - 1) % len(form.fields)
+	case tea.KeyBackspace:
+		if len(field.Value) > 0 {
+			runes := []rune(field.Value)
+			field.Value = string(runes[:len(runes)-1])
+		}
+	case tea.KeyEnter:
+		if form.focus < len(form.fields)-1 {
+			form.focus++
+			break
+		}
+		fault, err := form.toFault()
+		if err != nil {
+			m.controlMessage = "Invalid fault: " + err.Error()
+			m.controlError = true
+			break
+		}
+		m.form = nil
+		return m, addFaultCmd(m.ctx, m.client, m.currentTarget().baseURL, fault)
+	case tea.KeyRunes, tea.KeySpace:
+		field.Value += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// controlPanel builds the view of the fault injection controls
+func (m model) controlPanel() ui.ControlPanel {
+	panel := ui.ControlPanel{
+		Enabled: m.cfg.control,
+		Message: m.controlMessage,
+		IsError: m.controlError,
+	}
+	if m.form != nil {
+		panel.Form = m.form.fields
+		panel.Focus = m.form.focus
+	}
+	return panel
+}
+
+// addFaultCmd adds a fault to those configured in the Chaos API. PATCH
+// appends to the list, where POST would replace every existing fault.
+func addFaultCmd(ctx context.Context, client *http.Client, baseURL string, fault models.ChaosAPIFault) tea.Cmd {
+	return func() tea.Msg {
+		if err := sendFaults(ctx, client, http.MethodPatch, baseURL, []models.ChaosAPIFault{fault}); err != nil {
+			return controlResultMsg{err: err}
+		}
+		return controlResultMsg{
+			message: fmt.Sprintf("Added fault: %s (%s) at %.0f%%",
+				fault.Service, fault.Region, fault.Probability*100),
+		}
+	}
+}
+
+// clearFaultsCmd removes all faults by posting an empty fault list
+func clearFaultsCmd(ctx context.Context, client *http.Client, baseURL string) tea.Cmd {
+	return func() tea.Msg {
+		if err := sendFaults(ctx, client, http.MethodPost, baseURL, []models.ChaosAPIFault{}); err != nil {
+			return controlResultMsg{err: err}
+		}
+		return controlResultMsg{message: "Cleared all faults"}
+	}
+}
+
+// sendFaults sends faults to the Chaos API: POST replaces the configured
+// faults with them, PATCH adds them
+func sendFaults(ctx context.Context, client *http.Client, method, baseURL string, faults []models.ChaosAPIFault) error {
+	body, err := json.Marshal(faults)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+"/_localstack/chaos/faults", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("chaos API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
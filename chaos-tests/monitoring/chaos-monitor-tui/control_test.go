@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"chaos-monitor-tui/models"
+)
+
+// fakeChaosAPI keeps a fault list the way LocalStack does: POST replaces
+// it, PATCH appends to it
+type fakeChaosAPI struct {
+	mu     sync.Mutex
+	faults []models.ChaosAPIFault
+}
+
+func (f *fakeChaosAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var faults []models.ChaosAPIFault
+	if r.Method != http.MethodGet {
+		if err := json.NewDecoder(r.Body).Decode(&faults); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	switch r.Method {
+	case http.MethodPost:
+		f.faults = faults
+	case http.MethodPatch:
+		f.faults = append(f.faults, faults...)
+	}
+	json.NewEncoder(w).Encode(f.faults)
+}
+
+func TestAddFaultKeepsExistingFaults(t *testing.T) {
+	api := &fakeChaosAPI{faults: []models.ChaosAPIFault{{Service: "dynamodb", Region: "us-east-1", Probability: 1}}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	msg := addFaultCmd(context.Background(), server.Client(), server.URL, models.ChaosAPIFault{Service: "s3", Region: "us-west-2", Probability: 0.5})()
+	result := msg.(controlResultMsg)
+	if result.err != nil {
+		t.Fatal(result.err)
+	}
+	if result.message != "Added fault: s3 (us-west-2) at 50%" {
+		t.Errorf("message = %q", result.message)
+	}
+	if len(api.faults) != 2 || api.faults[0].Service != "dynamodb" || api.faults[1].Service != "s3" {
+		t.Errorf("faults after adding = %+v", api.faults)
+	}
+
+	if result := clearFaultsCmd(context.Background(), server.Client(), server.URL)().(controlResultMsg); result.err != nil {
+		t.Fatal(result.err)
+	}
+	if len(api.faults) != 0 {
+		t.Errorf("faults after clearing = %+v", api.faults)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+	"chaos-monitor-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ansiPattern matches the SGR escape sequences lipgloss emits
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// selectableRow is an endpoint or service that can be selected
+type selectableRow struct {
+	key  string // "endpoint|<name>" or "service|<label>"
+	name string // Text shown on the dashboard, used for mouse hit-testing
+}
+
+// selectableRows lists the rows shown, in dashboard order
+func (m model) selectableRows() []selectableRow {
+	var rows []selectableRow
+	for _, endpoint := range ui.FilterEndpoints(ui.SortEndpoints(&m.state, m.sort), m.filter) {
+		rows = append(rows, selectableRow{key: "endpoint|" + endpoint.Name, name: endpoint.Name})
+	}
+	for _, service := range ui.FilterServices(ui.SortServices(&m.state, m.sort), m.filter) {
+		rows = append(rows, selectableRow{key: "service|" + service.Label(), name: service.Name})
+	}
+	return rows
+}
+
+// recordHistory appends this tick's results to each row's history
+func (m *model) recordHistory() {
+	for _, endpoint := range m.state.NginxEndpoints {
+		detail := endpoint.Reason
+		if detail == "" && endpoint.Status != "ok" {
+			detail = endpoint.Status
+		}
+		m.addSample("endpoint|"+endpoint.Name, models.CheckSample{
+			Time:         endpoint.LastChecked,
+			Status:       endpoint.Status,
+			OK:           endpoint.Available(),
+			ResponseTime: endpoint.ResponseTime,
+			Detail:       detail,
+		})
+	}
+	for _, service := range m.state.AWSServices {
+		detail := service.Error
+		if detail == "" && service.FailureType != "ok" {
+			detail = service.FailureType
+		}
+		m.addSample("service|"+service.Label(), models.CheckSample{
+			Time:         service.LastChecked,
+			Status:       service.Status,
+			OK:           service.Status == "healthy",
+			ResponseTime: service.ResponseTime,
+			Detail:       detail,
+		})
+	}
+
+	m.faultCounts = append(m.faultCounts, models.FaultCount{
+		Time:    m.state.LastUpdate,
+		Faults:  len(m.state.ChaosAPIFaults),
+		Effects: len(m.state.ChaosAPIEffects),
+	})
+	if len(m.faultCounts) > m.cfg.historySize {
+		m.faultCounts = m.faultCounts[len(m.faultCounts)-m.cfg.historySize:]
+	}
+}
+
+func (m *model) addSample(key string, sample models.CheckSample) {
+	if sample.Time.IsZero() {
+		sample.Time = time.Now()
+	}
+	samples := append(m.history[key], sample)
+	if len(samples) > m.cfg.historySize {
+		samples = samples[len(samples)-m.cfg.historySize:]
+	}
+	m.history[key] = samples
+}
+
+// moveSelection moves the selection by delta, wrapping around
+func (m *model) moveSelection(delta int) {
+	rows := m.selectableRows()
+	if len(rows) == 0 {
+		return
+	}
+	if m.selected < 0 {
+		// The first move selects the first or last row
+		if delta > 0 {
+			m.selected = 0
+		} else {
+			m.selected = len(rows) - 1
+		}
+		return
+	}
+	m.selected = (m.selected + delta + len(rows)) % len(rows)
+}
+
+// selectedKey returns the key of the selected row, if any
+func (m model) selectedKey() string {
+	rows := m.selectableRows()
+	if m.selected < 0 || m.selected >= len(rows) {
+		return ""
+	}
+	return rows[m.selected].key
+}
+
+// selectKey selects the row with key, if it's still present
+func (m *model) selectKey(key string) {
+	if key == "" {
+		return
+	}
+	for i, row := range m.selectableRows() {
+		if row.key == key {
+			m.selected = i
+			return
+		}
+	}
+}
+
+// handleMouse selects the row under a left click and opens its detail view
+func (m model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress {
+		if m.logVisible() && !m.showDetail {
+			var cmd tea.Cmd
+			m.logView, cmd = m.logView.Update(msg)
+			return m, cmd
+		}
+		if !m.showDetail {
+			m.syncBody()
+			m.body, _ = m.body.Update(msg)
+		}
+		return m, nil
+	}
+	if m.showDetail {
+		m.showDetail = false
+		return m, nil
+	}
+
+	lines := strings.Split(ansiPattern.ReplaceAllString(m.View(), ""), "\n")
+	if msg.Y < 0 || msg.Y >= len(lines) {
+		return m, nil
+	}
+	line := lines[msg.Y]
+
+	// Prefer the longest name so "Main Site [TCP]" isn't taken for "Main Site"
+	rows := m.selectableRows()
+	best := -1
+	for i, row := range rows {
+		if strings.Contains(line, " "+row.name+" ") && (best < 0 || len(row.name) > len(rows[best].name)) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		m.selected = best
+		m.showDetail = true
+	}
+	return m, nil
+}
+
+// detailView builds the detail overlay for the selected row
+func (m model) detailView() ui.DetailView {
+	key := m.selectedKey()
+	kind, _, _ := strings.Cut(key, "|")
+	view := ui.DetailView{Kind: kind, Samples: m.history[key]}
+
+	rows := m.selectableRows()
+	if m.selected < len(rows) {
+		view.Name = rows[m.selected].name
+	}
+	for _, endpoint := range m.state.NginxEndpoints {
+		if key == "endpoint|"+endpoint.Name {
+			view.Target = endpoint.URL
+			if endpoint.Address != "" {
+				view.Target += " → " + endpoint.Address + " (" + endpoint.Family + ")"
+			}
+			if stats, ok := m.state.Stats.NginxStats[endpoint.Name]; ok {
+				view.LatencyBuckets = stats.LatencyBuckets
+				view.Histogram = stats.Histogram
+			}
+		}
+	}
+	for _, service := range m.state.AWSServices {
+		if key == "service|"+service.Label() {
+			view.Name = service.Label()
+			view.Target = service.Region
+		}
+	}
+	return view
+}
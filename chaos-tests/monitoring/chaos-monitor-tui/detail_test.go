@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// TestHistoryBounded checks every per-row and per-refresh buffer stays
+// within -history-size however long the monitor runs
+func TestHistoryBounded(t *testing.T) {
+	const size = 5
+	m := newTestModel(t)
+	m.cfg.historySize = size
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4*size; i++ {
+		m.state.LastUpdate = start.Add(time.Duration(i) * updateInterval)
+		m.state.NginxEndpoints = []models.EndpointStatus{{Name: "Main Site", Status: "ok", LastChecked: m.state.LastUpdate}}
+		m.state.AWSServices = []models.ServiceStatus{{Name: "s3", Region: "us-east-1", Status: "healthy", FailureType: "ok", LastChecked: m.state.LastUpdate}}
+		m.updateStatistics()
+		m.recordHistory()
+	}
+
+	check := func(name string, n int) {
+		t.Helper()
+		if n != size {
+			t.Errorf("%s keeps %d entries, want %d", name, n, size)
+		}
+	}
+	check("endpoint heatmap", len(m.state.Stats.NginxStats["Main Site"].Recent))
+	check("service heatmap", len(m.state.Stats.ServiceStats["s3"].Recent))
+	check("region heatmap", len(m.state.Stats.RegionStats["s3"]["us-east-1"].Recent))
+	check("fault trend", len(m.faultCounts))
+	for key, samples := range m.history {
+		check(key+" samples", len(samples))
+	}
+	if len(m.history) != 2 {
+		t.Errorf("history rows = %d, want 2", len(m.history))
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/monitor"
+)
+
+// awsCLIImage is the image service probes run the AWS CLI in
+const awsCLIImage = "amazon/aws-cli"
+
+// doctorTimeout bounds each docker command the doctor runs
+const doctorTimeout = 10 * time.Second
+
+// doctorResult is the outcome of one prerequisite check
+type doctorResult struct {
+	name     string
+	err      error  // Nil when the check passed
+	detail   string // What was found, shown when the check passed
+	hint     string // How to fix a failure
+	critical bool   // A failure stops the monitor from working at all
+}
+
+// runDoctor checks the monitor's prerequisites, prints a report and returns
+// the process exit code: 1 if a critical check failed, 0 otherwise
+func runDoctor(ctx context.Context, w io.Writer, cfg config) int {
+	m := initialModel(ctx, cfg, discardLogger())
+	var results []doctorResult
+
+	for i, t := range cfg.targets {
+		m.target = i
+		result := doctorResult{
+			name:     fmt.Sprintf("LocalStack %s reachable at %s", t.name, t.baseURL),
+			hint:     "Start LocalStack (e.g. 'docker compose up localstack') or point -target at it",
+			critical: true,
+		}
+		result.err = waitForLocalStack(ctx, t.baseURL, 0, cfg.proxy, cfg.tlsConfig)
+		results = append(results, result)
+		if result.err != nil {
+			continue
+		}
+
+		for _, path := range []string{"/_localstack/chaos/faults", "/_localstack/chaos/effects"} {
+			check := doctorResult{
+				name: fmt.Sprintf("Chaos API %s responds", path),
+				hint: "The Chaos API needs LocalStack Pro; without it faults are only detected from status files",
+			}
+			_, check.err = m.getChaosAPI(path)
+			results = append(results, check)
+		}
+	}
+	m.target = 0
+
+	docker := doctorResult{
+		name:     "Docker available",
+		hint:     "Install Docker and start the daemon; AWS service probes run the AWS CLI in a container",
+		critical: true,
+	}
+	docker.detail, docker.err = dockerOutput(ctx, "version", "--format", "{{.Server.Version}}")
+	results = append(results, docker)
+
+	if docker.err == nil {
+		image := doctorResult{
+			name: awsCLIImage + " image present",
+			hint: "Run 'docker pull " + awsCLIImage + "'; otherwise the first service probes pull it and time out",
+		}
+		_, image.err = dockerOutput(ctx, "image", "inspect", "--format", "{{.Id}}", awsCLIImage)
+		results = append(results, image)
+
+		if image.err == nil {
+			service, region := cfg.services[0], cfg.regions[0]
+			probe := doctorResult{
+				name: fmt.Sprintf("AWS CLI probe of %s in %s", service.name, region),
+				hint: "Check that LocalStack runs the service, and that no fault is injected into it",
+			}
+			status := m.checkAWSService(service, region)
+			if status.Status == "healthy" {
+				probe.detail = fmt.Sprintf("%.1fs", status.ResponseTime)
+			} else {
+				probe.err = fmt.Errorf("%s (%s)", status.Status, status.FailureType)
+			}
+			results = append(results, probe)
+		}
+	}
+
+	for _, dir := range monitor.StatusDirs {
+		results = append(results, checkStatusDir(dir))
+	}
+
+	writeDoctorReport(w, results)
+	for _, r := range results {
+		if r.err != nil && r.critical {
+			return 1
+		}
+	}
+	return 0
+}
+
+// dockerOutput runs a docker command and returns its trimmed output
+func dockerOutput(ctx context.Context, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput()
+	if errors.Is(err, exec.ErrNotFound) {
+		return "", fmt.Errorf("docker not found in PATH")
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return "", errors.New(firstLine(msg))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// checkStatusDir checks that a status directory can be read if it exists.
+// A missing directory is fine: test scripts create it when they start.
+func checkStatusDir(dir string) doctorResult {
+	result := doctorResult{
+		name: "Status directory " + dir + " readable",
+		hint: "Fix the directory's permissions so test status files can be read",
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		result.detail = "not created yet"
+		return result
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.detail = fmt.Sprintf("%d entries", len(entries))
+	return result
+}
+
+// firstLine returns the first line of s
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// writeDoctorReport prints each check with a remediation hint for failures
+func writeDoctorReport(w io.Writer, results []doctorResult) {
+	fmt.Fprintln(w, "Chaos Engineering Monitor prerequisites")
+	fmt.Fprintln(w)
+
+	failed, critical := 0, 0
+	for _, r := range results {
+		switch {
+		case r.err == nil && r.detail != "":
+			fmt.Fprintf(w, "  [ok]   %s (%s)\n", r.name, r.detail)
+		case r.err == nil:
+			fmt.Fprintf(w, "  [ok]   %s\n", r.name)
+		case r.critical:
+			fmt.Fprintf(w, "  [FAIL] %s: %v\n", r.name, r.err)
+			fmt.Fprintf(w, "         → %s\n", r.hint)
+		default:
+			fmt.Fprintf(w, "  [warn] %s: %v\n", r.name, r.err)
+			fmt.Fprintf(w, "         → %s\n", r.hint)
+		}
+		if r.err != nil {
+			failed++
+			if r.critical {
+				critical++
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%d checks, %d failed (%d critical)\n", len(results), failed, critical)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// userEndpoint is an HTTP endpoint added with -endpoint or -endpoints
+type userEndpoint struct {
+	name          string
+	url           string
+	expectedCodes []int // Acceptable status codes; empty keeps the endpoint's own
+}
+
+// endpointFlag is a repeatable flag of "name=url" entries, kept in the
+// order given
+type endpointFlag []userEndpoint
+
+func (f *endpointFlag) String() string {
+	var entries []string
+	for _, ep := range *f {
+		entry := ep.name + "=" + ep.url
+		if len(ep.expectedCodes) > 0 {
+			entry += " " + formatStatusCodes(ep.expectedCodes)
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, " ")
+}
+
+func (f *endpointFlag) Set(value string) error {
+	ep, err := parseUserEndpoint(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, ep)
+	return nil
+}
+
+// parseUserEndpoint parses a "name=http://host/path" entry, optionally
+// followed by the accepted status codes, as in "name=http://host/ 200,301"
+func parseUserEndpoint(entry string) (userEndpoint, error) {
+	name, rest, ok := strings.Cut(entry, "=")
+	name = strings.TrimSpace(name)
+	fields := strings.Fields(rest)
+	if !ok || name == "" || len(fields) == 0 || len(fields) > 2 {
+		return userEndpoint{}, fmt.Errorf("expected 'name=http://host/path [codes]', got %q", entry)
+	}
+	rawURL := fields[0]
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return userEndpoint{}, fmt.Errorf("invalid URL for endpoint %s: %q (want http:// or https://)", name, rawURL)
+	}
+	ep := userEndpoint{name: name, url: rawURL}
+	if len(fields) == 2 {
+		if ep.expectedCodes, err = parseStatusCodes(fields[1]); err != nil {
+			return userEndpoint{}, fmt.Errorf("invalid status codes for endpoint %s: %v", name, err)
+		}
+	}
+	return ep, nil
+}
+
+// loadEndpointsFile reads "name=url [codes]" entries from path, one per
+// line. Blank lines and lines starting with # are skipped.
+func loadEndpointsFile(path string) ([]userEndpoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var endpoints []userEndpoint
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		ep, err := parseUserEndpoint(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints, scanner.Err()
+}
+
+// withUserEndpoints adds the user-defined endpoints to the built-in ones.
+// One named like a built-in endpoint replaces its URL; later entries win.
+func withUserEndpoints(endpoints []endpointDef, user []userEndpoint) []endpointDef {
+	for _, ep := range user {
+		replaced := false
+		for i := range endpoints {
+			if endpoints[i].name == ep.name {
+				endpoints[i].url = ep.url
+				if len(ep.expectedCodes) > 0 {
+					endpoints[i].expectedCodes = ep.expectedCodes
+				}
+				replaced = true
+			}
+		}
+		if !replaced {
+			endpoints = append(endpoints, endpointDef{name: ep.name, url: ep.url, expectedCodes: ep.expectedCodes})
+		}
+	}
+	return endpoints
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// csvHeader is the first row of a statistics export
+var csvHeader = []string{"kind", "name", "total_checks", "failures", "success_pct", "p50_s", "p90_s", "p99_s"}
+
+// exportCSV writes the accumulated statistics to a timestamped CSV file in
+// dir and returns its path. Percentiles cover the recent check history.
+func (m *model) exportCSV(dir string) (string, error) {
+	path := filepath.Join(dir, "chaos-stats-"+time.Now().Format("20060102-150405")+".csv")
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write(csvHeader)
+	for _, name := range sortedKeys(m.state.Stats.NginxStats) {
+		stats := m.state.Stats.NginxStats[name]
+		w.Write(csvRow("endpoint", name, stats.TotalChecks, stats.Failures, stats.SuccessRate, m.history["endpoint|"+name]))
+	}
+	for _, name := range sortedKeys(m.state.Stats.ServiceStats) {
+		stats := m.state.Stats.ServiceStats[name]
+		w.Write(csvRow("service", name, stats.TotalChecks, stats.TotalChecks-stats.OKCount, stats.AvailabilityPct, m.history["service|"+name]))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// csvRow formats one endpoint or service for the export
+func csvRow(kind, name string, total, failures int, successPct float64, samples []models.CheckSample) []string {
+	times := sortedResponseTimes(samples)
+	row := []string{kind, name, strconv.Itoa(total), strconv.Itoa(failures), strconv.FormatFloat(successPct, 'f', 2, 64)}
+	for _, p := range []float64{50, 90, 99} {
+		row = append(row, formatPercentile(times, p))
+	}
+	return row
+}
+
+// sortedResponseTimes returns the samples' response times in ascending order
+func sortedResponseTimes(samples []models.CheckSample) []float64 {
+	times := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		times = append(times, sample.ResponseTime)
+	}
+	sort.Float64s(times)
+	return times
+}
+
+// formatPercentile returns the nearest-rank percentile of sorted values, or
+// an empty field when there are none
+func formatPercentile(sorted []float64, p float64) string {
+	if len(sorted) == 0 {
+		return ""
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return strconv.FormatFloat(sorted[rank], 'f', 3, 64)
+}
+
+// exportStats writes a CSV export and reports the result in the event log
+func (m *model) exportStats() {
+	dir := m.cfg.csvOut
+	if dir == "" {
+		dir = "."
+	}
+	event := models.Event{Time: time.Now(), Severity: "info", Kind: "export"}
+	if path, err := m.exportCSV(dir); err != nil {
+		event.Severity = "error"
+		event.Message = fmt.Sprintf("CSV export failed: %v", err)
+	} else {
+		event.Message = "Statistics exported to " + path
+	}
+	m.appendEvents([]models.Event{event})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"slices"
+	"testing"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestExportCSV(t *testing.T) {
+	m := newTestModel(t)
+	m.state.Stats.NginxStats["Main Site"] = &models.EndpointStats{TotalChecks: 10, Failures: 1, SuccessRate: 90}
+	m.state.Stats.ServiceStats["S3"] = &models.ServiceStats{TotalChecks: 4, OKCount: 3, AvailabilityPct: 75}
+	for _, seconds := range []float64{0.4, 0.1, 0.3, 0.2, 1.5} {
+		m.addSample("endpoint|Main Site", models.CheckSample{ResponseTime: seconds})
+	}
+
+	path, err := m.exportCSV(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"kind", "name", "total_checks", "failures", "success_pct", "p50_s", "p90_s", "p99_s"},
+		{"endpoint", "Main Site", "10", "1", "90.00", "0.300", "1.500", "1.500"},
+		{"service", "S3", "4", "1", "75.00", "", "", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q", rows)
+	}
+	for i := range want {
+		if !slices.Equal(rows[i], want[i]) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"chaos-monitor-tui/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateFilter handles keys while the filter text is being typed. Enter
+// keeps the filter, esc clears it.
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	filter := m.filter
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		filter = ui.Filter{FailingOnly: filter.FailingOnly}
+	case tea.KeyEnter:
+		filter.Editing = false
+	case tea.KeyBackspace:
+		if runes := []rune(filter.Text); len(runes) > 0 {
+			filter.Text = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		filter.Text += string(msg.Runes)
+	}
+	m.setFilter(filter)
+	return m, nil
+}
+
+// setFilter changes which rows are shown, keeping the selected row selected
+// when it's still visible
+func (m *model) setFilter(filter ui.Filter) {
+	key := m.selectedKey()
+	m.filter = filter
+	m.selected = -1
+	m.selectKey(key)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCTarget(t *testing.T) {
+	tests := []struct {
+		url, addr, service string
+	}{
+		{"grpc://localhost:50051", "localhost:50051", ""},
+		{"grpc://localhost:50051/orders.Orders", "localhost:50051", "orders.Orders"},
+	}
+	for _, tt := range tests {
+		if addr, service := grpcTarget(tt.url); addr != tt.addr || service != tt.service {
+			t.Errorf("grpcTarget(%q) = %q, %q", tt.url, addr, service)
+		}
+	}
+}
+
+func TestCheckGRPCEndpoint(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	healthServer.SetServingStatus("orders.Orders", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("payments.Payments", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	// Dial the in-memory listener in place of the network
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	m := newTestModel(t)
+	m.grpcConns = map[string]*grpc.ClientConn{"bufnet": conn}
+
+	tests := []struct {
+		url, status, reason string
+	}{
+		{"grpc://bufnet", "ok", ""},
+		{"grpc://bufnet/orders.Orders", "ok", ""},
+		{"grpc://bufnet/payments.Payments", "failed", "health status NOT_SERVING"},
+		{"grpc://bufnet/unknown.Unknown", "failed", "unknown service"},
+	}
+	for _, tt := range tests {
+		got := m.checkGRPCEndpoint(endpointDef{name: "grpc", url: tt.url})
+		if got.Status != tt.status || got.Reason != tt.reason {
+			t.Errorf("%s: status %q (%q), want %q (%q)", tt.url, got.Status, got.Reason, tt.status, tt.reason)
+		}
+		if got.ResponseTime <= 0 {
+			t.Errorf("%s: response time %v not measured", tt.url, got.ResponseTime)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// newLogger builds the structured audit logger from the -log-* flags. It
+// discards everything unless -log-file is set; "-" logs to stderr, which is
+// only useful with -once since the dashboard owns the terminal otherwise.
+func newLogger(cfg config) (*slog.Logger, io.Closer, error) {
+	if cfg.logFile == "" {
+		return discardLogger(), nopWriteCloser{io.Discard}, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.logLevel)); err != nil {
+		return nil, nil, fmt.Errorf("invalid -log-level %q: %v", cfg.logLevel, err)
+	}
+
+	var w io.WriteCloser = nopWriteCloser{os.Stderr}
+	if cfg.logFile != "-" {
+		f, err := os.OpenFile(cfg.logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, nil, err
+		}
+		w = f
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(cfg.logFormat) {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), w, nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), w, nil
+	default:
+		w.Close()
+		return nil, nil, fmt.Errorf("invalid -log-format %q (available: text, json)", cfg.logFormat)
+	}
+}
+
+// discardLogger returns a logger that drops every record
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// logTick writes one record summarizing a refresh
+func logTick(logger *slog.Logger, state *models.MonitorState, took time.Duration) {
+	endpointsFailing := 0
+	for _, endpoint := range state.NginxEndpoints {
+		if !endpoint.Available() {
+			endpointsFailing++
+		}
+	}
+	servicesFailing := 0
+	for _, service := range state.AWSServices {
+		if service.Status != "healthy" {
+			servicesFailing++
+		}
+	}
+
+	logger.Info("tick",
+		slog.Int("update_count", state.UpdateCount),
+		slog.Duration("duration", took),
+		slog.Int("endpoints", len(state.NginxEndpoints)),
+		slog.Int("endpoints_failing", endpointsFailing),
+		slog.Int("services", len(state.AWSServices)),
+		slog.Int("services_failing", servicesFailing),
+		slog.Int("active_faults", len(state.ChaosAPIFaults)),
+		slog.Int("active_effects", len(state.ChaosAPIEffects)),
+		slog.Int("active_tests", len(state.ActiveTests)),
+	)
+}
+
+// logEvents writes one record per transition, at a level matching its severity
+func logEvents(logger *slog.Logger, events []models.Event) {
+	for _, event := range events {
+		level := slog.LevelInfo
+		switch event.Severity {
+		case "error":
+			level = slog.LevelError
+		case "warning":
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(context.Background(), level, "transition",
+			slog.String("kind", event.Kind),
+			slog.String("severity", event.Severity),
+			slog.String("message", event.Message),
+			slog.Time("observed_at", event.Time),
+		)
+	}
+}
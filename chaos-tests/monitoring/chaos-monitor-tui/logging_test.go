@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestTickLogged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	t.Setenv("PATH", t.TempDir()) // No docker, so the service probes return at once
+	useStatusDirs(t, t.TempDir())
+
+	cfg := config{
+		targets:     []target{{name: "local", baseURL: server.URL}},
+		historySize: models.DefaultHistorySize,
+		logFile:     filepath.Join(t.TempDir(), "monitor.log"),
+		logLevel:    "info",
+		logFormat:   "json",
+	}
+	logger, closer, err := newLogger(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := initialModel(ctx, cfg, logger)
+	m.Update(tickMsg(time.Now()))
+	closer.Close()
+
+	data, err := os.ReadFile(cfg.logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticks := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			continue
+		}
+		var record struct {
+			Msg         string `json:"msg"`
+			UpdateCount int    `json:"update_count"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("%v in %q", err, line)
+		}
+		if record.Msg == "tick" && record.UpdateCount == 1 {
+			ticks++
+		}
+	}
+	if ticks != 1 {
+		t.Errorf("log file has %d tick records, want 1:\n%s", ticks, data)
+	}
+}
@@ -1,95 +1,419 @@
 package main
 
 import (
-	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math/rand"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"chaos-monitor-tui/alert"
 	"chaos-monitor-tui/models"
 	"chaos-monitor-tui/monitor"
 	"chaos-monitor-tui/ui"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"google.golang.org/grpc"
 )
 
 const (
 	baseURL        = "http://localhost:4566"
 	nginxURL       = "http://localhost:4566/nginx-hello-world"
 	updateInterval = 2 * time.Second
+	uiInterval     = 250 * time.Millisecond // Redraws the countdown between refreshes
+	maxEvents      = 500                    // Events retained in the event log
+
+	maxCompletedTests = 5 // Recently completed tests shown on the dashboard
 )
 
+// tickMsg triggers a data refresh
 type tickMsg time.Time
 
+// uiTickMsg triggers a redraw only, keeping the countdown current
+type uiTickMsg time.Time
+
+// refreshMsg requests a single out-of-band update without rescheduling the tick
+type refreshMsg struct{}
+
 type model struct {
+	ctx    context.Context // Cancelled on shutdown to abort in-flight probes
+	client *http.Client    // Shared by all HTTP probes and Chaos API calls
 	state  models.MonitorState
 	width  int
 	height int
 	err    error
+	paused bool // When true, ticks don't refresh monitoring data
+	cfg    config
+
+	compact bool // Show the single-screen summary instead of the full dashboard
+
+	target       int           // Index of the shown target in cfg.targets
+	targetStates []targetState // Data kept for the other targets
+
+	// Transports for probes restricted to one address family, by network
+	familyTransports map[string]*http.Transport
+	grpcConns        map[string]*grpc.ClientConn // gRPC health probe connections, by address
+
+	// state is only touched on the Bubble Tea goroutine; other goroutines
+	// read the copy published to store after each refresh
+	store *stateStore
+
+	metrics *metricsExporter // Nil unless -otlp-endpoint is set
+	api     *apiServer       // Nil unless -api-addr is set
+	stream  *streamServer    // Nil unless -ws-addr is set
+
+	logger   *slog.Logger    // Structured audit log; discards unless -log-file is set
+	notifier *alert.Notifier // Nil unless an alert sink (-notify, -slack-webhook, -pagerduty-key, -smtp-host) is set
+	recorder *recorder       // Nil unless -record is set
+	replay   *replaySession  // Non-nil when replaying a -replay file instead of probing
+
+	// Posts chaos test starts and ends; nil unless -annotation-webhook is set
+	annotations *alert.Annotations
+
+	// Test status piped in on stdin; nil unless -status-stdin is set
+	statusStream *monitor.StatusStream
+
+	// Fault injection controls (only with -control)
+	form           *faultForm
+	controlMessage string
+	controlError   bool
+
+	// Event log of state transitions between ticks
+	prevState models.MonitorState
+	events    []models.Event
+	logView   viewport.Model
+	showLog   bool
+
+	// Title bar message, e.g. a clipboard confirmation, shown until noticeUntil
+	notice      string
+	noticeUntil time.Time
+
+	// Until when a second 'R' confirms resetting the statistics
+	resetConfirmUntil time.Time
+
+	// Alert suppression: toggled with 'm', and whether it or -quiet-hours
+	// was in effect at the last check
+	maintenance       bool
+	maintenanceActive bool
+
+	// Highest chaos intensity this session, for the incident report
+	peakIntensity   float64
+	peakIntensityAt time.Time
+
+	// Scrolls the dashboard below the title and tab bars
+	body viewport.Model
+
+	// Infers cascade failures from the order services start failing
+	cascade *monitor.CascadeDetector
+
+	// Infers API throttling from 429s that persist across checks
+	throttling *monitor.ThrottleDetector
+
+	// Rows that changed recently, highlighted until the recorded time
+	flashUntil map[string]time.Time
+
+	tab    int          // Active dashboard tab
+	sort   ui.SortOrder // Order of the endpoint and service tables
+	filter ui.Filter    // Rows shown in the endpoint and service tables
+
+	// Row selection and the detail overlay
+	selected   int // Index into selectableRows; -1 when nothing is selected
+	showDetail bool
+	history    map[string][]models.CheckSample // Recent checks per row, keyed like flashUntil
+
+	// Chaos API faults and effects configured at each recent refresh, oldest first
+	faultCounts []models.FaultCount
+
+	// When the last data tick was scheduled and its interval, for the countdown
+	lastTick  time.Time
+	tickDelay time.Duration
 }
 
-func initialModel() model {
+// initialModel builds the monitor's model, writing its audit log to logger
+func initialModel(ctx context.Context, cfg config, logger *slog.Logger) model {
 	return model{
-		state: models.MonitorState{
-			Stats: models.Statistics{
-				NginxStats:   make(map[string]*models.EndpointStats),
-				ServiceStats: make(map[string]*models.ServiceStats),
-				StartTime:    time.Now(),
-			},
+		ctx:        ctx,
+		client:     newHTTPClient(cfg.proxy, cfg.tlsConfig),
+		cfg:        cfg,
+		logView:    newLogViewport(),
+		body:       newBodyViewport(),
+		flashUntil: make(map[string]time.Time),
+		history:    make(map[string][]models.CheckSample),
+		logger:     logger,
+		selected:   -1,
+		lastTick:   time.Now(),
+		tickDelay:  jitter(updateInterval, cfg.jitterPct),
+		state:      newMonitorState(),
+		store:      newStateStore(),
+		cascade:    monitor.NewCascadeDetector(cfg.cascade),
+		throttling: monitor.NewThrottleDetector(cfg.throttleChecks),
+	}
+}
+
+// newMonitorState returns an empty state with statistics starting now
+func newMonitorState() models.MonitorState {
+	return models.MonitorState{
+		Stats: models.Statistics{
+			NginxStats:   make(map[string]*models.EndpointStats),
+			ServiceStats: make(map[string]*models.ServiceStats),
+			RegionStats:  make(map[string]map[string]*models.ServiceStats),
+			StartTime:    time.Now(),
 		},
 	}
 }
 
 func (m model) Init() tea.Cmd {
 	return tea.Batch(
-		tickCmd(),
+		tickCmd(m.tickDelay),
+		uiTickCmd(),
 		tea.EnterAltScreen,
 	)
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(updateInterval, func(t time.Time) tea.Msg {
+func tickCmd(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
+func uiTickCmd() tea.Cmd {
+	return tea.Tick(uiInterval, func(t time.Time) tea.Msg {
+		return uiTickMsg(t)
+	})
+}
+
+// scheduleTick schedules the next data tick, remembering when it's due
+func (m *model) scheduleTick() tea.Cmd {
+	m.lastTick = time.Now()
+	m.tickDelay = m.tickInterval()
+	return tickCmd(m.tickDelay)
+}
+
+// tickInterval is the delay until the next refresh
+func (m model) tickInterval() time.Duration {
+	if m.replay != nil {
+		return m.replay.nextDelay()
+	}
+	return jitter(updateInterval, m.cfg.jitterPct)
+}
+
+// jitter spreads interval by up to ±pct percent, uniformly, so several
+// monitors don't probe in lockstep while the average interval stays the same
+func jitter(interval time.Duration, pct float64) time.Duration {
+	if pct <= 0 {
+		return interval
+	}
+	spread := float64(interval) * pct / 100
+	return interval + time.Duration((rand.Float64()*2-1)*spread)
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.form != nil {
+			return m.updateForm(msg)
+		}
+		if m.filter.Editing {
+			return m.updateFilter(msg)
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 		case "r":
-			// Force refresh
+			// Force refresh, even while paused
 			return m, func() tea.Msg {
-				return tickMsg(time.Now())
+				return refreshMsg{}
+			}
+		case "p":
+			m.paused = !m.paused
+		case "m":
+			m.maintenance = !m.maintenance
+			m.updateMaintenance()
+		case "R":
+			if m.replay == nil {
+				m.requestReset()
+			}
+		case "a":
+			if m.cfg.control {
+				m.form = newFaultForm()
+			}
+		case "x":
+			if m.cfg.control {
+				return m, clearFaultsCmd(m.ctx, m.client, m.currentTarget().baseURL)
+			}
+		case "up", "k", "down", "j":
+			// The arrow keys scroll the event log while it's open
+			if m.logVisible() && !m.showDetail {
+				var cmd tea.Cmd
+				m.logView, cmd = m.logView.Update(msg)
+				return m, cmd
 			}
+			delta := 1
+			if msg.String() == "up" || msg.String() == "k" {
+				delta = -1
+			}
+			if len(m.selectableRows()) == 0 {
+				m.scrollBody(delta)
+			} else {
+				m.moveSelection(delta)
+				m.scrollToSelection()
+			}
+		case "pgup", "pgdown", "home", "end":
+			// Page through the event log while it's open, else the dashboard
+			if m.logVisible() && !m.showDetail && (msg.String() == "pgup" || msg.String() == "pgdown") {
+				var cmd tea.Cmd
+				m.logView, cmd = m.logView.Update(msg)
+				return m, cmd
+			}
+			m.pageBody(msg.String())
+		case "enter":
+			m.showDetail = m.selectedKey() != ""
+		case "esc":
+			// Close the detail view, then clear the filter, then the selection
+			if m.showDetail {
+				m.showDetail = false
+			} else if m.filter.Active() {
+				m.setFilter(ui.Filter{})
+			} else {
+				m.selected = -1
+			}
+		case "/":
+			m.filter.Editing = true
+		case "f":
+			filter := m.filter
+			filter.FailingOnly = !filter.FailingOnly
+			m.setFilter(filter)
+		case "1", "2", "3", "4", "5":
+			m.setTab(int(msg.String()[0] - '1'))
+		case "tab":
+			m.setTab((m.tab + 1) % len(ui.TabNames))
+		case "shift+tab":
+			m.setTab((m.tab + len(ui.TabNames) - 1) % len(ui.TabNames))
+		case "s":
+			// Keep the same row selected while its position changes
+			key := m.selectedKey()
+			m.sort.Key = (m.sort.Key + 1) % len(ui.SortNames)
+			m.selectKey(key)
+		case "S":
+			key := m.selectedKey()
+			m.sort.Desc = !m.sort.Desc
+			m.selectKey(key)
+		case "l":
+			m.showLog = !m.showLog
+		case "c":
+			m.compact = !m.compact
+		case "e":
+			m.exportStats()
+		case "E":
+			m.exportScenario()
+		case "M":
+			m.generateReport()
+		case "y", "Y":
+			// 'y' copies the dashboard as text, 'Y' the state as JSON
+			m.copyToClipboard(msg.String() == "Y")
+		case "t":
+			if len(m.cfg.targets) > 1 {
+				m.switchTarget(1)
+				m.appendEvents([]models.Event{{
+					Time:     time.Now(),
+					Severity: "info",
+					Kind:     "target",
+					Message:  "Switched to " + m.currentTarget().name + " at " + m.currentTarget().baseURL,
+				}})
+				// Probe the new target right away rather than on the next tick
+				return m, func() tea.Msg {
+					return refreshMsg{}
+				}
+			}
+		case "left", "right":
+			if m.replay != nil {
+				delta := 1
+				if msg.String() == "left" {
+					delta = -1
+				}
+				m.replay.step(delta)
+				m.showReplayFrame()
+			}
+		default:
+			if m.logVisible() {
+				var cmd tea.Cmd
+				m.logView, cmd = m.logView.Update(msg)
+				return m, cmd
+			}
+		}
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
+	case controlResultMsg:
+		if msg.err != nil {
+			m.controlMessage = "Chaos API error: " + msg.err.Error()
+			m.controlError = true
+		} else {
+			m.controlMessage = msg.message
+			m.controlError = false
+		}
+		// Refresh so the change shows up immediately
+		return m, func() tea.Msg {
+			return refreshMsg{}
 		}
 
 	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+		// Only the layout changes; the data is refreshed on the next tick
+		m.resize(msg.Width, msg.Height)
 
 	case tickMsg:
-		// Update monitoring data
+		// Update monitoring data unless paused; keep ticking so resume works
+		if !m.paused {
+			m.updateMonitoringData()
+		}
+		return m, m.scheduleTick()
+
+	case uiTickMsg:
+		return m, uiTickCmd()
+
+	case refreshMsg:
 		m.updateMonitoringData()
-		return m, tickCmd()
 	}
 
 	return m, nil
 }
 
 func (m *model) updateMonitoringData() {
+	// Rows can move when sorted by status, so follow the selected row
+	defer m.selectKey(m.selectedKey())
+
+	if m.replay != nil {
+		if !m.replay.done() {
+			m.replay.step(1)
+		}
+		m.showReplayFrame()
+		return
+	}
+
 	m.state.UpdateCount++
 	m.state.LastUpdate = time.Now()
+	defer func(start time.Time) {
+		logTick(m.logger, &m.state, time.Since(start))
+	}(m.state.LastUpdate)
 
 	// Update Chaos API status
 	m.updateChaosAPIStatus()
+	m.updateLocalStackHealth()
 
 	// Update Nginx endpoints
 	m.updateNginxEndpoints()
@@ -102,182 +426,289 @@ func (m *model) updateMonitoringData() {
 
 	// Detect active chaos tests
 	m.detectActiveChaosTests()
+
+	m.state.ChaosIntensity = monitor.ChaosIntensity(&m.state, monitor.DefaultIntensityWeights)
+	m.trackPeakIntensity()
+
+	// Record transitions since the previous tick
+	m.recordEvents()
+	m.recordHistory()
+
+	if m.metrics != nil {
+		m.metrics.record(m.ctx, m.currentTarget().baseURL, &m.state)
+	}
+	m.store.publish(&m.state)
+	if m.stream != nil {
+		m.stream.publish(&m.state)
+	}
+	if m.recorder != nil {
+		m.recorder.write(&m.state)
+	}
+	m.updateMaintenance()
+	if m.notifier != nil {
+		m.notifier.Update(m.ctx, &m.state)
+	}
+	if m.annotations != nil {
+		m.annotations.Update(m.currentTarget().name, m.state.ActiveTests, m.state.LastUpdate)
+	}
 }
 
-func (m *model) updateChaosAPIStatus() {
-	// Get faults
-	resp, err := http.Get(baseURL + "/_localstack/chaos/faults")
-	if err == nil && resp.StatusCode == 200 {
-		body, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
+// showReplayFrame displays the recorded state at the playback position,
+// feeding it through the same event and highlight tracking as live data
+func (m *model) showReplayFrame() {
+	m.state = m.replay.current()
+	m.recordEvents()
+	m.recordHistory()
+}
 
-		var faults []models.ChaosAPIFault
-		if err := json.Unmarshal(body, &faults); err == nil {
-			m.state.ChaosAPIFaults = faults
-		}
+// recordEvents diffs the state against the previous tick and appends any
+// transitions to the event log
+func (m *model) recordEvents() {
+	events := monitor.DiffStates(&m.prevState, &m.state)
+	logEvents(m.logger, events)
+	m.recordChanges(monitor.ChangedRows(&m.prevState, &m.state))
+	m.prevState = m.state
+	m.appendEvents(events)
+}
+
+// appendEvents adds events to the event log
+func (m *model) appendEvents(events []models.Event) {
+	if len(events) == 0 {
+		return
 	}
 
-	// Get effects
-	resp, err = http.Get(baseURL + "/_localstack/chaos/effects")
-	if err == nil && resp.StatusCode == 200 {
-		body, _ := io.ReadAll(resp.Body)
-		resp.Body.Close()
+	// Keep following new events unless the user has scrolled up
+	follow := m.logView.AtBottom()
+	m.events = monitor.AppendEvents(m.events, events, maxEvents)
+	m.logView.SetContent(ui.FormatEvents(m.events))
+	if follow {
+		m.logView.GotoBottom()
+	}
+}
 
-		var effects []models.ChaosAPIEffect
-		if err := json.Unmarshal(body, &effects); err == nil {
-			m.state.ChaosAPIEffects = effects
+// recordChanges marks changed rows to be highlighted for the configured duration
+func (m *model) recordChanges(changes models.ChangeSet) {
+	// The first tick has nothing to compare against
+	if m.state.UpdateCount <= 1 {
+		return
+	}
+
+	until := time.Now().Add(m.cfg.highlight)
+	for name := range changes.Endpoints {
+		m.flashUntil["endpoint|"+name] = until
+	}
+	for label := range changes.Services {
+		m.flashUntil["service|"+label] = until
+	}
+	for key := range changes.Faults {
+		m.flashUntil["fault|"+key] = until
+	}
+}
+
+// activeChanges returns the rows still within their highlight window
+func (m model) activeChanges() models.ChangeSet {
+	changes := models.NewChangeSet()
+	now := time.Now()
+	for key, until := range m.flashUntil {
+		if now.After(until) {
+			continue
+		}
+		kind, id, _ := strings.Cut(key, "|")
+		switch kind {
+		case "endpoint":
+			changes.Endpoints[id] = true
+		case "service":
+			changes.Services[id] = true
+		case "fault":
+			changes.Faults[id] = true
 		}
 	}
+	return changes
 }
 
-func (m *model) updateNginxEndpoints() {
-	// Get terraform outputs for dynamic endpoint configuration
-	domainName := m.getTerraformOutput("domain_name", "hello.localstack.cloud")
-	usEast1ALB := m.getTerraformOutput("us_east_1_alb_dns", "")
-	usEast2ALB := m.getTerraformOutput("us_east_2_alb_dns", "")
+// setTab switches the dashboard tab
+func (m *model) setTab(tab int) {
+	m.tab = tab
+	m.resizeLog()
+	m.body.GotoTop()
+}
 
-	endpoints := []struct {
-		name string
-		url  string
-	}{
-		{"Main Site", "http://" + domainName},
+// logVisible reports whether the event log is on screen
+func (m model) logVisible() bool {
+	return m.showLog || m.tab == ui.TabLog
+}
+
+// resizeLog gives the event log the whole screen on the Log tab
+func (m *model) resizeLog() {
+	height := ui.EventLogHeight
+	if m.tab == ui.TabLog && m.height-ui.TabLogChrome > height {
+		height = m.height - ui.TabLogChrome
+	}
+	follow := m.logView.AtBottom()
+	m.logView.Height = height
+	if follow {
+		m.logView.GotoBottom()
 	}
+}
 
-	// Add regional endpoints if ALB DNS names are available
-	if usEast1ALB != "" {
-		endpoints = append(endpoints, struct{name string; url string}{"US-EAST-1", "http://" + usEast1ALB})
-	} else {
-		// Fallback to S3 static files if ALB not available
-		endpoints = append(endpoints, struct{name string; url string}{"US-EAST-1", nginxURL + "/us-east-1.html"})
+func newLogViewport() viewport.Model {
+	vp := viewport.New(0, ui.EventLogHeight)
+	vp.KeyMap = viewport.KeyMap{
+		PageDown:     key.NewBinding(key.WithKeys("pgdown")),
+		PageUp:       key.NewBinding(key.WithKeys("pgup")),
+		HalfPageUp:   key.NewBinding(key.WithKeys("ctrl+u")),
+		HalfPageDown: key.NewBinding(key.WithKeys("ctrl+d")),
+		Up:           key.NewBinding(key.WithKeys("up", "k")),
+		Down:         key.NewBinding(key.WithKeys("down", "j")),
 	}
+	vp.SetContent(ui.FormatEvents(nil))
+	return vp
+}
 
-	if usEast2ALB != "" {
-		endpoints = append(endpoints, struct{name string; url string}{"US-EAST-2", "http://" + usEast2ALB})
-	} else {
-		// Fallback to S3 static files if ALB not available
-		endpoints = append(endpoints, struct{name string; url string}{"US-EAST-2", nginxURL + "/us-east-2.html"})
+func (m *model) updateChaosAPIStatus() {
+	// A body in an unexpected shape keeps the last good configuration and
+	// is reported, rather than passing for "no chaos"
+	var warnings []string
+
+	// Get faults
+	if body, err := m.getChaosAPI("/_localstack/chaos/faults"); err == nil {
+		if faults, err := parseChaosFaults(body); err == nil {
+			m.state.ChaosAPIFaults = faults
+		} else {
+			warnings = append(warnings, err.Error())
+		}
 	}
 
-	m.state.NginxEndpoints = nil
+	// Get effects
+	if body, err := m.getChaosAPI("/_localstack/chaos/effects"); err == nil {
+		if effects, err := parseChaosEffects(body); err == nil {
+			m.state.ChaosAPIEffects = effects
+		} else {
+			warnings = append(warnings, err.Error())
+		}
+	}
 
-	for _, ep := range endpoints {
-		status := m.checkHTTPEndpoint(ep.url)
-		status.Name = ep.name
-		status.URL = ep.url
-		m.state.NginxEndpoints = append(m.state.NginxEndpoints, status)
+	warning := strings.Join(warnings, "; ")
+	if warning != "" && warning != m.state.ChaosAPIWarning {
+		m.logger.Warn("chaos API format unexpected", "detail", warning)
 	}
+	m.state.ChaosAPIWarning = warning
 }
 
-func (m *model) checkHTTPEndpoint(url string) models.EndpointStatus {
-	start := time.Now()
-	status := models.EndpointStatus{
-		LastChecked: start,
+// updateLocalStackHealth records the backend states LocalStack reports, so a
+// service that's still starting can be told apart from an injected outage.
+// Only the monitored services and any backend in trouble are kept.
+func (m *model) updateLocalStackHealth() {
+	health := models.LocalStackHealth{Services: make(map[string]string)}
+	defer func() { m.state.LocalStack = health }()
+
+	body, err := m.getChaosAPI("/_localstack/health")
+	if err != nil {
+		return
 	}
+	health.Reachable = true
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+	// Decode leniently: unexpected fields or shapes are ignored
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return
 	}
+	json.Unmarshal(payload["version"], &health.Version)
+	json.Unmarshal(payload["edition"], &health.Edition)
 
-	resp, err := client.Get(url)
-	if err != nil {
-		if strings.Contains(err.Error(), "timeout") {
-			status.Status = "timeout"
-		} else {
-			status.Status = "failed"
+	var services map[string]json.RawMessage
+	if err := json.Unmarshal(payload["services"], &services); err != nil {
+		return
+	}
+	monitored := make(map[string]bool)
+	for _, service := range m.cfg.services {
+		monitored[strings.ToLower(service.name)] = true
+	}
+	for name, raw := range services {
+		var state string
+		if err := json.Unmarshal(raw, &state); err != nil {
+			continue
+		}
+		if monitored[name] || (state != "available" && state != "running" && state != "disabled") {
+			health.Services[name] = state
 		}
-		status.ResponseTime = time.Since(start).Seconds()
-		return status
 	}
-	defer resp.Body.Close()
+}
 
-	status.HTTPCode = resp.StatusCode
-	status.ResponseTime = time.Since(start).Seconds()
+// getChaosAPI fetches a LocalStack API path and returns the body of a 200 response
+func (m *model) getChaosAPI(path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(m.ctx, http.MethodGet, m.currentTarget().baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	if resp.StatusCode == 200 {
-		status.Status = "ok"
-	} else {
-		status.Status = "failed"
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	return status
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("%s returned %d", path, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
 }
 
-func (m *model) updateAWSServices() {
-	services := []string{"s3", "dynamodb", "lambda"}
-	m.state.AWSServices = nil
+// endpointDefs returns the endpoints to monitor, with the per-endpoint
+// settings applied
+func (m *model) endpointDefs() []endpointDef {
+	// Get terraform outputs for dynamic endpoint configuration
+	domainName := m.getTerraformOutput("domain_name", "hello.localstack.cloud")
+	usEast1ALB := m.getTerraformOutput("us_east_1_alb_dns", "")
+	usEast2ALB := m.getTerraformOutput("us_east_2_alb_dns", "")
 
-	for _, service := range services {
-		status := m.checkAWSService(service)
-		status.Name = strings.ToUpper(service)
-		m.state.AWSServices = append(m.state.AWSServices, status)
+	endpoints := []endpointDef{
+		{name: "Main Site", url: "http://" + domainName},
 	}
-}
 
-func (m *model) checkAWSService(service string) models.ServiceStatus {
-	start := time.Now()
-	status := models.ServiceStatus{
-		LastChecked: start,
+	// Add regional endpoints if ALB DNS names are available
+	if usEast1ALB != "" {
+		endpoints = append(endpoints, endpointDef{name: "US-EAST-1", url: "http://" + usEast1ALB})
+	} else {
+		// Fallback to S3 static files if ALB not available
+		endpoints = append(endpoints, endpointDef{name: "US-EAST-1", url: m.currentTarget().nginxURL + "/us-east-1.html"})
 	}
 
-	var cmd *exec.Cmd
-	switch service {
-	case "s3":
-		cmd = exec.Command("docker", "run", "--rm", "--network", "host",
-			"-e", "AWS_ACCESS_KEY_ID=test",
-			"-e", "AWS_SECRET_ACCESS_KEY=test",
-			"-e", "AWS_DEFAULT_REGION=us-east-1",
-			"amazon/aws-cli",
-			"--endpoint-url", baseURL,
-			"s3", "ls")
-	case "dynamodb":
-		cmd = exec.Command("docker", "run", "--rm", "--network", "host",
-			"-e", "AWS_ACCESS_KEY_ID=test",
-			"-e", "AWS_SECRET_ACCESS_KEY=test",
-			"-e", "AWS_DEFAULT_REGION=us-east-1",
-			"amazon/aws-cli",
-			"--endpoint-url", baseURL,
-			"dynamodb", "list-tables")
-	case "lambda":
-		cmd = exec.Command("docker", "run", "--rm", "--network", "host",
-			"-e", "AWS_ACCESS_KEY_ID=test",
-			"-e", "AWS_SECRET_ACCESS_KEY=test",
-			"-e", "AWS_DEFAULT_REGION=us-east-1",
-			"amazon/aws-cli",
-			"--endpoint-url", baseURL,
-			"lambda", "list-functions")
+	if usEast2ALB != "" {
+		endpoints = append(endpoints, endpointDef{name: "US-EAST-2", url: "http://" + usEast2ALB})
+	} else {
+		// Fallback to S3 static files if ALB not available
+		endpoints = append(endpoints, endpointDef{name: "US-EAST-2", url: m.currentTarget().nginxURL + "/us-east-2.html"})
 	}
 
-	var out bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
+	endpoints = withUserEndpoints(endpoints, m.cfg.userEndpoints)
 
-	err := cmd.Run()
-	status.ResponseTime = time.Since(start).Seconds()
+	for _, name := range sortedKeys(m.cfg.grpcEndpoints) {
+		endpoints = append(endpoints, endpointDef{name: name, url: "grpc://" + m.cfg.grpcEndpoints[name]})
+	}
 
-	output := out.String() + stderr.String()
+	for i := range endpoints {
+		m.cfg.applyEndpointSettings(&endpoints[i])
+	}
 
-	if err != nil {
-		if strings.Contains(output, "ServiceUnavailable") || strings.Contains(output, "InternalError") {
-			status.Status = "outage"
-			status.FailureType = "service_outage"
-		} else if strings.Contains(output, "SlowDown") || strings.Contains(output, "TooManyRequests") ||
-			strings.Contains(output, "ThrottlingException") {
-			status.Status = "throttled"
-			status.FailureType = "throttled"
-		} else if strings.Contains(output, "QuotaExceeded") || strings.Contains(output, "ResourceInUseException") {
-			status.Status = "exhausted"
-			status.FailureType = "resource_exhausted"
-		} else {
-			status.Status = "outage"
-			status.FailureType = "error"
-		}
-	} else {
-		status.Status = "healthy"
-		status.FailureType = "ok"
+	endpoints = withAddressFamilies(endpoints)
+	if m.cfg.networkProbes {
+		endpoints = withNetworkProbes(endpoints)
 	}
+	return endpoints
+}
 
-	return status
+func (m *model) updateNginxEndpoints() {
+	m.state.NginxEndpoints = nil
+
+	for _, ep := range m.endpointDefs() {
+		status := m.checkEndpoint(ep)
+		status.Name = ep.name
+		status.URL = ep.url
+		status.ProbeKind = ep.probeKind()
+		m.state.NginxEndpoints = append(m.state.NginxEndpoints, status)
+	}
+	m.state.EndpointGroups = models.EndpointGroups(m.state.NginxEndpoints, m.cfg.endpointGroups)
 }
 
 func (m *model) updateStatistics() {
@@ -289,70 +720,166 @@ func (m *model) updateStatistics() {
 			m.state.Stats.NginxStats[endpoint.Name] = stats
 		}
 
-		stats.TotalChecks++
-		if endpoint.Status != "ok" {
-			stats.Failures++
-		}
-		stats.SuccessRate = float64(stats.TotalChecks-stats.Failures) * 100 / float64(stats.TotalChecks)
+		stats.Record(endpoint.Available(), m.state.LastUpdate, updateInterval)
+		stats.RecordLatency(endpoint.ResponseTime, m.cfg.latencyBuckets)
+		stats.Recent = models.AppendRecent(stats.Recent, endpoint.Status, m.cfg.historySize)
+		stats.ResponseTimeEMA = models.UpdateEMA(stats.ResponseTimeEMA, endpoint.ResponseTime, m.cfg.emaAlpha, stats.TotalChecks == 1)
 	}
 
 	// Update Service stats
 	for _, service := range m.state.AWSServices {
+		// A check that couldn't run isn't evidence either way
+		if service.FailureType == "docker_unavailable" {
+			continue
+		}
 		stats, exists := m.state.Stats.ServiceStats[service.Name]
 		if !exists {
 			stats = &models.ServiceStats{}
 			m.state.Stats.ServiceStats[service.Name] = stats
 		}
+		stats.Record(service.FailureType, m.state.LastUpdate, updateInterval)
+		if service.Error != "" {
+			stats.LastError = service.Error
+		}
+		stats.Recent = models.AppendRecent(stats.Recent, service.Status, m.cfg.historySize)
+		stats.ResponseTimeEMA = models.UpdateEMA(stats.ResponseTimeEMA, service.ResponseTime, m.cfg.emaAlpha, stats.TotalChecks == 1)
 
-		stats.TotalChecks++
-		switch service.FailureType {
-		case "ok":
-			stats.OKCount++
-		case "throttled":
-			stats.ThrottledCount++
-		case "service_outage":
-			stats.OutageCount++
-		case "resource_exhausted":
-			stats.ExhaustedCount++
+		// Per-region breakdown
+		if service.Region == "" {
+			continue
+		}
+		regions, exists := m.state.Stats.RegionStats[service.Name]
+		if !exists {
+			regions = make(map[string]*models.ServiceStats)
+			m.state.Stats.RegionStats[service.Name] = regions
+		}
+		regionStats, exists := regions[service.Region]
+		if !exists {
+			regionStats = &models.ServiceStats{}
+			regions[service.Region] = regionStats
 		}
-		stats.AvailabilityPct = float64(stats.OKCount) * 100 / float64(stats.TotalChecks)
+		regionStats.Record(service.FailureType, m.state.LastUpdate, updateInterval)
+		if service.Error != "" {
+			regionStats.LastError = service.Error
+		}
+		regionStats.Recent = models.AppendRecent(regionStats.Recent, service.Status, m.cfg.historySize)
+		regionStats.ResponseTimeEMA = models.UpdateEMA(regionStats.ResponseTimeEMA, service.ResponseTime, m.cfg.emaAlpha, regionStats.TotalChecks == 1)
+	}
+
+	m.state.SystemAvailability = nil
+	if availability, ok := models.SystemAvailability(m.state.Stats, m.cfg.weights); ok {
+		m.state.SystemAvailability = &availability
 	}
 }
 
+// recordCompletedTests keeps the most recently finished tests for display.
+// A test that was archived and also disappeared from the active list is
+// only kept once.
+func (m *model) recordCompletedTests(ended []models.ActiveChaosTest) {
+	for _, test := range ended {
+		if test.EndTime.IsZero() {
+			test.EndTime = test.LastSeen
+		}
+		if m.isCompleted(test) {
+			continue
+		}
+		m.state.CompletedTests = append([]models.ActiveChaosTest{test}, m.state.CompletedTests...)
+	}
+	if len(m.state.CompletedTests) > maxCompletedTests {
+		m.state.CompletedTests = m.state.CompletedTests[:maxCompletedTests]
+	}
+}
+
+// isCompleted reports whether a run of test is already in the completed list
+func (m *model) isCompleted(test models.ActiveChaosTest) bool {
+	for _, done := range m.state.CompletedTests {
+		if done.Type == test.Type && done.Target == test.Target && done.StartTime.Equal(test.StartTime) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectActiveChaosTests refreshes the active tests, moving any that have
+// finished since the last tick to the completed list
 func (m *model) detectActiveChaosTests() {
+	prev := m.state.ActiveTests
+	m.detectTests()
+	monitor.KeepStartTimes(prev, m.state.ActiveTests)
+	m.recordCompletedTests(monitor.EndedTests(prev, m.state.ActiveTests, time.Now()))
+}
+
+func (m *model) detectTests() {
 	// Clear previous detections
 	m.state.ActiveTests = []models.ActiveChaosTest{}
 
+	// Track failing services every tick so the propagation order is known
+	// even while other sources take precedence
+	cascade := m.cascade.Observe(m.state.AWSServices, m.state.LastUpdate)
+	throttling := m.throttling.Observe(m.state.NginxEndpoints, m.state.AWSServices, m.state.LastUpdate)
+
 	// First check for test status files
-	fileTests := monitor.DetectChaosTestFromFiles()
+	fileTests, archived := monitor.DetectChaosTestFromFiles(monitor.StatusFileOptions{
+		StaleAfter: m.cfg.staleAfter,
+		Prune:      m.cfg.prune,
+		Logger:     m.logger,
+		OnUnreadableDir: func(dir string, err error) {
+			m.appendEvents([]models.Event{{
+				Time:     time.Now(),
+				Severity: "warning",
+				Kind:     "config",
+				Message:  fmt.Sprintf("Status directory %s unreadable: %v", dir, err),
+			}})
+		},
+	})
 	m.state.ActiveTests = append(m.state.ActiveTests, fileTests...)
+	m.recordCompletedTests(archived)
+
+	// Merge in status reported by a remote source, e.g. from inside a container
+	if m.cfg.statusURL != "" {
+		remoteTests, err := monitor.FetchRemoteStatus(m.ctx, m.client, m.cfg.statusURL, m.cfg.staleAfter)
+		if err == nil {
+			fileTests = append(fileTests, remoteTests...)
+			m.state.ActiveTests = append(m.state.ActiveTests, remoteTests...)
+		}
+	}
+	if m.statusStream != nil {
+		streamTests := m.statusStream.Tests(time.Now(), m.cfg.staleAfter)
+		fileTests = append(fileTests, streamTests...)
+		m.state.ActiveTests = append(m.state.ActiveTests, streamTests...)
+	}
 
 	// If we have file-based tests, don't do behavioral detection to avoid duplicates
 	if len(fileTests) > 0 {
 		return
 	}
 
+	// Next look for chaos test scripts among running processes
+	processTests := monitor.DetectFromProcessList(m.cfg.testTypes)
+	m.state.ActiveTests = append(m.state.ActiveTests, processTests...)
+	if len(processTests) > 0 {
+		return
+	}
+
 	// Otherwise, detect based on Chaos API and behavior
 	// This provides backwards compatibility for tests that don't write status files
 	
 	// Detect based on Chaos API faults
 	if len(m.state.ChaosAPIFaults) > 0 {
 		for _, fault := range m.state.ChaosAPIFaults {
-			testType := "service-outage"
-			details := fmt.Sprintf("%.0f%% failure rate, Error %d", fault.Probability*100, fault.Error.StatusCode)
-			
-			// Check if it might be API throttling based on error code
-			if fault.Error.StatusCode == 429 || strings.Contains(fault.Error.Code, "Throttl") {
-				testType = "api-throttling"
-				details = fmt.Sprintf("Rate limiting active, Error %d", fault.Error.StatusCode)
-			}
-			
+			testType, details := faultTestType(fault, m.cfg.classifyRules)
+
+			affected := monitor.AffectedBy(fault, &m.state)
 			test := models.ActiveChaosTest{
 				Type:      testType,
 				Target:    fault.Service + " (" + fault.Region + ")",
+				Region:    fault.Region,
 				Status:    "active",
 				StartTime: time.Now(),
 				Details:   details,
+				Source:    "chaos_api",
+				Affected:  affected,
+				Impact:    monitor.ImpactLabel(affected),
 			}
 			m.state.ActiveTests = append(m.state.ActiveTests, test)
 		}
@@ -363,14 +890,35 @@ func (m *model) detectActiveChaosTests() {
 		for _, effect := range m.state.ChaosAPIEffects {
 			test := models.ActiveChaosTest{
 				Type:      "network-partition",
-				Target:    "all services",
+				Target:    effect.Target(),
+				Region:    effect.Region,
 				Status:    "active",
 				StartTime: time.Now(),
-				Details:   fmt.Sprintf("%dms latency injected", effect.Latency),
+				Details:   effect.Summary() + " injected",
 			}
 			m.state.ActiveTests = append(m.state.ActiveTests, test)
 		}
 	}
+
+	// Detect services failing one after another
+	if cascade != nil {
+		m.state.ActiveTests = append(m.state.ActiveTests, *cascade)
+	}
+
+	// Detect rate limiting applied outside the Chaos API, e.g. at the proxy
+	if throttling != nil && !hasTestType(m.state.ActiveTests, "api-throttling") {
+		m.state.ActiveTests = append(m.state.ActiveTests, *throttling)
+	}
+}
+
+// hasTestType reports whether any of the tests is of the given type
+func hasTestType(tests []models.ActiveChaosTest, testType string) bool {
+	for _, test := range tests {
+		if test.Type == testType {
+			return true
+		}
+	}
+	return false
 }
 
 func (m model) View() string {
@@ -382,7 +930,65 @@ func (m model) View() string {
 		return "Initializing..."
 	}
 
-	return ui.RenderDashboard(&m.state, m.width, m.height)
+	if m.showDetail && m.selectedKey() != "" {
+		return ui.RenderDetail(m.detailView(), m.width, m.height)
+	}
+
+	opts := m.dashboardOptions()
+	if m.compact || m.height < ui.CompactHeightThreshold {
+		return ui.RenderCompact(&m.state, opts, m.width)
+	}
+	header := ui.RenderDashboardHeader(&m.state, opts, m.width)
+	m.setBody(header, ui.RenderDashboardBody(&m.state, opts, m.width))
+	return header + "\n" + m.body.View()
+}
+
+// dashboardOptions collects what the dashboard shows besides the state
+func (m model) dashboardOptions() ui.DashboardOptions {
+	opts := ui.DashboardOptions{
+		Paused:   m.paused,
+		Control:  m.controlPanel(),
+		Changed:  m.activeChanges(),
+		SLO:      m.cfg.slo,
+		Selected: m.selectedKey(),
+		Tab:      m.tab,
+		Sort:     m.sort,
+		Filter:   m.filter,
+	}
+	if time.Now().Before(m.noticeUntil) {
+		opts.Notice = m.notice
+	}
+	opts.Maintenance = m.maintenanceActive
+	opts.FaultTrend = m.faultCounts
+	if m.replay != nil {
+		opts.Replay = m.replay.label()
+	}
+	if len(m.cfg.targets) > 1 {
+		opts.Target = fmt.Sprintf("Target: %s (%d/%d)", m.currentTarget().name, m.target+1, len(m.cfg.targets))
+	}
+	if !m.paused {
+		opts.NextRefresh = m.lastTick.Add(m.tickDelay)
+	}
+	if m.logVisible() {
+		opts.EventLog = m.logView.View()
+	}
+	opts.Baseline = m.cfg.baseline
+	return opts
+}
+
+// faultTestType classifies a Chaos API fault by its error code with the
+// same rules that classify AWS CLI errors, so a fault and the failures it
+// causes agree, and describes it
+func faultTestType(fault models.ChaosAPIFault, rules []classifyRule) (testType, details string) {
+	_, failureType := classifyAWSError(fault.Error.Code, rules)
+	switch {
+	case fault.Error.StatusCode == 429 || failureType == "throttled" || strings.Contains(fault.Error.Code, "Throttl"):
+		return "api-throttling", fmt.Sprintf("Rate limiting active, Error %d", fault.Error.StatusCode)
+	case failureType == "resource_exhausted":
+		return "resource-exhaustion", fmt.Sprintf("%.0f%% of calls out of capacity, Error %d %s", fault.Probability*100, fault.Error.StatusCode, fault.Error.Code)
+	default:
+		return "service-outage", fmt.Sprintf("%.0f%% failure rate, Error %d", fault.Probability*100, fault.Error.StatusCode)
+	}
 }
 
 func (m *model) getTerraformOutput(outputName string, defaultValue string) string {
@@ -421,17 +1027,215 @@ func (m *model) getTerraformOutput(outputName string, defaultValue string) strin
 	return defaultValue
 }
 
+// newNotifier returns a notifier delivering to the configured alert sinks,
+// or nil when there are none
+func newNotifier(ctx context.Context, cfg config, client *http.Client, logger *slog.Logger) *alert.Notifier {
+	var sinks []alert.Sink
+	if cfg.notify {
+		sinks = append(sinks, alert.Bell{W: os.Stdout, Desktop: cfg.notifyDesktop})
+	}
+	if cfg.slackWebhook != "" {
+		sinks = append(sinks, alert.NewSlack(ctx, cfg.slackWebhook, client, logger))
+	}
+	if cfg.pagerDutyKey != "" {
+		sinks = append(sinks, alert.NewPagerDuty(ctx, cfg.pagerDutyKey, "", client, logger))
+	}
+	if cfg.email.Addr != "" {
+		sinks = append(sinks, alert.NewEmail(ctx, cfg.email, logger))
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+	notifier := alert.NewNotifier(cfg.notifyAfter, sinks...)
+	if cfg.budgetAlert > 0 {
+		notifier.AlertOnBudget(cfg.slo, cfg.budgetAlert)
+	}
+	return notifier
+}
+
+// waitForLocalStack polls the health endpoint with exponential backoff until
+// LocalStack responds or the timeout is exhausted
+func waitForLocalStack(ctx context.Context, baseURL string, timeout time.Duration, proxy *url.URL, tlsConfig *tls.Config) error {
+	deadline := time.Now().Add(timeout)
+	backoff := 500 * time.Millisecond
+	client := newHTTPClient(proxy, tlsConfig)
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/_localstack/health", nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == 200 {
+				return nil
+			}
+			err = fmt.Errorf("health check returned %d", resp.StatusCode)
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return err
+		}
+
+		fmt.Printf("Waiting for LocalStack at %s (attempt %d): %v\n", baseURL, attempt, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(backoff, remaining)):
+		}
+		backoff = min(backoff*2, 5*time.Second)
+	}
+}
+
 func main() {
-	// Check if LocalStack is running
-	resp, err := http.Get(baseURL + "/_localstack/health")
-	if err != nil || resp.StatusCode != 200 {
-		fmt.Println("Error: LocalStack is not running at", baseURL)
-		fmt.Println("Please start LocalStack with 'make start'")
-		os.Exit(1)
+	cfg, err := parseFlags()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+
+	if cfg.version {
+		fmt.Println(versionString())
+		return
+	}
+
+	logger, logCloser, err := newLogger(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
 	}
+	defer logCloser.Close()
 
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	// Cancel in-flight probes and docker invocations on SIGINT/SIGTERM or quit
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if cfg.doctor {
+		code := runDoctor(ctx, os.Stdout, cfg)
+		cancel()
+		logCloser.Close()
+		os.Exit(code)
+	}
+
+	var replay *replaySession
+	if cfg.replayFile != "" {
+		if replay, err = loadReplay(cfg.replayFile, cfg.replaySpeed); err != nil {
+			fmt.Println("Error: could not load replay:", err)
+			os.Exit(1)
+		}
+	}
+
+	// Catch endpoint typos up front so they aren't mistaken for chaos
+	var problems []endpointProblem
+	if replay == nil {
+		probe := initialModel(ctx, cfg, logger)
+		endpoints := probe.endpointDefs()
+		problems = validateEndpoints(ctx, endpoints)
+		writeProblems(os.Stderr, problems)
+		if cfg.validate {
+			if hasFatalProblem(problems) {
+				os.Exit(1)
+			}
+			fmt.Printf("%d endpoints checked, %d with problems\n", len(endpoints), len(problems))
+			return
+		}
+	}
+
+	// Wait for LocalStack to come up; a replay doesn't probe anything
+	if replay == nil {
+		if err := waitForLocalStack(ctx, cfg.targets[0].baseURL, cfg.startupTimeout, cfg.proxy, cfg.tlsConfig); err != nil {
+			fmt.Println("Error: LocalStack is not running at", cfg.targets[0].baseURL)
+			fmt.Println("Please start LocalStack with 'make start'")
+			os.Exit(1)
+		}
+	}
+
+	var metrics *metricsExporter
+	if cfg.otlpEndpoint != "" {
+		if metrics, err = newMetricsExporter(ctx, cfg.otlpEndpoint); err != nil {
+			fmt.Println("Error: could not set up OTLP export:", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.once {
+		code := runOnce(ctx, cfg, metrics, logger)
+		cancel()
+		logCloser.Close()
+		if metrics != nil {
+			metrics.shutdown()
+		}
+		os.Exit(code)
+	}
+
+	m := initialModel(ctx, cfg, logger)
+	m.metrics = metrics
+	m.notifier = newNotifier(ctx, cfg, m.client, logger)
+	if cfg.annotationURL != "" {
+		m.annotations = alert.NewAnnotations(ctx, cfg.annotationURL, cfg.annotationFmt, m.client)
+	}
+	m.appendEvents(problemEvents(problems))
+	if replay != nil {
+		m.replay = replay
+		m.tickDelay = replay.nextDelay()
+		m.showReplayFrame()
+	}
+	if cfg.recordFile != "" {
+		if m.recorder, err = newRecorder(cfg.recordFile); err != nil {
+			fmt.Println("Error: could not open record file:", err)
+			os.Exit(1)
+		}
+		defer m.recorder.close()
+	}
+	if cfg.apiAddr != "" {
+		if m.api, err = newAPIServer(cfg.apiAddr, m.store); err != nil {
+			fmt.Println("Error: could not start API server:", err)
+			os.Exit(1)
+		}
+		defer m.api.shutdown()
+	}
+	if cfg.wsAddr != "" {
+		if m.stream, err = newStreamServer(cfg.wsAddr); err != nil {
+			fmt.Println("Error: could not start WebSocket server:", err)
+			os.Exit(1)
+		}
+		defer m.stream.shutdown()
+	}
+
+	options := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithContext(ctx)}
+	if cfg.statusStdin {
+		// stdin carries test status, so keys come from the terminal
+		m.statusStream = monitor.NewStatusStream(logger)
+		go func() {
+			if err := m.statusStream.Read(os.Stdin); err != nil {
+				logger.Warn("reading test status from stdin", "error", err)
+			}
+		}()
+		options = append(options, tea.WithInputTTY())
+	}
+
+	p := tea.NewProgram(m, options...)
+	final, err := p.Run()
+	cancel()
+	if cfg.reportPath != "" {
+		if fm, ok := final.(model); ok {
+			if err := fm.writeReportFile(cfg.reportPath); err != nil {
+				fmt.Println("Warning: could not write report:", err)
+			} else {
+				fmt.Println("Incident report written to", cfg.reportPath)
+			}
+		}
+	}
+	if metrics != nil {
+		// Flush the final measurements before exiting
+		if err := metrics.shutdown(); err != nil {
+			fmt.Println("Warning: OTLP metrics flush failed:", err)
+		}
+	}
+	if err != nil && !errors.Is(err, tea.ErrProgramKilled) {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+	"chaos-monitor-tui/monitor"
+)
+
+// newTestModel returns a model monitoring a single local target with the
+// defaults parseFlags would set, for tests that drive it directly
+func newTestModel(t testing.TB) model {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return initialModel(ctx, config{
+		targets:     []target{{name: "local", baseURL: "http://localhost:4566"}},
+		historySize: models.DefaultHistorySize,
+	}, discardLogger())
+}
+
+// useStatusDirs points monitor.StatusDirs at dirs for the rest of the test
+func useStatusDirs(t *testing.T, dirs ...string) {
+	t.Helper()
+	saved := monitor.StatusDirs
+	monitor.StatusDirs = dirs
+	t.Cleanup(func() { monitor.StatusDirs = saved })
+}
+
+func TestFinishedTestMovesToCompleted(t *testing.T) {
+	dir := t.TempDir()
+	useStatusDirs(t, dir)
+
+	m := newTestModel(t)
+	m.cfg.staleAfter = monitor.DefaultStaleAfter
+	start := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	status := filepath.Join(dir, "region.status.json")
+	data := fmt.Sprintf(`{"test_type":"region-failure","target":"us-east-1","status":"running","start_time":%q}`, start.Format(time.RFC3339))
+	if err := os.WriteFile(status, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m.detectActiveChaosTests()
+	if len(m.state.ActiveTests) != 1 || len(m.state.CompletedTests) != 0 {
+		t.Fatalf("after appearing: active %+v, completed %+v", m.state.ActiveTests, m.state.CompletedTests)
+	}
+
+	if err := os.Remove(status); err != nil {
+		t.Fatal(err)
+	}
+	m.detectActiveChaosTests()
+	if len(m.state.ActiveTests) != 0 || len(m.state.CompletedTests) != 1 {
+		t.Fatalf("after disappearing: active %+v, completed %+v", m.state.ActiveTests, m.state.CompletedTests)
+	}
+	done := m.state.CompletedTests[0]
+	if done.Type != "region-failure" || done.Status != "completed" || !done.StartTime.Equal(start) {
+		t.Errorf("completed = %+v", done)
+	}
+	if d := done.Duration(); d < time.Minute || d > 2*time.Minute {
+		t.Errorf("duration = %v, want about a minute", d)
+	}
+
+	// Seeing it end again doesn't add a second entry
+	m.recordCompletedTests([]models.ActiveChaosTest{done})
+	if len(m.state.CompletedTests) != 1 {
+		t.Errorf("completed twice: %+v", m.state.CompletedTests)
+	}
+}
+
+func TestUnreadableStatusDirEvent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "status")
+	if err := os.WriteFile(file, []byte("not a directory"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	useStatusDirs(t, file)
+
+	// Shown once in the event log, not on every tick
+	m := newTestModel(t)
+	m.detectActiveChaosTests()
+	m.detectActiveChaosTests()
+	if len(m.events) != 1 {
+		t.Fatalf("events = %+v", m.events)
+	}
+	if event := m.events[0]; event.Severity != "warning" || !strings.Contains(event.Message, file) || !strings.Contains(event.Message, "not a directory") {
+		t.Errorf("event = %+v", event)
+	}
+}
+
+func TestFaultTestType(t *testing.T) {
+	fault := func(status int, code string) models.ChaosAPIFault {
+		f := models.ChaosAPIFault{Service: "dynamodb", Region: "us-east-1", Probability: 0.25}
+		f.Error.StatusCode, f.Error.Code = status, code
+		return f
+	}
+	tests := []struct {
+		fault    models.ChaosAPIFault
+		testType string
+		details  string
+	}{
+		{fault(400, "QuotaExceeded"), "resource-exhaustion", "25% of calls out of capacity, Error 400 QuotaExceeded"},
+		{fault(400, "LimitExceededException"), "resource-exhaustion", "25% of calls out of capacity, Error 400 LimitExceededException"},
+		{fault(429, "QuotaExceeded"), "api-throttling", "Rate limiting active, Error 429"},
+		{fault(400, "ThrottlingException"), "api-throttling", "Rate limiting active, Error 400"},
+		{fault(503, "ServiceUnavailable"), "service-outage", "25% failure rate, Error 503"},
+		{fault(500, ""), "service-outage", "25% failure rate, Error 500"},
+	}
+	for _, tt := range tests {
+		testType, details := faultTestType(tt.fault, defaultClassifyRules)
+		if testType != tt.testType || details != tt.details {
+			t.Errorf("%d %s: got %s (%q), want %s (%q)", tt.fault.Error.StatusCode, tt.fault.Error.Code, testType, details, tt.testType, tt.details)
+		}
+	}
+
+	// Detected as such from the Chaos API when nothing else reports a test
+	useStatusDirs(t, t.TempDir())
+	m := newTestModel(t)
+	m.cfg.classifyRules = defaultClassifyRules
+	m.state.ChaosAPIFaults = []models.ChaosAPIFault{fault(400, "QuotaExceeded")}
+	m.detectActiveChaosTests()
+	if len(m.state.ActiveTests) != 1 || m.state.ActiveTests[0].Type != "resource-exhaustion" || m.state.ActiveTests[0].Source != "chaos_api" {
+		t.Errorf("active tests = %+v", m.state.ActiveTests)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// quietHours is a daily window, in local time, during which alerts are
+// suppressed. It may wrap past midnight; the zero value is never active.
+type quietHours struct {
+	start, end time.Duration // Offsets from midnight
+}
+
+// parseQuietHours parses a "22:00-06:00" window
+func parseQuietHours(spec string) (quietHours, error) {
+	from, to, ok := strings.Cut(spec, "-")
+	if !ok {
+		return quietHours{}, fmt.Errorf("expected 'HH:MM-HH:MM', got %q", spec)
+	}
+	var q quietHours
+	var err error
+	if q.start, err = parseClock(from); err != nil {
+		return quietHours{}, err
+	}
+	if q.end, err = parseClock(to); err != nil {
+		return quietHours{}, err
+	}
+	if q.start == q.end {
+		return quietHours{}, fmt.Errorf("window %q is empty", spec)
+	}
+	return q, nil
+}
+
+// parseClock parses "HH:MM" as an offset from midnight
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// contains reports whether t falls within the window
+func (q quietHours) contains(t time.Time) bool {
+	if q.start == q.end {
+		return false
+	}
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if q.start < q.end {
+		return offset >= q.start && offset < q.end
+	}
+	return offset >= q.start || offset < q.end
+}
+
+// inMaintenance reports whether alerts are suppressed, by 'm' or -quiet-hours
+func (m *model) inMaintenance(now time.Time) bool {
+	return m.maintenance || m.cfg.quietHours.contains(now)
+}
+
+// updateMaintenance applies a change of maintenance mode to the alerts and
+// notes it in the event log. Checks and events are recorded as usual
+// either way; only alert delivery stops.
+func (m *model) updateMaintenance() {
+	now := time.Now()
+	on := m.inMaintenance(now)
+	if on == m.maintenanceActive {
+		return
+	}
+	m.maintenanceActive = on
+
+	message := "Maintenance mode started, alerts suppressed"
+	if !on {
+		message = "Maintenance mode ended"
+		if m.notifier != nil {
+			message += fmt.Sprintf(", %d alerts suppressed", m.notifier.Held())
+		}
+	}
+	m.appendEvents([]models.Event{{
+		Time:     now,
+		Severity: "info",
+		Kind:     "maintenance",
+		Message:  message,
+	}})
+	m.logger.Info("maintenance mode", "active", on)
+	if m.notifier != nil {
+		m.notifier.SetSuppressed(m.ctx, &m.state, on)
+	}
+}
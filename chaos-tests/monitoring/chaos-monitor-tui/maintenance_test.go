@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/alert"
+	"chaos-monitor-tui/models"
+)
+
+// recordingSink keeps every batch of alerts sent to it
+type recordingSink struct {
+	batches [][]alert.Alert
+}
+
+func (s *recordingSink) Send(ctx context.Context, alerts []alert.Alert, state *models.MonitorState) {
+	s.batches = append(s.batches, alerts)
+}
+
+func TestMaintenanceSuppressesAlerts(t *testing.T) {
+	sink := &recordingSink{}
+	m := newTestModel(t)
+	m.notifier = alert.NewNotifier(1, sink)
+
+	tick := func(status string) {
+		t.Helper()
+		m.state.LastUpdate = time.Now()
+		m.state.NginxEndpoints = []models.EndpointStatus{{Name: "Main Site", Status: status}}
+		m.recordEvents()
+		m.updateMaintenance()
+		m.notifier.Update(m.ctx, &m.state)
+	}
+	lastEvent := func() string {
+		return m.events[len(m.events)-1].Message
+	}
+
+	tick("ok")
+	m.maintenance = true
+	tick("ok")
+	if lastEvent() != "Maintenance mode started, alerts suppressed" {
+		t.Errorf("event = %q", lastEvent())
+	}
+
+	// The outage is logged but not delivered
+	tick("failed")
+	tick("failed")
+	if len(sink.batches) != 0 {
+		t.Errorf("sent while suppressed: %+v", sink.batches)
+	}
+	var logged bool
+	for _, event := range m.events {
+		logged = logged || event.Message == "Main Site went down (failed)"
+	}
+	if !logged {
+		t.Errorf("outage not in the event log: %+v", m.events)
+	}
+
+	m.maintenance = false
+	tick("failed")
+	if lastEvent() != "Maintenance mode ended, 1 alerts suppressed" {
+		t.Errorf("event = %q", lastEvent())
+	}
+	if len(sink.batches) != 1 || len(sink.batches[0]) != 1 || sink.batches[0][0].Kind != "maintenance" || sink.batches[0][0].Checks != 1 {
+		t.Fatalf("after maintenance: %+v", sink.batches)
+	}
+
+	// The outage that began during maintenance doesn't alert again, but
+	// its recovery is delivered
+	tick("ok")
+	if len(sink.batches) != 2 || !sink.batches[1][0].Recovered {
+		t.Errorf("after recovering: %+v", sink.batches)
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	at := func(clock string) time.Time {
+		t.Helper()
+		parsed, err := time.Parse("15:04", clock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return time.Date(2024, 5, 1, parsed.Hour(), parsed.Minute(), 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		spec   string
+		inside []string
+		out    []string
+	}{
+		{"09:00-17:30", []string{"09:00", "12:00", "17:29"}, []string{"08:59", "17:30", "23:00"}},
+		{"22:00-06:00", []string{"22:00", "23:59", "00:00", "05:59"}, []string{"06:00", "12:00", "21:59"}},
+	}
+	for _, tt := range tests {
+		q, err := parseQuietHours(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.spec, err)
+		}
+		for _, clock := range tt.inside {
+			if !q.contains(at(clock)) {
+				t.Errorf("%s doesn't contain %s", tt.spec, clock)
+			}
+		}
+		for _, clock := range tt.out {
+			if q.contains(at(clock)) {
+				t.Errorf("%s contains %s", tt.spec, clock)
+			}
+		}
+	}
+
+	for _, spec := range []string{"22:00", "25:00-06:00", "08:00-08:00", "8am-5pm"} {
+		if _, err := parseQuietHours(spec); err == nil {
+			t.Errorf("%q accepted", spec)
+		}
+	}
+	if (quietHours{}).contains(at("12:00")) {
+		t.Error("the zero window is active")
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+// SystemAvailability combines every checked endpoint and service into one
+// availability percentage: the mean of their availabilities, weighted by
+// weights[name] (1 when unset, 0 to leave it out). Each HTTP endpoint and
+// each AWS service is one component, whatever its number of checks, so a
+// service probed in several regions or more often than the endpoints
+// doesn't outweigh them; an endpoint's availability is its success rate
+// and a service's the share of its checks that were healthy. ok is false
+// until a weighted component has been checked.
+func SystemAvailability(stats Statistics, weights map[string]float64) (pct float64, ok bool) {
+	var sum, total float64
+	add := func(name string, checks int, availability float64) {
+		weight, set := weights[name]
+		if !set {
+			weight = 1
+		}
+		if checks == 0 || weight <= 0 {
+			return
+		}
+		sum += weight * availability
+		total += weight
+	}
+	for name, endpoint := range stats.NginxStats {
+		add(name, endpoint.TotalChecks, endpoint.SuccessRate)
+	}
+	for name, service := range stats.ServiceStats {
+		add(name, service.TotalChecks, service.AvailabilityPct)
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return sum / total, true
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSystemAvailability(t *testing.T) {
+	stats := Statistics{
+		NginxStats: map[string]*EndpointStats{
+			"Main Site": {TotalChecks: 10, SuccessRate: 100},
+			"New Site":  {TotalChecks: 40, SuccessRate: 80},
+			"Unchecked": {TotalChecks: 0, SuccessRate: 0},
+		},
+		ServiceStats: map[string]*ServiceStats{
+			"S3":  {TotalChecks: 4, AvailabilityPct: 90},
+			"SQS": {TotalChecks: 200, AvailabilityPct: 50},
+		},
+	}
+	tests := []struct {
+		weights map[string]float64
+		want    float64
+	}{
+		// Each checked component counts once: (100 + 80 + 90 + 50) / 4
+		{nil, 80},
+		// (2*100 + 1*80 + 3*90 + 0*50) / (2 + 1 + 3)
+		{map[string]float64{"Main Site": 2, "S3": 3, "SQS": 0}, 550.0 / 6},
+		// (1*100 + 0.5*80 + 1*90 + 1*50) / 3.5
+		{map[string]float64{"New Site": 0.5, "Unchecked": 10}, 280.0 / 3.5},
+	}
+	for _, tt := range tests {
+		got, ok := SystemAvailability(stats, tt.weights)
+		if !ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("weights %v: got %v (%v), want %v", tt.weights, got, ok, tt.want)
+		}
+	}
+
+	// Nothing checked, or everything weighted out, has no availability
+	if got, ok := SystemAvailability(Statistics{}, nil); ok {
+		t.Errorf("no components: %v", got)
+	}
+	if got, ok := SystemAvailability(stats, map[string]float64{"Main Site": 0, "New Site": 0, "S3": 0, "SQS": 0}); ok {
+		t.Errorf("all weighted out: %v", got)
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+// BaselineDelta is how a row's statistics moved from a baseline run to the
+// current one
+type BaselineDelta struct {
+	Availability float64 // Percentage points, current minus baseline
+	ResponseTime float64 // Seconds of average response time, current minus baseline
+}
+
+// EndpointDelta compares an endpoint's statistics with its baseline
+func EndpointDelta(current, baseline *EndpointStats) BaselineDelta {
+	return BaselineDelta{
+		Availability: current.SuccessRate - baseline.SuccessRate,
+		ResponseTime: current.ResponseTimeEMA - baseline.ResponseTimeEMA,
+	}
+}
+
+// ServiceDelta compares a service's statistics with its baseline
+func ServiceDelta(current, baseline *ServiceStats) BaselineDelta {
+	return BaselineDelta{
+		Availability: current.AvailabilityPct - baseline.AvailabilityPct,
+		ResponseTime: current.ResponseTimeEMA - baseline.ResponseTimeEMA,
+	}
+}
@@ -1,90 +1,662 @@
 package models
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 )
 
 // ChaosAPIFault represents a fault configuration from the Chaos API
 type ChaosAPIFault struct {
-	ID          string  `json:"id"`
+	ID          string  `json:"id,omitempty"`
 	Service     string  `json:"service"`
 	Region      string  `json:"region"`
 	Probability float64 `json:"probability"`
 	Error       struct {
 		StatusCode int    `json:"statusCode"`
 		Code       string `json:"code"`
-		Message    string `json:"message"`
+		Message    string `json:"message,omitempty"`
 	} `json:"error"`
 }
 
-// ChaosAPIEffect represents a network effect configuration
+// Key identifies the fault, falling back to its settings when it has no ID
+func (f ChaosAPIFault) Key() string {
+	if f.ID != "" {
+		return f.ID
+	}
+	return strings.Join([]string{f.Service, f.Region, fmt.Sprint(f.Probability), f.Error.Code}, "|")
+}
+
+// ChaosAPIEffect represents a network effect configuration. Only Latency
+// is always present; the other fields are optional.
 type ChaosAPIEffect struct {
-	ID      string `json:"id"`
-	Latency int    `json:"latency"`
+	ID          string  `json:"id"`
+	Latency     int     `json:"latency"`               // Milliseconds added to each request
+	Jitter      int     `json:"jitter,omitempty"`      // Latency varies by up to ± this many milliseconds
+	Service     string  `json:"service,omitempty"`     // Affected service; empty means all
+	Region      string  `json:"region,omitempty"`      // Affected region; empty means all
+	Probability float64 `json:"probability,omitempty"` // Fraction of requests dropped, 0-1
+}
+
+// Target describes the services the effect applies to
+func (e ChaosAPIEffect) Target() string {
+	switch {
+	case e.Service == "" && e.Region == "":
+		return "all services"
+	case e.Service == "":
+		return "all services (" + e.Region + ")"
+	case e.Region == "":
+		return e.Service
+	default:
+		return e.Service + " (" + e.Region + ")"
+	}
+}
+
+// Summary describes the latency and loss the effect injects, e.g.
+// "500ms ±100ms latency, 10% loss"
+func (e ChaosAPIEffect) Summary() string {
+	var parts []string
+	if e.Latency > 0 || e.Jitter > 0 {
+		latency := fmt.Sprintf("%dms", e.Latency)
+		if e.Jitter > 0 {
+			latency += fmt.Sprintf(" ±%dms", e.Jitter)
+		}
+		parts = append(parts, latency+" latency")
+	}
+	if e.Probability > 0 {
+		parts = append(parts, fmt.Sprintf("%.0f%% loss", e.Probability*100))
+	}
+	if len(parts) == 0 {
+		return "no latency"
+	}
+	return strings.Join(parts, ", ")
 }
 
 // EndpointStatus represents the status of a monitored endpoint
 type EndpointStatus struct {
-	Name         string
-	URL          string
-	Status       string // "ok", "failed", "timeout"
-	ResponseTime float64
-	HTTPCode     int
-	LastChecked  time.Time
+	Name          string    `json:"name"`
+	URL           string    `json:"url"`
+	ProbeKind     string    `json:"probe_kind"` // "http", "tcp", "dns", "grpc"
+	Status        string    `json:"status"`     // "ok", "failed", "timeout", "throttled", "serving-fallback"
+	ResponseTime  float64   `json:"response_time"`
+	HTTPCode      int       `json:"http_code"`
+	LastChecked   time.Time `json:"last_checked"`
+	ExpectedCodes []int     `json:"expected_codes"` // HTTP status codes treated as "ok"
+	ContentMatch  string    `json:"content_match"`  // "matched", "mismatched", or empty when not validated
+	Reason        string    `json:"reason"`         // Why the check failed, if known
+	RetryAfter    string    `json:"retry_after"`    // Retry-After header of a throttled response
+	Address       string    `json:"address"`        // IP the probe reached or resolved, if any
+	Family        string    `json:"family"`         // "ipv4" or "ipv6", with Address
+	Retries       int       `json:"retries"`        // Failed attempts, with no response or a 5xx, before this result
+	ErrorKind     string    `json:"error_kind"`     // "tls" when the TLS handshake or certificate check failed
+}
+
+// Available reports whether the endpoint served its content, possibly
+// the fallback region's after a failover
+func (e EndpointStatus) Available() bool {
+	return e.Status == "ok" || e.Status == "serving-fallback"
 }
 
 // ServiceStatus represents the status of an AWS service
 type ServiceStatus struct {
-	Name         string
-	Status       string // "healthy", "throttled", "outage", "exhausted"
-	ResponseTime float64
-	LastChecked  time.Time
-	FailureType  string
+	Name         string    `json:"name"`
+	Region       string    `json:"region"`
+	Status       string    `json:"status"` // "healthy", "throttled", "outage", "exhausted"
+	ResponseTime float64   `json:"response_time"`
+	LastChecked  time.Time `json:"last_checked"`
+	FailureType  string    `json:"failure_type"`
+
+	Error string `json:"error,omitempty"` // Why the check failed, e.g. the AWS error code and message
 }
 
 // Statistics tracks cumulative statistics
 type Statistics struct {
-	NginxStats   map[string]*EndpointStats
-	ServiceStats map[string]*ServiceStats
-	StartTime    time.Time
+	NginxStats   map[string]*EndpointStats `json:"nginx_stats"`
+	ServiceStats map[string]*ServiceStats  `json:"service_stats"`
+	// RegionStats breaks service stats down by region: service -> region -> stats
+	RegionStats map[string]map[string]*ServiceStats `json:"region_stats"`
+	StartTime   time.Time                           `json:"start_time"`
 }
 
 // EndpointStats tracks statistics for a single endpoint
 type EndpointStats struct {
-	TotalChecks int
-	Failures    int
-	SuccessRate float64
+	TotalChecks int           `json:"total_checks"`
+	Failures    int           `json:"failures"`
+	SuccessRate float64       `json:"success_rate"`
+	Downtime    time.Duration `json:"downtime"`    // Time since the previous check, summed over failed checks
+	Transitions int           `json:"transitions"` // Status changes within the flap window
+	Flapping    bool          `json:"flapping"`
+
+	ResponseTimeEMA float64 `json:"response_time_ema"` // Exponential moving average, in seconds
+
+	// Availability over each of AvailabilityWindows, ending at the last check
+	RollingAvailability []float64 `json:"rolling_availability"`
+
+	// When the first and latest checks were made, for the check rate, and
+	// when a check last passed; zero if none has
+	FirstCheck  time.Time `json:"first_check"`
+	LastCheck   time.Time `json:"last_check"`
+	LastSuccess time.Time `json:"last_success"`
+
+	// Failing checks in a row, reset by a passing one
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// Statuses of the last -history-size checks, oldest first
+	Recent []string `json:"recent"`
+
+	// Response time distribution; Histogram[i] counts checks below
+	// LatencyBuckets[i], with the last entry for anything slower
+	LatencyBuckets []time.Duration `json:"latency_buckets"`
+	Histogram      []int           `json:"histogram"`
+
+	history statusHistory
+	window  checkWindow
+}
+
+// Rate returns the endpoint's checks per second
+func (s *EndpointStats) Rate() float64 {
+	return CheckRate(s.TotalChecks, s.FirstCheck, s.LastCheck)
+}
+
+// CheckRate returns the average checks per second of count checks made
+// from first to last. It's 0 until there are two checks to measure
+// between. When probes take longer than the refresh interval, the rate
+// falls below 1/interval.
+func CheckRate(count int, first, last time.Time) float64 {
+	if count < 2 || !last.After(first) {
+		return 0
+	}
+	return float64(count-1) / last.Sub(first).Seconds()
+}
+
+// UpdateEMA folds sample into an exponential moving average with smoothing
+// factor alpha (0-1, higher reacts faster). The first sample seeds it.
+func UpdateEMA(ema, sample, alpha float64, first bool) float64 {
+	if first {
+		return sample
+	}
+	return alpha*sample + (1-alpha)*ema
+}
+
+// DefaultLatencyBuckets are the histogram upper bounds used unless configured
+var DefaultLatencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// BucketIndex returns the histogram bucket for a response time: the first
+// bound it is strictly below, or len(bounds) when it is at or above them all
+func BucketIndex(bounds []time.Duration, responseTime time.Duration) int {
+	for i, bound := range bounds {
+		if responseTime < bound {
+			return i
+		}
+	}
+	return len(bounds)
+}
+
+// RecordLatency adds a response time, in seconds, to the histogram
+func (s *EndpointStats) RecordLatency(responseTime float64, bounds []time.Duration) {
+	if s.Histogram == nil {
+		s.LatencyBuckets = bounds
+		s.Histogram = make([]int, len(bounds)+1)
+	}
+	s.Histogram[BucketIndex(s.LatencyBuckets, time.Duration(responseTime*float64(time.Second)))]++
+}
+
+// Record counts a check result made at the given time. A failed check adds
+// the time since the previous check to the downtime, or interval if it's
+// the first.
+func (s *EndpointStats) Record(ok bool, at time.Time, interval time.Duration) {
+	s.RollingAvailability = s.window.add(at, ok)
+	elapsed := sinceLastCheck(s.TotalChecks, s.LastCheck, at, interval)
+	if s.TotalChecks == 0 {
+		s.FirstCheck = at
+	}
+	s.LastCheck = at
+	if ok {
+		s.LastSuccess = at
+		s.ConsecutiveFailures = 0
+	} else {
+		s.ConsecutiveFailures++
+	}
+	s.TotalChecks++
+	if !ok {
+		s.Failures++
+		s.Downtime += elapsed
+	}
+	status := "ok"
+	if !ok {
+		status = "failed"
+	}
+	s.Transitions = s.history.add(status)
+	s.Flapping = s.Transitions >= FlapThreshold
+	s.SuccessRate = float64(s.TotalChecks-s.Failures) * 100 / float64(s.TotalChecks)
+}
+
+// sinceLastCheck returns the time a check made at at covers: since the
+// previous check, or the nominal interval when there's none or the clock
+// went backwards
+func sinceLastCheck(checks int, last, at time.Time, interval time.Duration) time.Duration {
+	if checks == 0 || at.Before(last) {
+		return interval
+	}
+	return at.Sub(last)
 }
 
 // ServiceStats tracks statistics for a single service
 type ServiceStats struct {
-	TotalChecks     int
-	OKCount         int
-	ThrottledCount  int
-	OutageCount     int
-	ExhaustedCount  int
-	AvailabilityPct float64
+	TotalChecks     int           `json:"total_checks"`
+	OKCount         int           `json:"ok_count"`
+	ThrottledCount  int           `json:"throttled_count"`
+	OutageCount     int           `json:"outage_count"`
+	ExhaustedCount  int           `json:"exhausted_count"`
+	AvailabilityPct float64       `json:"availability_pct"`
+	Downtime        time.Duration `json:"downtime"`    // Time since the previous check, summed over failed checks
+	Transitions     int           `json:"transitions"` // Status changes within the flap window
+	Flapping        bool          `json:"flapping"`
+	ResponseTimeEMA float64       `json:"response_time_ema"` // Exponential moving average, in seconds
+
+	// Availability over each of AvailabilityWindows, ending at the last check
+	RollingAvailability []float64 `json:"rolling_availability"`
+
+	// When the first and latest checks were made, for the check rate, and
+	// when a check last passed; zero if none has
+	FirstCheck  time.Time `json:"first_check"`
+	LastCheck   time.Time `json:"last_check"`
+	LastSuccess time.Time `json:"last_success"`
+
+	// Failing checks in a row, reset by a passing one
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// Statuses of the last -history-size checks, oldest first
+	Recent []string `json:"recent"`
+
+	LastError string `json:"last_error,omitempty"` // Error of the most recent failed check
+
+	history statusHistory
+	window  checkWindow
+}
+
+// Rate returns the service's checks per second
+func (s *ServiceStats) Rate() float64 {
+	return CheckRate(s.TotalChecks, s.FirstCheck, s.LastCheck)
+}
+
+// Record counts a check result made at the given time by its failure type.
+// Anything other than "ok" adds the time since the previous check to the
+// downtime, or interval if it's the first.
+func (s *ServiceStats) Record(failureType string, at time.Time, interval time.Duration) {
+	s.RollingAvailability = s.window.add(at, failureType == "ok")
+	elapsed := sinceLastCheck(s.TotalChecks, s.LastCheck, at, interval)
+	if s.TotalChecks == 0 {
+		s.FirstCheck = at
+	}
+	s.LastCheck = at
+	if failureType == "ok" {
+		s.LastSuccess = at
+		s.ConsecutiveFailures = 0
+	} else {
+		s.ConsecutiveFailures++
+	}
+	s.TotalChecks++
+	if failureType != "ok" {
+		s.Downtime += elapsed
+	}
+	switch failureType {
+	case "ok":
+		s.OKCount++
+	case "throttled":
+		s.ThrottledCount++
+	case "service_outage":
+		s.OutageCount++
+	case "resource_exhausted":
+		s.ExhaustedCount++
+	}
+	s.AvailabilityPct = float64(s.OKCount) * 100 / float64(s.TotalChecks)
+	s.Transitions = s.history.add(failureType)
+	s.Flapping = s.Transitions >= FlapThreshold
+}
+
+// DefaultHistorySize is how many recent checks are kept per endpoint and
+// service for the heatmaps, sparklines and detail view, and how many
+// refreshes of fault counts for the chaos trend, unless configured. It
+// matches the heatmap length used before it was configurable. Each kept
+// check costs roughly 100 bytes per row, a detail sample plus a heatmap
+// status, so 20 rows keeping 10,000 checks use about 20 MB.
+//
+// Two buffers aren't sized by it, as they cover fixed spans: the flapping
+// detector keeps FlapWindow statuses, and the rolling availability keeps
+// the checks within the longest of AvailabilityWindows, up to
+// MaxWindowChecks.
+const DefaultHistorySize = 200
+
+// AppendRecent adds a check status to recent, dropping the oldest ones
+// beyond size
+func AppendRecent(recent []string, status string, size int) []string {
+	recent = append(recent, status)
+	if len(recent) > size {
+		recent = recent[len(recent)-size:]
+	}
+	return recent
+}
+
+const (
+	// FlapWindow is how many recent checks are considered for flapping
+	FlapWindow = 10
+	// FlapThreshold is how many status changes within the window count as flapping
+	FlapThreshold = 4
+)
+
+// AvailabilityWindows are the spans of the rolling availability figures,
+// read like load averages: recent, medium and long term
+var AvailabilityWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// MaxWindowChecks bounds the checks kept for the rolling availability:
+// the longest window at ten checks a second, far more than the refresh
+// makes, so a fast replay can't grow it without limit
+const MaxWindowChecks = 9000
+
+// checkWindow keeps timestamped check results covering the longest of
+// AvailabilityWindows, up to MaxWindowChecks of them, with running counts
+// per window so a check is added in constant time
+type checkWindow struct {
+	checks []timedCheck
+
+	// Per entry of AvailabilityWindows: the index in checks of the oldest
+	// check within it, and how many checks from there on were made and passed
+	start  []int
+	total  []int
+	passed []int
+}
+
+type timedCheck struct {
+	at time.Time
+	ok bool
+}
+
+// add records a check, drops those too old for any window and returns the
+// availability percentage over each window ending at at. Checks exactly one
+// window old have aged out. A check older than the last, e.g. after the
+// clock was set back, starts the window over.
+func (w *checkWindow) add(at time.Time, ok bool) []float64 {
+	if n := len(w.checks); n > 0 && at.Before(w.checks[n-1].at) {
+		*w = checkWindow{}
+	}
+	if w.start == nil {
+		w.start = make([]int, len(AvailabilityWindows))
+		w.total = make([]int, len(AvailabilityWindows))
+		w.passed = make([]int, len(AvailabilityWindows))
+	}
+
+	w.checks = append(w.checks, timedCheck{at: at, ok: ok})
+	oldest := len(w.checks) - MaxWindowChecks // Index of the oldest check kept
+	result := make([]float64, len(AvailabilityWindows))
+	for i, span := range AvailabilityWindows {
+		w.total[i]++
+		if ok {
+			w.passed[i]++
+		}
+		for w.start[i] < oldest || !w.checks[w.start[i]].at.After(at.Add(-span)) {
+			w.total[i]--
+			if w.checks[w.start[i]].ok {
+				w.passed[i]--
+			}
+			w.start[i]++
+		}
+		if w.total[i] > 0 {
+			result[i] = float64(w.passed[i]) * 100 / float64(w.total[i])
+		}
+	}
+
+	// Checks before the longest window are no longer needed
+	drop := slices.Min(w.start)
+	w.checks = w.checks[drop:]
+	for i := range w.start {
+		w.start[i] -= drop
+	}
+	return result
+}
+
+// clone returns a copy sharing no memory with w
+func (w checkWindow) clone() checkWindow {
+	return checkWindow{
+		checks: slices.Clone(w.checks),
+		start:  slices.Clone(w.start),
+		total:  slices.Clone(w.total),
+		passed: slices.Clone(w.passed),
+	}
+}
+
+// statusHistory keeps the most recent check statuses
+type statusHistory struct {
+	recent []string
+}
+
+// add records a status and returns the number of transitions in the window
+func (h *statusHistory) add(status string) int {
+	h.recent = append(h.recent, status)
+	if len(h.recent) > FlapWindow {
+		h.recent = h.recent[len(h.recent)-FlapWindow:]
+	}
+
+	transitions := 0
+	for i := 1; i < len(h.recent); i++ {
+		if h.recent[i] != h.recent[i-1] {
+			transitions++
+		}
+	}
+	return transitions
+}
+
+// Label returns the service name, qualified with its region when known
+func (s ServiceStatus) Label() string {
+	if s.Region == "" {
+		return s.Name
+	}
+	return s.Name + " (" + s.Region + ")"
 }
 
 // ActiveChaosTest represents a detected chaos test
 type ActiveChaosTest struct {
-	Type        string    // "region-failure", "latency", "service-outage", etc.
-	Target      string    // What is being targeted (region, service, etc.)
-	Status      string    // "active", "recovering", "completed"
-	StartTime   time.Time
-	Details     string    // Additional details about the test
-	Source      string    // "status_file", "chaos_api", "behavioral"
-	LastSeen    time.Time // When this test was last detected
+	Type      string    `json:"type"`   // "region-failure", "latency", "service-outage", etc.
+	Target    string    `json:"target"` // What is being targeted (region, service, etc.)
+	Status    string    `json:"status"` // "active", "recovering", "completed"
+	StartTime time.Time `json:"start_time"`
+	Details   string    `json:"details"`            // Additional details about the test
+	Source    string    `json:"source"`             // "status_file", "chaos_api", "behavioral"
+	LastSeen  time.Time `json:"last_seen"`          // When this test was last detected
+	EndTime   time.Time `json:"end_time,omitempty"` // When the test was seen to finish
+	Region    string    `json:"region,omitempty"`   // AWS region the test targets, if known
+
+	// Failing endpoints and services attributed to a Chaos API fault, and a
+	// summary such as "affecting US-EAST-1, Main Site"
+	Affected []string `json:"affected,omitempty"`
+	Impact   string   `json:"impact,omitempty"`
+}
+
+// Duration is how long a completed test ran
+func (t ActiveChaosTest) Duration() time.Duration {
+	if t.EndTime.IsZero() || t.EndTime.Before(t.StartTime) {
+		return 0
+	}
+	return t.EndTime.Sub(t.StartTime)
+}
+
+// FaultCount is how many Chaos API faults and effects were configured at
+// a refresh
+type FaultCount struct {
+	Time    time.Time
+	Faults  int
+	Effects int
+}
+
+// Total is the number of faults and effects together
+func (c FaultCount) Total() int {
+	return c.Faults + c.Effects
 }
 
 // MonitorState represents the complete state of the monitoring system
 type MonitorState struct {
-	ChaosAPIFaults  []ChaosAPIFault
-	ChaosAPIEffects []ChaosAPIEffect
-	NginxEndpoints  []EndpointStatus
-	AWSServices     []ServiceStatus
-	Stats           Statistics
-	LastUpdate      time.Time
-	UpdateCount     int
-	ActiveTests     []ActiveChaosTest // New field for detected chaos tests
-}
\ No newline at end of file
+	ChaosAPIFaults  []ChaosAPIFault   `json:"chaos_api_faults"`
+	ChaosAPIEffects []ChaosAPIEffect  `json:"chaos_api_effects"`
+	NginxEndpoints  []EndpointStatus  `json:"nginx_endpoints"`
+	AWSServices     []ServiceStatus   `json:"aws_services"`
+	Stats           Statistics        `json:"stats"`
+	LastUpdate      time.Time         `json:"last_update"`
+	UpdateCount     int               `json:"update_count"`
+	ActiveTests     []ActiveChaosTest `json:"active_tests"`    // New field for detected chaos tests
+	CompletedTests  []ActiveChaosTest `json:"completed_tests"` // Recently finished tests, newest first
+	ChaosIntensity  float64           `json:"chaos_intensity"` // Overall severity score, 0-100
+	LocalStack      LocalStackHealth  `json:"localstack"`
+
+	// Set when a Chaos API response matched no known shape; the faults
+	// and effects are then the last ones read successfully
+	ChaosAPIWarning string `json:"chaos_api_warning,omitempty"`
+
+	// Health of the endpoint groups set with -group
+	EndpointGroups []EndpointGroupStatus `json:"endpoint_groups,omitempty"`
+
+	// Weighted availability of all endpoints and services, see
+	// SystemAvailability; nil until one has been checked
+	SystemAvailability *float64 `json:"system_availability,omitempty"`
+}
+
+// Clone returns a deep copy of the state that shares no slices, maps or
+// stats with the original
+func (s *MonitorState) Clone() MonitorState {
+	clone := *s
+	clone.ChaosAPIFaults = slices.Clone(s.ChaosAPIFaults)
+	clone.ChaosAPIEffects = slices.Clone(s.ChaosAPIEffects)
+	clone.NginxEndpoints = slices.Clone(s.NginxEndpoints)
+	for i := range clone.NginxEndpoints {
+		clone.NginxEndpoints[i].ExpectedCodes = slices.Clone(s.NginxEndpoints[i].ExpectedCodes)
+	}
+	clone.AWSServices = slices.Clone(s.AWSServices)
+	clone.EndpointGroups = slices.Clone(s.EndpointGroups)
+	for i := range clone.EndpointGroups {
+		clone.EndpointGroups[i].Failing = slices.Clone(s.EndpointGroups[i].Failing)
+	}
+	if s.SystemAvailability != nil {
+		availability := *s.SystemAvailability
+		clone.SystemAvailability = &availability
+	}
+	clone.ActiveTests = cloneTests(s.ActiveTests)
+	clone.CompletedTests = cloneTests(s.CompletedTests)
+
+	clone.LocalStack.Services = maps.Clone(s.LocalStack.Services)
+
+	clone.Stats.NginxStats = maps.Clone(s.Stats.NginxStats)
+	for name, stats := range clone.Stats.NginxStats {
+		c := *stats
+		c.LatencyBuckets = slices.Clone(stats.LatencyBuckets)
+		c.Histogram = slices.Clone(stats.Histogram)
+		c.history.recent = slices.Clone(stats.history.recent)
+		c.window = stats.window.clone()
+		c.RollingAvailability = slices.Clone(stats.RollingAvailability)
+		c.Recent = slices.Clone(stats.Recent)
+		clone.Stats.NginxStats[name] = &c
+	}
+	clone.Stats.ServiceStats = cloneServiceStats(s.Stats.ServiceStats)
+	clone.Stats.RegionStats = maps.Clone(s.Stats.RegionStats)
+	for name, regions := range clone.Stats.RegionStats {
+		clone.Stats.RegionStats[name] = cloneServiceStats(regions)
+	}
+	return clone
+}
+
+func cloneTests(tests []ActiveChaosTest) []ActiveChaosTest {
+	clone := slices.Clone(tests)
+	for i := range clone {
+		clone[i].Affected = slices.Clone(tests[i].Affected)
+	}
+	return clone
+}
+
+func cloneServiceStats(stats map[string]*ServiceStats) map[string]*ServiceStats {
+	clone := maps.Clone(stats)
+	for name, s := range clone {
+		c := *s
+		c.history.recent = slices.Clone(s.history.recent)
+		c.window = s.window.clone()
+		c.RollingAvailability = slices.Clone(s.RollingAvailability)
+		c.Recent = slices.Clone(s.Recent)
+		clone[name] = &c
+	}
+	return clone
+}
+
+// LocalStackHealth is LocalStack's own view of its service backends, from
+// /_localstack/health
+type LocalStackHealth struct {
+	Reachable bool              `json:"reachable"`
+	Version   string            `json:"version"`
+	Edition   string            `json:"edition"`
+	Services  map[string]string `json:"services"` // Backend state, e.g. "running", "available", "starting", "error"
+}
+
+// Event records a discrete state transition observed by the monitor
+type Event struct {
+	Time     time.Time `json:"time"`
+	Severity string    `json:"severity"` // "error", "warning", "recovery", "info"
+	Kind     string    `json:"kind"`     // "endpoint", "service", "fault", "effect", "test", "export", "report", "config", "target", "reset", "maintenance"
+	Message  string    `json:"message"`
+}
+
+// ChangeSet identifies the rows that changed between two ticks
+type ChangeSet struct {
+	Endpoints map[string]bool // By endpoint name
+	Services  map[string]bool // By service label
+	Faults    map[string]bool // By fault key
+}
+
+// NewChangeSet returns an empty change set
+func NewChangeSet() ChangeSet {
+	return ChangeSet{
+		Endpoints: make(map[string]bool),
+		Services:  make(map[string]bool),
+		Faults:    make(map[string]bool),
+	}
+}
+
+// Empty reports whether nothing changed
+func (c ChangeSet) Empty() bool {
+	return len(c.Endpoints) == 0 && len(c.Services) == 0 && len(c.Faults) == 0
+}
+
+// SLO is an availability objective over a rolling window
+type SLO struct {
+	Target float64       `json:"target"` // Availability percentage, e.g. 99.9
+	Window time.Duration `json:"window"` // e.g. 30 days
+}
+
+// Enabled reports whether an SLO target has been configured
+func (s SLO) Enabled() bool {
+	return s.Target > 0 && s.Target < 100 && s.Window > 0
+}
+
+// BudgetRemaining returns the percentage of the error budget left after
+// observing availabilityPct over elapsed. The budget is the unavailability
+// the SLO allows over its window, (1 - target) * window; the burn is the
+// unavailability observed, (1 - availability) * elapsed. The result is
+// negative once the budget is overspent.
+func (s SLO) BudgetRemaining(availabilityPct float64, elapsed time.Duration) float64 {
+	budget := (1 - s.Target/100) * s.Window.Seconds()
+	burned := (1 - availabilityPct/100) * elapsed.Seconds()
+	return (1 - burned/budget) * 100
+}
+
+// CheckSample is one check result kept for the detail view
+type CheckSample struct {
+	Time         time.Time `json:"time"`
+	Status       string    `json:"status"`
+	OK           bool      `json:"ok"`
+	ResponseTime float64   `json:"response_time"`
+	Detail       string    `json:"detail"` // Failure reason or type, if any
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Some LocalStack versions encode the Chaos API's numbers as strings, e.g.
+// "probability": "0.5" or "statusCode": "503". The decoders below accept
+// either, so such a fault isn't dropped; encoding always writes numbers.
+
+// UnmarshalJSON decodes a fault whose probability and status code may be
+// numbers or numeric strings
+func (f *ChaosAPIFault) UnmarshalJSON(data []byte) error {
+	type plain ChaosAPIFault
+	aux := struct {
+		*plain
+		Probability json.RawMessage `json:"probability"`
+		Error       struct {
+			StatusCode json.RawMessage `json:"statusCode"`
+			Code       string          `json:"code"`
+			Message    string          `json:"message,omitempty"`
+		} `json:"error"`
+	}{plain: (*plain)(f)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if f.Probability, err = flexFloat(aux.Probability); err != nil {
+		return fmt.Errorf("fault probability: %w", err)
+	}
+	if f.Error.StatusCode, err = flexInt(aux.Error.StatusCode); err != nil {
+		return fmt.Errorf("fault status code: %w", err)
+	}
+	f.Error.Code, f.Error.Message = aux.Error.Code, aux.Error.Message
+	return nil
+}
+
+// UnmarshalJSON decodes an effect whose latency, jitter and probability
+// may be numbers or numeric strings
+func (e *ChaosAPIEffect) UnmarshalJSON(data []byte) error {
+	type plain ChaosAPIEffect
+	aux := struct {
+		*plain
+		Latency     json.RawMessage `json:"latency"`
+		Jitter      json.RawMessage `json:"jitter,omitempty"`
+		Probability json.RawMessage `json:"probability,omitempty"`
+	}{plain: (*plain)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if e.Latency, err = flexInt(aux.Latency); err != nil {
+		return fmt.Errorf("effect latency: %w", err)
+	}
+	if e.Jitter, err = flexInt(aux.Jitter); err != nil {
+		return fmt.Errorf("effect jitter: %w", err)
+	}
+	if e.Probability, err = flexFloat(aux.Probability); err != nil {
+		return fmt.Errorf("effect probability: %w", err)
+	}
+	return nil
+}
+
+// flexFloat decodes a JSON number or numeric string; missing, null and ""
+// are zero
+func flexFloat(raw json.RawMessage) (float64, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return 0, err
+		}
+		if s = strings.TrimSpace(s); s == "" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+	var f float64
+	err := json.Unmarshal(raw, &f)
+	return f, err
+}
+
+// flexInt decodes a whole JSON number or numeric string, like flexFloat
+func flexInt(raw json.RawMessage) (int, error) {
+	f, err := flexFloat(raw)
+	if err != nil {
+		return 0, err
+	}
+	if f != float64(int(f)) {
+		return 0, fmt.Errorf("%v is not a whole number", f)
+	}
+	return int(f), nil
+}
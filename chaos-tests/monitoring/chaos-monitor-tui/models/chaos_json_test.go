@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChaosAPIFaultNumberEncodings(t *testing.T) {
+	numbers := `{"id":"f1","service":"s3","region":"us-east-1","probability":0.5,"error":{"statusCode":503,"code":"ServiceUnavailable","message":"down"}}`
+	strs := `{"id":"f1","service":"s3","region":"us-east-1","probability":"0.5","error":{"statusCode":"503","code":"ServiceUnavailable","message":"down"}}`
+
+	var fromNumbers, fromStrings ChaosAPIFault
+	if err := json.Unmarshal([]byte(numbers), &fromNumbers); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(strs), &fromStrings); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromNumbers, fromStrings) {
+		t.Errorf("numbers decoded to %+v, strings to %+v", fromNumbers, fromStrings)
+	}
+	if fromNumbers.Probability != 0.5 || fromNumbers.Error.StatusCode != 503 || fromNumbers.Error.Message != "down" {
+		t.Errorf("decoded %+v", fromNumbers)
+	}
+
+	// Encoding writes numbers, so the result decodes the same again
+	data, err := json.Marshal(fromStrings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"probability":0.5`) || !strings.Contains(string(data), `"statusCode":503`) {
+		t.Errorf("encoded %s", data)
+	}
+	var again ChaosAPIFault
+	if err := json.Unmarshal(data, &again); err != nil || !reflect.DeepEqual(again, fromNumbers) {
+		t.Errorf("round trip = %+v, %v", again, err)
+	}
+}
+
+func TestChaosAPIEffectNumberEncodings(t *testing.T) {
+	var fromNumbers, fromStrings ChaosAPIEffect
+	if err := json.Unmarshal([]byte(`{"id":"e1","latency":500,"jitter":50,"service":"sqs","probability":0.25}`), &fromNumbers); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"e1","latency":"500","jitter":" 50 ","service":"sqs","probability":"0.25"}`), &fromStrings); err != nil {
+		t.Fatal(err)
+	}
+	want := ChaosAPIEffect{ID: "e1", Latency: 500, Jitter: 50, Service: "sqs", Probability: 0.25}
+	if fromNumbers != want || fromStrings != want {
+		t.Errorf("numbers decoded to %+v, strings to %+v, want %+v", fromNumbers, fromStrings, want)
+	}
+}
+
+func TestFlexNumbers(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{``, 0, false},
+		{`null`, 0, false},
+		{`""`, 0, false},
+		{`429`, 429, false},
+		{`"429"`, 429, false},
+		{`"1e3"`, 1000, false},
+		{`"fast"`, 0, true},
+		{`"1.5"`, 0, true},
+		{`true`, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := flexInt(json.RawMessage(tt.raw))
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("flexInt(%s) = %d, %v", tt.raw, got, err)
+		}
+	}
+
+	// A bad value fails the fault rather than decoding as zero
+	var f ChaosAPIFault
+	if err := json.Unmarshal([]byte(`{"service":"s3","probability":"half"}`), &f); err == nil || !strings.Contains(err.Error(), "fault probability") {
+		t.Errorf("bad probability: %v", err)
+	}
+}
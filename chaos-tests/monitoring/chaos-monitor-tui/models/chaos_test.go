@@ -0,0 +1,339 @@
+package models
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDowntimeUsesElapsedTime(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Checks at irregular offsets, as when probes overrun the refresh
+	checks := []struct {
+		offset time.Duration
+		ok     bool
+	}{
+		{0, false},               // First check: the nominal interval
+		{3 * time.Second, false}, // +3s
+		{4 * time.Second, true},
+		{9 * time.Second, false}, // +5s
+		{10 * time.Second, true},
+		{12 * time.Second, true},
+	}
+	const interval = 2 * time.Second
+	const want = interval + 3*time.Second + 5*time.Second
+
+	var endpoint EndpointStats
+	var service ServiceStats
+	for _, check := range checks {
+		at := start.Add(check.offset)
+		endpoint.Record(check.ok, at, interval)
+		failureType := "ok"
+		if !check.ok {
+			failureType = "service_outage"
+		}
+		service.Record(failureType, at, interval)
+	}
+	if endpoint.Downtime != want {
+		t.Errorf("endpoint downtime = %v, want %v", endpoint.Downtime, want)
+	}
+	if service.Downtime != want {
+		t.Errorf("service downtime = %v, want %v", service.Downtime, want)
+	}
+}
+
+func TestFlapping(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var stats EndpointStats
+	record := func(i int, ok bool) {
+		stats.Record(ok, start.Add(time.Duration(i)*2*time.Second), 2*time.Second)
+	}
+
+	// Alternating statuses flap once FlapThreshold changes are in the window
+	for i := 0; i <= FlapThreshold; i++ {
+		record(i, i%2 == 0)
+		if want := i >= FlapThreshold; stats.Flapping != want || stats.Transitions != i {
+			t.Fatalf("after %d alternating checks: %d transitions, flapping %v", i+1, stats.Transitions, stats.Flapping)
+		}
+	}
+
+	// A steady run pushes the changes out of the window
+	for i := FlapThreshold + 1; i < FlapThreshold+1+FlapWindow; i++ {
+		record(i, true)
+	}
+	if stats.Flapping || stats.Transitions != 0 {
+		t.Errorf("after a steady run: %d transitions, flapping %v", stats.Transitions, stats.Flapping)
+	}
+
+	// Services count changes between failure types too
+	var service ServiceStats
+	for i, failureType := range []string{"ok", "throttled", "service_outage", "throttled", "ok"} {
+		service.Record(failureType, start.Add(time.Duration(i)*2*time.Second), 2*time.Second)
+	}
+	if service.Transitions != 4 || !service.Flapping {
+		t.Errorf("service: %d transitions, flapping %v", service.Transitions, service.Flapping)
+	}
+}
+
+func TestBudgetRemaining(t *testing.T) {
+	slo := SLO{Target: 99.9, Window: 30 * 24 * time.Hour}
+	// 0.1% of 30 days is 43.2 minutes of budget
+	tests := []struct {
+		name         string
+		availability float64
+		elapsed      time.Duration
+		want         float64
+	}{
+		{"fully available", 100, 30 * 24 * time.Hour, 100},
+		{"nothing elapsed", 0, 0, 100},
+		{"at the target for the whole window", 99.9, 30 * 24 * time.Hour, 0},
+		{"half the budget", 99.95, 30 * 24 * time.Hour, 50},
+		{"down for exactly the budget", 0, 43*time.Minute + 12*time.Second, 0},
+		{"overspent", 0, 86*time.Minute + 24*time.Second, -100},
+	}
+	for _, tt := range tests {
+		if got := slo.BudgetRemaining(tt.availability, tt.elapsed); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s: BudgetRemaining = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, slo := range []SLO{{}, {Target: 100, Window: time.Hour}, {Target: 99, Window: 0}} {
+		if slo.Enabled() {
+			t.Errorf("%+v is enabled", slo)
+		}
+	}
+}
+
+func TestLatencyHistogramBoundaries(t *testing.T) {
+	bounds := []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second}
+	tests := []struct {
+		responseTime time.Duration
+		want         int
+	}{
+		{0, 0},
+		{10*time.Millisecond - time.Nanosecond, 0},
+		{10 * time.Millisecond, 1}, // A bound belongs to the bucket above it
+		{99 * time.Millisecond, 1},
+		{100 * time.Millisecond, 2},
+		{time.Second - time.Microsecond, 2},
+		{time.Second, 3},
+		{time.Hour, 3},
+	}
+	for _, tt := range tests {
+		if got := BucketIndex(bounds, tt.responseTime); got != tt.want {
+			t.Errorf("BucketIndex(%v) = %d, want %d", tt.responseTime, got, tt.want)
+		}
+	}
+
+	var stats EndpointStats
+	for _, seconds := range []float64{0.005, 0.01, 0.05, 0.5, 1, 2} {
+		stats.RecordLatency(seconds, bounds)
+	}
+	if want := []int{1, 2, 1, 2}; !slices.Equal(stats.Histogram, want) {
+		t.Errorf("histogram = %v, want %v", stats.Histogram, want)
+	}
+	if !slices.Equal(stats.LatencyBuckets, bounds) {
+		t.Errorf("buckets = %v", stats.LatencyBuckets)
+	}
+}
+
+func TestUpdateEMA(t *testing.T) {
+	const alpha = 0.3
+	ema := UpdateEMA(0, 0.2, alpha, true)
+	if ema != 0.2 {
+		t.Fatalf("first sample gives %v, want it to seed the average", ema)
+	}
+
+	// A step from 0.2s to 1s closes 30% of the remaining gap each check
+	gap := 0.8
+	for i := 1; i <= 5; i++ {
+		ema = UpdateEMA(ema, 1, alpha, false)
+		gap *= 1 - alpha
+		if math.Abs((1-ema)-gap) > 1e-9 {
+			t.Fatalf("after %d checks ema = %v, want %v", i, ema, 1-gap)
+		}
+	}
+
+	// and converges on a steady value
+	for i := 0; i < 100; i++ {
+		ema = UpdateEMA(ema, 1, alpha, false)
+	}
+	if math.Abs(ema-1) > 1e-9 {
+		t.Errorf("ema = %v after a long steady run, want 1", ema)
+	}
+
+	if got := UpdateEMA(0.5, 1, 1, false); got != 1 {
+		t.Errorf("alpha 1 gives %v, want the latest sample", got)
+	}
+}
+
+func TestRollingAvailability(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	const interval = 30 * time.Second
+	var stats EndpointStats
+
+	// Twenty minutes of checks: failing for the first ten, then passing
+	// apart from one blip thirty seconds before the end
+	for at := time.Duration(0); at <= 20*time.Minute; at += interval {
+		ok := at >= 10*time.Minute && at != 19*time.Minute+30*time.Second
+		stats.Record(ok, start.Add(at), interval)
+		if at == 0 && !slices.Equal(stats.RollingAvailability, []float64{0, 0, 0}) {
+			t.Errorf("after one failure = %v", stats.RollingAvailability)
+		}
+	}
+
+	// 1m: 19:30 and 20:00, the check at 19:00 has aged out
+	// 5m: 15:30 to 20:00, one failure in ten
+	// 15m: 5:30 to 20:00, nine failures before 10:00 plus the blip in thirty
+	want := []float64{50, 90, 200.0 / 3}
+	for i, got := range stats.RollingAvailability {
+		if math.Abs(got-want[i]) > 1e-9 {
+			t.Errorf("%v availability = %v, want %v", AvailabilityWindows[i], got, want[i])
+		}
+	}
+}
+
+func TestCheckRate(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		count int
+		last  time.Time
+		want  float64
+	}{
+		{0, start, 0},
+		{1, start, 0},
+		{2, start, 0}, // No time between them to measure
+		{2, start.Add(-time.Second), 0},
+		{2, start.Add(2 * time.Second), 0.5},
+		{31, start.Add(time.Minute), 0.5},
+	}
+	for _, tt := range tests {
+		if got := CheckRate(tt.count, start, tt.last); got != tt.want {
+			t.Errorf("CheckRate(%d, +%v) = %v, want %v", tt.count, tt.last.Sub(start), got, tt.want)
+		}
+	}
+
+	// Probes overrunning a 2s refresh: eleven checks over 30s
+	var endpoint EndpointStats
+	var service ServiceStats
+	for i := 0; i <= 10; i++ {
+		at := start.Add(time.Duration(i) * 3 * time.Second)
+		endpoint.Record(true, at, 2*time.Second)
+		service.Record("ok", at, 2*time.Second)
+	}
+	if got := endpoint.Rate(); math.Abs(got-1.0/3) > 1e-9 {
+		t.Errorf("endpoint rate = %v, want 1/3", got)
+	}
+	if got := service.Rate(); math.Abs(got-1.0/3) > 1e-9 {
+		t.Errorf("service rate = %v, want 1/3", got)
+	}
+}
+
+func TestLastSuccessOnlyAdvancesOnSuccess(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var endpoint EndpointStats
+	var service ServiceStats
+	check := func(offset time.Duration, ok bool, wantLastOK time.Duration, wantNone bool) {
+		t.Helper()
+		at := start.Add(offset)
+		endpoint.Record(ok, at, 2*time.Second)
+		failureType := "ok"
+		if !ok {
+			failureType = "throttled"
+		}
+		service.Record(failureType, at, 2*time.Second)
+
+		for name, got := range map[string]time.Time{"endpoint": endpoint.LastSuccess, "service": service.LastSuccess} {
+			if wantNone {
+				if !got.IsZero() {
+					t.Errorf("+%v: %s last success = %v, want none", offset, name, got)
+				}
+			} else if want := start.Add(wantLastOK); !got.Equal(want) {
+				t.Errorf("+%v: %s last success = %v, want %v", offset, name, got, want)
+			}
+		}
+	}
+
+	check(0, false, 0, true) // Never passed yet
+	check(2*time.Second, true, 2*time.Second, false)
+	check(4*time.Second, true, 4*time.Second, false)
+	check(6*time.Second, false, 4*time.Second, false)
+	check(8*time.Second, false, 4*time.Second, false)
+	check(10*time.Second, true, 10*time.Second, false)
+}
+
+func TestConsecutiveFailuresReset(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var endpoint EndpointStats
+	var service ServiceStats
+	results := []struct {
+		failureType string
+		want        int
+	}{
+		{"service_outage", 1},
+		{"throttled", 2},
+		{"resource_exhausted", 3},
+		{"ok", 0},
+		{"ok", 0},
+		{"service_outage", 1},
+		{"ok", 0},
+	}
+	for i, r := range results {
+		at := start.Add(time.Duration(i) * 2 * time.Second)
+		endpoint.Record(r.failureType == "ok", at, 2*time.Second)
+		service.Record(r.failureType, at, 2*time.Second)
+		if endpoint.ConsecutiveFailures != r.want || service.ConsecutiveFailures != r.want {
+			t.Errorf("check %d (%s): endpoint streak %d, service streak %d, want %d",
+				i, r.failureType, endpoint.ConsecutiveFailures, service.ConsecutiveFailures, r.want)
+		}
+	}
+}
+
+func TestCheckWindowBounded(t *testing.T) {
+	var stats EndpointStats
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Checks every 10ms keep well over MaxWindowChecks within 15 minutes
+	for i := 0; i < MaxWindowChecks+10; i++ {
+		stats.Record(i%2 == 0, start.Add(time.Duration(i)*10*time.Millisecond), 10*time.Millisecond)
+	}
+	if n := len(stats.window.checks); n != MaxWindowChecks {
+		t.Errorf("window keeps %d checks, want %d", n, MaxWindowChecks)
+	}
+	if !slices.Equal(stats.RollingAvailability, []float64{50, 50, 50}) {
+		t.Errorf("rolling availability = %v", stats.RollingAvailability)
+	}
+	if n := len(stats.history.recent); n != FlapWindow {
+		t.Errorf("flap history keeps %d statuses, want %d", n, FlapWindow)
+	}
+
+	// At the refresh rate the window is bounded by time instead
+	stats = EndpointStats{}
+	longest := AvailabilityWindows[len(AvailabilityWindows)-1]
+	for i := 0; i < int(longest/(2*time.Second))+10; i++ {
+		stats.Record(i < 10, start.Add(time.Duration(i)*2*time.Second), 2*time.Second)
+	}
+	if n, want := len(stats.window.checks), int(longest/(2*time.Second)); n != want {
+		t.Errorf("window keeps %d checks, want %d", n, want)
+	}
+	if got := stats.RollingAvailability; got[0] != 0 || got[2] != 0 {
+		t.Errorf("rolling availability = %v, want the passing checks aged out", got)
+	}
+
+	// A check from before the last starts the window over
+	stats.Record(true, start, 2*time.Second)
+	if n := len(stats.window.checks); n != 1 || !slices.Equal(stats.RollingAvailability, []float64{100, 100, 100}) {
+		t.Errorf("after the clock went back: %d checks, rolling availability %v", n, stats.RollingAvailability)
+	}
+}
+
+func TestAppendRecent(t *testing.T) {
+	var recent []string
+	for i := 0; i < 10; i++ {
+		recent = AppendRecent(recent, "ok", 3)
+	}
+	recent = AppendRecent(recent, "failed", 3)
+	if len(recent) != 3 || recent[2] != "failed" {
+		t.Errorf("recent = %v", recent)
+	}
+}
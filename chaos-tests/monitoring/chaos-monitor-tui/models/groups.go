@@ -0,0 +1,65 @@
+package models
+
+import "sort"
+
+// EndpointGroupStatus is the health of a named group of endpoints that
+// serve one logical service, such as the regional sites behind a domain
+type EndpointGroupStatus struct {
+	Name    string   `json:"name"`
+	Health  string   `json:"health"` // "healthy", "degraded" or "down"
+	Up      int      `json:"up"`
+	Total   int      `json:"total"`
+	Failing []string `json:"failing,omitempty"` // Members that aren't available
+}
+
+// GroupHealth is "healthy" when every member is up, "down" when none is
+// and "degraded" in between
+func GroupHealth(up, total int) string {
+	switch {
+	case up == total:
+		return "healthy"
+	case up == 0:
+		return "down"
+	default:
+		return "degraded"
+	}
+}
+
+// EndpointGroups computes the health of each group from the endpoints'
+// latest checks, by group name. Members that weren't checked are left out,
+// and a group with none checked is skipped.
+func EndpointGroups(endpoints []EndpointStatus, groups map[string][]string) []EndpointGroupStatus {
+	byName := make(map[string]EndpointStatus, len(endpoints))
+	for _, endpoint := range endpoints {
+		byName[endpoint.Name] = endpoint
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var statuses []EndpointGroupStatus
+	for _, name := range names {
+		group := EndpointGroupStatus{Name: name}
+		for _, member := range groups[name] {
+			endpoint, ok := byName[member]
+			if !ok {
+				continue
+			}
+			group.Total++
+			if endpoint.Available() {
+				group.Up++
+			} else {
+				group.Failing = append(group.Failing, member)
+			}
+		}
+		if group.Total == 0 {
+			continue
+		}
+		group.Health = GroupHealth(group.Up, group.Total)
+		statuses = append(statuses, group)
+	}
+	return statuses
+}
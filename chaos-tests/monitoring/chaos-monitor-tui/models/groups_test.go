@@ -0,0 +1,40 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEndpointGroups(t *testing.T) {
+	groups := map[string][]string{"Regions": {"US-EAST-1", "US-EAST-2", "EU-WEST-1"}}
+	tests := []struct {
+		statuses [3]string
+		want     EndpointGroupStatus
+	}{
+		{[3]string{"ok", "ok", "ok"}, EndpointGroupStatus{Health: "healthy", Up: 3}},
+		{[3]string{"ok", "failed", "ok"}, EndpointGroupStatus{Health: "degraded", Up: 2, Failing: []string{"US-EAST-2"}}},
+		{[3]string{"serving-fallback", "timeout", "failed"}, EndpointGroupStatus{Health: "degraded", Up: 1, Failing: []string{"US-EAST-2", "EU-WEST-1"}}},
+		{[3]string{"failed", "timeout", "unexpected-status"}, EndpointGroupStatus{Health: "down", Failing: []string{"US-EAST-1", "US-EAST-2", "EU-WEST-1"}}},
+	}
+	for _, tt := range tests {
+		var endpoints []EndpointStatus
+		for i, status := range tt.statuses {
+			endpoints = append(endpoints, EndpointStatus{Name: groups["Regions"][i], Status: status})
+		}
+		endpoints = append(endpoints, EndpointStatus{Name: "Other", Status: "failed"})
+
+		got := EndpointGroups(endpoints, groups)
+		want := tt.want
+		want.Name, want.Total = "Regions", 3
+		if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+			t.Errorf("%v: got %+v, want %+v", tt.statuses, got, want)
+		}
+	}
+
+	// Members that weren't checked don't count, and a group with none is skipped
+	groups["Empty"] = []string{"Missing"}
+	got := EndpointGroups([]EndpointStatus{{Name: "US-EAST-1", Status: "ok"}, {Name: "EU-WEST-1", Status: "failed"}}, groups)
+	if len(got) != 1 || got[0].Up != 1 || got[0].Total != 2 || got[0].Health != "degraded" {
+		t.Errorf("with a member unchecked: %+v", got)
+	}
+}
@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// CascadeOptions configures behavioral cascade-failure detection
+type CascadeOptions struct {
+	Window      time.Duration // How soon after the first failure the others must follow
+	MinAffected int           // Failing services, including the first, that make a cascade
+}
+
+// DefaultCascadeOptions call three services failing within 30s a cascade
+var DefaultCascadeOptions = CascadeOptions{
+	Window:      30 * time.Second,
+	MinAffected: 3,
+}
+
+// CascadeDetector infers cascade failures from the order in which services
+// start failing. It keeps state between ticks, so use one per target.
+type CascadeDetector struct {
+	opts         CascadeOptions
+	failingSince map[string]time.Time // When each currently failing service started failing, by label
+}
+
+// NewCascadeDetector returns a detector with nothing failing
+func NewCascadeDetector(opts CascadeOptions) *CascadeDetector {
+	return &CascadeDetector{opts: opts, failingSince: make(map[string]time.Time)}
+}
+
+// cascadeStatuses are the service statuses that can propagate a cascade
+var cascadeStatuses = map[string]bool{
+	"outage":    true,
+	"throttled": true,
+}
+
+// Observe records the services' statuses as of at, and returns a
+// cascade-failure test when at least MinAffected services are failing and
+// each started within Window of the first, or nil otherwise
+func (d *CascadeDetector) Observe(services []models.ServiceStatus, at time.Time) *models.ActiveChaosTest {
+	failing := make(map[string]bool)
+	for _, service := range services {
+		label := service.Label()
+		if !cascadeStatuses[service.Status] {
+			continue
+		}
+		failing[label] = true
+		if _, ok := d.failingSince[label]; !ok {
+			d.failingSince[label] = at
+		}
+	}
+	for label := range d.failingSince {
+		if !failing[label] {
+			delete(d.failingSince, label)
+		}
+	}
+
+	// Order by when each service started failing; ties are broken by name
+	// so the propagation order is stable between ticks
+	order := make([]string, 0, len(d.failingSince))
+	for label := range d.failingSince {
+		order = append(order, label)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := d.failingSince[order[i]], d.failingSince[order[j]]
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return order[i] < order[j]
+	})
+
+	var chain []string
+	for _, label := range order {
+		if len(chain) > 0 && d.failingSince[label].Sub(d.failingSince[chain[0]]) > d.opts.Window {
+			break
+		}
+		chain = append(chain, label)
+	}
+	if len(chain) < d.opts.MinAffected || d.opts.MinAffected < 1 {
+		return nil
+	}
+
+	start := d.failingSince[chain[0]]
+	spread := d.failingSince[chain[len(chain)-1]].Sub(start)
+	return &models.ActiveChaosTest{
+		Type:      "cascade-failure",
+		Target:    chain[0],
+		Status:    "active",
+		StartTime: start,
+		Details:   fmt.Sprintf("%s (%d services within %s)", strings.Join(chain, " → "), len(chain), spread.Round(time.Second)),
+		Source:    "behavioral",
+		LastSeen:  at,
+		Affected:  chain,
+	}
+}
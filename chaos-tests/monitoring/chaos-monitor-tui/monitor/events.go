@@ -0,0 +1,230 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// DiffStates compares two consecutive monitor states and returns an event for
+// every transition: endpoints or services changing status, faults and effects
+// being added or removed, and chaos tests being detected or completing.
+func DiffStates(prev, curr *models.MonitorState) []models.Event {
+	var events []models.Event
+	now := curr.LastUpdate
+
+	add := func(severity, kind, format string, args ...interface{}) {
+		events = append(events, models.Event{
+			Time:     now,
+			Severity: severity,
+			Kind:     kind,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// Endpoints
+	prevEndpoints := make(map[string]string)
+	for _, endpoint := range prev.NginxEndpoints {
+		prevEndpoints[endpoint.Name] = endpoint.Status
+	}
+	for _, endpoint := range curr.NginxEndpoints {
+		before, seen := prevEndpoints[endpoint.Name]
+		switch {
+		case seen && before == endpoint.Status:
+		case endpoint.Status == "ok":
+			if seen {
+				add("recovery", "endpoint", "%s is back up", endpoint.Name)
+			}
+		case endpoint.Status == "throttled":
+			add("warning", "endpoint", "%s is throttled", endpoint.Name)
+		case endpoint.Status == "serving-fallback":
+			add("warning", "endpoint", "%s failed over: %s", endpoint.Name, endpoint.Reason)
+		default:
+			add("error", "endpoint", "%s went down (%s)", endpoint.Name, endpoint.Status)
+		}
+	}
+
+	// AWS services
+	prevServices := make(map[string]string)
+	for _, service := range prev.AWSServices {
+		prevServices[service.Label()] = service.Status
+	}
+	for _, service := range curr.AWSServices {
+		before, seen := prevServices[service.Label()]
+		switch {
+		case seen && before == service.Status:
+		case service.Status == "healthy":
+			if seen {
+				add("recovery", "service", "%s recovered", service.Label())
+			}
+		case service.Status == "throttled":
+			add("warning", "service", "%s is throttled", service.Label())
+		case service.Status == "unavailable":
+			add("warning", "service", "%s can't be checked: docker is not available", service.Label())
+		default:
+			add("error", "service", "%s is in %s", service.Label(), service.Status)
+		}
+	}
+
+	// Chaos API faults
+	prevFaults := make(map[string]bool)
+	for _, fault := range prev.ChaosAPIFaults {
+		prevFaults[fault.Key()] = true
+	}
+	currFaults := make(map[string]bool)
+	for _, fault := range curr.ChaosAPIFaults {
+		key := fault.Key()
+		currFaults[key] = true
+		if !prevFaults[key] {
+			add("warning", "fault", "Fault added: %s (%s) at %.0f%%", fault.Service, fault.Region, fault.Probability*100)
+		}
+	}
+	for _, fault := range prev.ChaosAPIFaults {
+		if !currFaults[fault.Key()] {
+			add("recovery", "fault", "Fault removed: %s (%s)", fault.Service, fault.Region)
+		}
+	}
+
+	// Chaos API effects
+	prevEffects := make(map[string]bool)
+	for _, effect := range prev.ChaosAPIEffects {
+		prevEffects[effectKey(effect)] = true
+	}
+	currEffects := make(map[string]bool)
+	for _, effect := range curr.ChaosAPIEffects {
+		key := effectKey(effect)
+		currEffects[key] = true
+		if !prevEffects[key] {
+			add("warning", "effect", "Network effect added: %s on %s", effect.Summary(), effect.Target())
+		}
+	}
+	for _, effect := range prev.ChaosAPIEffects {
+		if !currEffects[effectKey(effect)] {
+			add("recovery", "effect", "Network effect removed: %s on %s", effect.Summary(), effect.Target())
+		}
+	}
+
+	// Chaos tests
+	prevTests := make(map[string]string)
+	for _, test := range prev.ActiveTests {
+		prevTests[testKey(test)] = test.Status
+	}
+	currTests := make(map[string]bool)
+	for _, test := range curr.ActiveTests {
+		key := testKey(test)
+		currTests[key] = true
+		before, seen := prevTests[key]
+		switch {
+		case !seen && test.Status != "completed":
+			add("warning", "test", "Chaos test detected: %s on %s", test.Type, test.Target)
+		case test.Status == "completed" && before != "completed":
+			add("info", "test", "Chaos test completed: %s on %s", test.Type, test.Target)
+		}
+	}
+	for _, test := range prev.ActiveTests {
+		if !currTests[testKey(test)] && test.Status != "completed" {
+			add("info", "test", "Chaos test completed: %s on %s", test.Type, test.Target)
+		}
+	}
+
+	return events
+}
+
+func effectKey(effect models.ChaosAPIEffect) string {
+	if effect.ID != "" {
+		return effect.ID
+	}
+	return fmt.Sprint(effect.Latency, "|", effect.Jitter, "|", effect.Probability, "|", effect.Target())
+}
+
+// EndedTests returns the tests in prev that have since disappeared or
+// flipped to "completed", marked completed at now
+func EndedTests(prev, curr []models.ActiveChaosTest, now time.Time) []models.ActiveChaosTest {
+	currTests := make(map[string]models.ActiveChaosTest)
+	for _, test := range curr {
+		currTests[testKey(test)] = test
+	}
+
+	var ended []models.ActiveChaosTest
+	for _, test := range prev {
+		if test.Status == "completed" {
+			continue
+		}
+		if current, seen := currTests[testKey(test)]; seen {
+			if current.Status != "completed" {
+				continue
+			}
+			test = current
+		}
+		test.Status = "completed"
+		test.EndTime = now
+		ended = append(ended, test)
+	}
+	return ended
+}
+
+// KeepStartTimes carries start times over from the previous detection, so
+// tests that are re-detected every tick keep their original start
+func KeepStartTimes(prev, curr []models.ActiveChaosTest) {
+	started := make(map[string]time.Time)
+	for _, test := range prev {
+		started[testKey(test)] = test.StartTime
+	}
+	for i, test := range curr {
+		if start, ok := started[testKey(test)]; ok && !start.IsZero() && start.Before(test.StartTime) {
+			curr[i].StartTime = start
+		}
+	}
+}
+
+func testKey(test models.ActiveChaosTest) string {
+	return test.Type + "|" + test.Target
+}
+
+// AppendEvents adds events to a log, dropping the oldest beyond limit
+func AppendEvents(log []models.Event, events []models.Event, limit int) []models.Event {
+	log = append(log, events...)
+	if len(log) > limit {
+		log = append([]models.Event(nil), log[len(log)-limit:]...)
+	}
+	return log
+}
+
+// ChangedRows identifies the endpoints, services and faults whose status
+// differs from the previous tick, including ones that just appeared
+func ChangedRows(prev, curr *models.MonitorState) models.ChangeSet {
+	changes := models.NewChangeSet()
+
+	prevEndpoints := make(map[string]string)
+	for _, endpoint := range prev.NginxEndpoints {
+		prevEndpoints[endpoint.Name] = endpoint.Status
+	}
+	for _, endpoint := range curr.NginxEndpoints {
+		if before, seen := prevEndpoints[endpoint.Name]; !seen || before != endpoint.Status {
+			changes.Endpoints[endpoint.Name] = true
+		}
+	}
+
+	prevServices := make(map[string]string)
+	for _, service := range prev.AWSServices {
+		prevServices[service.Label()] = service.FailureType
+	}
+	for _, service := range curr.AWSServices {
+		if before, seen := prevServices[service.Label()]; !seen || before != service.FailureType {
+			changes.Services[service.Label()] = true
+		}
+	}
+
+	prevFaults := make(map[string]bool)
+	for _, fault := range prev.ChaosAPIFaults {
+		prevFaults[fault.Key()] = true
+	}
+	for _, fault := range curr.ChaosAPIFaults {
+		if !prevFaults[fault.Key()] {
+			changes.Faults[fault.Key()] = true
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"regexp"
+	"strings"
+
+	"chaos-monitor-tui/models"
+)
+
+// NoImpact describes a fault whose effects haven't been observed yet
+const NoImpact = "configured, no impact observed"
+
+// endpointBackends are the services the nginx endpoints are served through;
+// a fault in one of them can take the endpoints down
+var endpointBackends = map[string]bool{"s3": true, "elbv2": true, "elb": true, "route53": true}
+
+// regionPattern finds an AWS region in an endpoint name such as "US-EAST-1"
+var regionPattern = regexp.MustCompile(`(?i)\b[a-z]{2}-[a-z]+-\d\b`)
+
+// targetRegion returns the AWS region named in a test target, lowercased,
+// or "" when there is none
+func targetRegion(target string) string {
+	return strings.ToLower(regionPattern.FindString(target))
+}
+
+// AffectedBy returns the failing endpoints and services this tick that the
+// fault could account for. Services match on name and region; endpoints
+// match faults in their backends, either in the region named in the
+// endpoint or, for endpoints without a region, in any region. An empty
+// service or region in the fault matches everything.
+func AffectedBy(fault models.ChaosAPIFault, state *models.MonitorState) []string {
+	var affected []string
+
+	if fault.Service == "" || endpointBackends[strings.ToLower(fault.Service)] {
+		for _, endpoint := range state.NginxEndpoints {
+			if endpoint.Status == "ok" {
+				continue
+			}
+			region := regionPattern.FindString(endpoint.Name)
+			if region == "" || regionMatches(fault.Region, region) {
+				affected = append(affected, endpoint.Name)
+			}
+		}
+	}
+
+	for _, service := range state.AWSServices {
+		if service.Status == "healthy" || service.FailureType == "docker_unavailable" {
+			continue
+		}
+		if fault.Service != "" && !strings.EqualFold(fault.Service, service.Name) {
+			continue
+		}
+		if service.Region != "" && !regionMatches(fault.Region, service.Region) {
+			continue
+		}
+		affected = append(affected, service.Label())
+	}
+	return affected
+}
+
+// regionMatches reports whether a fault region covers region
+func regionMatches(faultRegion, region string) bool {
+	return faultRegion == "" || strings.EqualFold(faultRegion, region)
+}
+
+// ImpactLabel summarizes the affected rows for display
+func ImpactLabel(affected []string) string {
+	if len(affected) == 0 {
+		return NoImpact
+	}
+	return "affecting " + strings.Join(affected, ", ")
+}
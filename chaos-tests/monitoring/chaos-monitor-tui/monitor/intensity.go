@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"chaos-monitor-tui/models"
+)
+
+// IntensityWeights sets how much each input contributes to the chaos
+// intensity score. Only the ratios matter; the score is normalized by the sum.
+type IntensityWeights struct {
+	Faults    float64 // Combined probability of hitting an active fault
+	Latency   float64 // Largest injected latency relative to LatencyCeiling
+	Endpoints float64 // Fraction of endpoints unavailable
+	Services  float64 // Fraction of services in outage
+}
+
+// DefaultIntensityWeights weigh observed impact slightly above injected chaos
+var DefaultIntensityWeights = IntensityWeights{
+	Faults:    0.25,
+	Latency:   0.15,
+	Endpoints: 0.30,
+	Services:  0.30,
+}
+
+// LatencyCeiling is the injected latency, in milliseconds, that counts as
+// maximal for the score
+const LatencyCeiling = 5000
+
+// ChaosIntensity scores the overall severity of the chaos in state from 0
+// (nothing injected or failing) to 100. Each input is normalized to 0-1:
+//
+//	faults    = 1 - Π(1 - probability)  for each active fault
+//	latency   = min(max latency / LatencyCeiling, 1)
+//	endpoints = unavailable endpoints / endpoints
+//	services  = services in outage / services checked
+//
+// and the score is 100 * Σ(weight * input) / Σ(weight). Adding a fault,
+// raising a probability or latency, or another failure never lowers it.
+func ChaosIntensity(state *models.MonitorState, w IntensityWeights) float64 {
+	total := w.Faults + w.Latency + w.Endpoints + w.Services
+	if total <= 0 {
+		return 0
+	}
+
+	// Probability that a request hits at least one fault
+	unaffected := 1.0
+	for _, fault := range state.ChaosAPIFaults {
+		p := fault.Probability
+		if p < 0 {
+			p = 0
+		} else if p > 1 {
+			p = 1
+		}
+		unaffected *= 1 - p
+	}
+	faults := 1 - unaffected
+
+	latency := 0.0
+	for _, effect := range state.ChaosAPIEffects {
+		if l := float64(effect.Latency) / LatencyCeiling; l > latency {
+			latency = l
+		}
+	}
+	if latency > 1 {
+		latency = 1
+	}
+
+	endpoints := 0.0
+	if len(state.NginxEndpoints) > 0 {
+		failing := 0
+		for _, endpoint := range state.NginxEndpoints {
+			if !endpoint.Available() {
+				failing++
+			}
+		}
+		endpoints = float64(failing) / float64(len(state.NginxEndpoints))
+	}
+
+	services := 0.0
+	checked, outages := 0, 0
+	for _, service := range state.AWSServices {
+		if service.Status == "unavailable" {
+			continue
+		}
+		checked++
+		if service.Status == "outage" {
+			outages++
+		}
+	}
+	if checked > 0 {
+		services = float64(outages) / float64(checked)
+	}
+
+	score := w.Faults*faults + w.Latency*latency + w.Endpoints*endpoints + w.Services*services
+	return score / total * 100
+}
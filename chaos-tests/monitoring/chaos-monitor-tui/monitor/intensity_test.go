@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"testing"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestChaosIntensity(t *testing.T) {
+	state := &models.MonitorState{
+		NginxEndpoints: []models.EndpointStatus{{Name: "Main Site", Status: "ok"}, {Name: "API", Status: "ok"}},
+		AWSServices: []models.ServiceStatus{
+			{Name: "SQS", Status: "healthy"},
+			{Name: "S3", Status: "healthy"},
+			{Name: "Lambda", Status: "unavailable"}, // Not checked, so never counted
+		},
+		ChaosAPIFaults: []models.ChaosAPIFault{{Service: "sqs", Probability: -0.5}},
+	}
+	if got := ChaosIntensity(state, DefaultIntensityWeights); got != 0 {
+		t.Fatalf("no chaos: intensity = %v, want 0", got)
+	}
+	// An endpoint serving its fallback region's content is still available
+	state.NginxEndpoints[1].Status = "serving-fallback"
+	if got := ChaosIntensity(state, DefaultIntensityWeights); got != 0 {
+		t.Fatalf("serving fallback: intensity = %v, want 0", got)
+	}
+
+	// Each step raises one input, so the score must rise and stay in 0-100
+	steps := []struct {
+		name  string
+		apply func(*models.MonitorState)
+	}{
+		{"add a fault", func(s *models.MonitorState) {
+			s.ChaosAPIFaults = append(s.ChaosAPIFaults, models.ChaosAPIFault{Service: "s3", Probability: 0.2})
+		}},
+		{"raise a fault's probability", func(s *models.MonitorState) { s.ChaosAPIFaults[1].Probability = 0.6 }},
+		{"add another fault", func(s *models.MonitorState) {
+			s.ChaosAPIFaults = append(s.ChaosAPIFaults, models.ChaosAPIFault{Service: "lambda", Probability: 0.5})
+		}},
+		{"inject latency", func(s *models.MonitorState) {
+			s.ChaosAPIEffects = append(s.ChaosAPIEffects, models.ChaosAPIEffect{ID: "slow", Latency: 1000})
+		}},
+		{"raise latency", func(s *models.MonitorState) { s.ChaosAPIEffects[0].Latency = 3000 }},
+		{"fail an endpoint", func(s *models.MonitorState) { s.NginxEndpoints[0].Status = "failed" }},
+		{"fail another endpoint", func(s *models.MonitorState) { s.NginxEndpoints[1].Status = "timeout" }},
+		{"take a service out", func(s *models.MonitorState) { s.AWSServices[0].Status = "outage" }},
+		{"take another service out", func(s *models.MonitorState) { s.AWSServices[1].Status = "outage" }},
+		{"push a probability past 1", func(s *models.MonitorState) { s.ChaosAPIFaults[1].Probability = 1.5 }},
+		{"push latency past the ceiling", func(s *models.MonitorState) { s.ChaosAPIEffects[0].Latency = 4 * LatencyCeiling }},
+	}
+	prev := 0.0
+	for _, step := range steps {
+		step.apply(state)
+		got := ChaosIntensity(state, DefaultIntensityWeights)
+		if got <= prev || got < 0 || got > 100 {
+			t.Errorf("%s: intensity = %v after %v", step.name, got, prev)
+		}
+		prev = got
+	}
+	if prev != 100 {
+		t.Errorf("everything maxed: intensity = %v, want 100", prev)
+	}
+
+	// Inputs already at their maximum can't push the score past 100
+	state.ChaosAPIFaults = append(state.ChaosAPIFaults, models.ChaosAPIFault{Service: "sns", Probability: 2})
+	state.ChaosAPIEffects = append(state.ChaosAPIEffects, models.ChaosAPIEffect{ID: "slower", Latency: 10 * LatencyCeiling})
+	if got := ChaosIntensity(state, DefaultIntensityWeights); got != 100 {
+		t.Errorf("beyond the maximum: intensity = %v, want 100", got)
+	}
+
+	if got := ChaosIntensity(state, IntensityWeights{}); got != 0 {
+		t.Errorf("zero weights: intensity = %v, want 0", got)
+	}
+}
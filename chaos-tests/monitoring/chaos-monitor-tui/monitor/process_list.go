@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessInfo describes a running process
+type ProcessInfo struct {
+	PID       int
+	PPID      int      // Parent process ID, or 0 if unknown
+	Args      []string // The command line's arguments, starting with the command
+	Cmdline   string   // Args joined with spaces
+	StartTime time.Time
+}
+
+// ProcessLister enumerates running processes
+type ProcessLister interface {
+	List() ([]ProcessInfo, error)
+}
+
+// clockTicks is the kernel USER_HZ used for /proc/<pid>/stat times. It is
+// 100 on practically every Linux system.
+const clockTicks = 100
+
+// procLister lists processes by reading the Linux /proc filesystem
+type procLister struct {
+	root string
+}
+
+// List returns every process whose command line can be read
+func (l procLister) List() ([]ProcessInfo, error) {
+	entries, err := os.ReadDir(l.root)
+	if err != nil {
+		return nil, err
+	}
+
+	bootTime, err := l.bootTime()
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []ProcessInfo
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		// Processes can exit between listing and reading; just skip them
+		raw, err := os.ReadFile(filepath.Join(l.root, entry.Name(), "cmdline"))
+		if err != nil || len(raw) == 0 {
+			continue
+		}
+		args := strings.Split(string(bytes.TrimRight(raw, "\x00")), "\x00")
+
+		info := ProcessInfo{PID: pid, Args: args, Cmdline: strings.TrimSpace(strings.Join(args, " "))}
+		if ppid, ticks, err := l.stat(entry.Name()); err == nil {
+			info.PPID = ppid
+			info.StartTime = bootTime.Add(time.Duration(ticks) * time.Second / clockTicks)
+		}
+		processes = append(processes, info)
+	}
+
+	return processes, nil
+}
+
+// bootTime reads the system boot time from /proc/stat
+func (l procLister) bootTime() (time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(l.root, "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "btime" {
+			secs, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(secs, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("btime not found in %s/stat", l.root)
+}
+
+// stat reads a process's parent ID and start time, in clock ticks since
+// boot
+func (l procLister) stat(pid string) (ppid int, startTicks int64, err error) {
+	data, err := os.ReadFile(filepath.Join(l.root, pid, "stat"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The command name is parenthesised and may contain spaces, so split
+	// after the closing paren. ppid is field 4 overall and starttime field
+	// 22, which are the 2nd and 20th fields after the name.
+	stat := string(data)
+	fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+	if len(fields) < 20 {
+		return 0, 0, fmt.Errorf("unexpected stat format for pid %s", pid)
+	}
+	if ppid, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, err
+	}
+	startTicks, err = strconv.ParseInt(fields[19], 10, 64)
+	return ppid, startTicks, err
+}
@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// maxRemoteStatusSize bounds the status list read from a remote source
+const maxRemoteStatusSize = 1 << 20
+
+// FetchRemoteStatus reads a JSON array of TestStatusFile objects from url,
+// for chaos tests writing their status inside a container. Entries with an
+// updated_at older than staleAfter are dropped, matching the local files;
+// PIDs belong to another host, so they aren't checked.
+func FetchRemoteStatus(ctx context.Context, client *http.Client, url string, staleAfter time.Duration) ([]models.ActiveChaosTest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status source returned %d", resp.StatusCode)
+	}
+
+	var raw []json.RawMessage
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRemoteStatusSize)).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decoding status source: %v", err)
+	}
+	var statuses []TestStatusFile
+	for _, data := range raw {
+		// Entries of an unsupported schema are skipped rather than misread
+		if status, _, err := parseStatusFile(data); err == nil {
+			statuses = append(statuses, status)
+		}
+	}
+
+	now := time.Now()
+	var tests []models.ActiveChaosTest
+	for _, status := range statuses {
+		lastSeen := now
+		if !status.UpdatedAt.IsZero() {
+			if now.Sub(status.UpdatedAt) > staleAfter {
+				continue
+			}
+			lastSeen = status.UpdatedAt
+		}
+
+		tests = append(tests, models.ActiveChaosTest{
+			Type:      status.TestType,
+			Target:    status.Target,
+			Status:    status.Status,
+			StartTime: status.StartTime,
+			Details:   status.Details,
+			Source:    "status_url",
+			Region:    targetRegion(status.Target),
+			LastSeen:  lastSeen,
+		})
+	}
+	return tests, nil
+}
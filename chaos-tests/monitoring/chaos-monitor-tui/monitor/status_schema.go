@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CurrentSchemaVersion is the status file layout written by
+// chaos-tests/lib/test_status.sh. Files without a schema_version are read
+// as this version unless they only have the legacy fields.
+const CurrentSchemaVersion = 1
+
+// legacyStatusFile is schema version 0, written by chaos scripts before
+// the fields were renamed: the test type was "type", the details were
+// "message" and the start time was Unix seconds in "started_at".
+type legacyStatusFile struct {
+	Type      string `json:"type"`
+	Target    string `json:"target"`
+	Status    string `json:"status"`
+	StartedAt int64  `json:"started_at"`
+	Message   string `json:"message"`
+	PID       int    `json:"pid,omitempty"`
+}
+
+// parseStatusFile decodes a status file of any supported schema version
+// into the current layout. migratedFrom is the version it was migrated
+// from, or -1 when it was already current.
+func parseStatusFile(data []byte) (status TestStatusFile, migratedFrom int, err error) {
+	var header struct {
+		SchemaVersion *int   `json:"schema_version"`
+		TestType      string `json:"test_type"`
+		Type          string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return status, -1, err
+	}
+
+	version := CurrentSchemaVersion
+	if header.SchemaVersion != nil {
+		version = *header.SchemaVersion
+	} else if header.TestType == "" && header.Type != "" {
+		// Legacy scripts never wrote a version
+		version = 0
+	}
+
+	switch version {
+	case 0:
+		var legacy legacyStatusFile
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return status, -1, err
+		}
+		return TestStatusFile{
+			SchemaVersion: CurrentSchemaVersion,
+			TestType:      legacy.Type,
+			Target:        legacy.Target,
+			Status:        legacy.Status,
+			StartTime:     time.Unix(legacy.StartedAt, 0).UTC(),
+			Details:       legacy.Message,
+			PID:           legacy.PID,
+		}, 0, nil
+	case CurrentSchemaVersion:
+		err := json.Unmarshal(data, &status)
+		status.SchemaVersion = CurrentSchemaVersion
+		return status, -1, err
+	default:
+		return status, -1, fmt.Errorf("unsupported schema_version %d (this monitor reads up to %d)", version, CurrentSchemaVersion)
+	}
+}
@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStatusFileVersionsParseAlike(t *testing.T) {
+	dir := t.TempDir()
+	modified := time.Now().Truncate(time.Second)
+	files := map[string]string{
+		"v0":          `{"type":"region-failure","target":"us-east-1","status":"running","started_at":1714564800,"message":"Blocking us-east-1"}`,
+		"v1":          `{"schema_version":1,"test_type":"region-failure","target":"us-east-1","status":"running","start_time":"2024-05-01T12:00:00Z","details":"Blocking us-east-1"}`,
+		"unversioned": `{"test_type":"region-failure","target":"us-east-1","status":"running","start_time":"2024-05-01T12:00:00Z","details":"Blocking us-east-1"}`,
+	}
+
+	opts := StatusFileOptions{StaleAfter: DefaultStaleAfter}
+	for name, data := range files {
+		path := filepath.Join(dir, name+".status.json")
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, _ := readTestStatusFile(filepath.Join(dir, "v1.status.json"), opts)
+	if want == nil || want.Type != "region-failure" || want.Region != "us-east-1" ||
+		!want.StartTime.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("v1 = %+v", want)
+	}
+	for _, name := range []string{"v0", "unversioned"} {
+		got, stale := readTestStatusFile(filepath.Join(dir, name+".status.json"), opts)
+		if got == nil || stale {
+			t.Errorf("%s: unreadable or stale", name)
+			continue
+		}
+		if !got.StartTime.Equal(want.StartTime) {
+			t.Errorf("%s start = %v, want %v", name, got.StartTime, want.StartTime)
+		}
+		if got.Type != want.Type || got.Target != want.Target || got.Status != want.Status ||
+			got.Details != want.Details || got.Region != want.Region || got.Source != want.Source || !got.LastSeen.Equal(want.LastSeen) {
+			t.Errorf("%s = %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestParseStatusFileVersions(t *testing.T) {
+	tests := []struct {
+		data         string
+		migratedFrom int
+		wantErr      bool
+	}{
+		{`{"type":"service-outage","started_at":0}`, 0, false},
+		{`{"schema_version":0,"type":"service-outage"}`, 0, false},
+		{`{"schema_version":1,"test_type":"service-outage"}`, -1, false},
+		{`{"test_type":"service-outage"}`, -1, false},
+		{`{"schema_version":2,"test_type":"service-outage"}`, -1, true},
+		{`{"schema_version":"1"}`, -1, true},
+	}
+	for _, tt := range tests {
+		status, migratedFrom, err := parseStatusFile([]byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error %v", tt.data, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if migratedFrom != tt.migratedFrom || status.TestType != "service-outage" || status.SchemaVersion != CurrentSchemaVersion {
+			t.Errorf("%s = %+v migrated from %d", tt.data, status, migratedFrom)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// maxStatusLine bounds one line of a status stream; longer lines are skipped
+const maxStatusLine = 64 << 10
+
+// StatusStream collects newline-delimited TestStatusFile objects written
+// by an upstream process, e.g. a chaos orchestrator piping into the
+// monitor. The latest line for each test type and target wins. Read runs
+// in the background while Tests is called from the refresh.
+type StatusStream struct {
+	mu      sync.Mutex
+	entries map[string]streamEntry // By test type and target
+	logger  *slog.Logger           // Receives skipped lines; nil discards
+}
+
+// streamEntry is the latest status of a test and when it was received
+type streamEntry struct {
+	status   TestStatusFile
+	received time.Time
+}
+
+// lastSeen is when the test last reported: its updated_at if set, or else
+// when its line was received
+func (e streamEntry) lastSeen() time.Time {
+	if !e.status.UpdatedAt.IsZero() {
+		return e.status.UpdatedAt
+	}
+	return e.received
+}
+
+// NewStatusStream returns an empty stream logging skipped lines to logger
+func NewStatusStream(logger *slog.Logger) *StatusStream {
+	return &StatusStream{entries: make(map[string]streamEntry), logger: logger}
+}
+
+// Read consumes status lines from r until it ends. Blank lines are
+// ignored; lines that aren't a status object, or are too long, are skipped
+// without stopping the stream. It returns nil at the end of r.
+func (s *StatusStream) Read(r io.Reader) error {
+	br := bufio.NewReaderSize(r, maxStatusLine)
+	for {
+		line, err := br.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			for err == bufio.ErrBufferFull {
+				_, err = br.ReadSlice('\n')
+			}
+			s.skip("line too long", nil)
+		} else if line = bytes.TrimSpace(line); len(line) > 0 {
+			s.add(line, time.Now())
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// add records one status line received at now
+func (s *StatusStream) add(line []byte, now time.Time) {
+	status, _, err := parseStatusFile(line)
+	if err != nil {
+		s.skip("unreadable status line", err)
+		return
+	}
+	if status.TestType == "" {
+		s.skip("status line without a test type", nil)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[status.TestType+"|"+status.Target] = streamEntry{status: status, received: now}
+}
+
+func (s *StatusStream) skip(reason string, err error) {
+	if s.logger == nil {
+		return
+	}
+	if err != nil {
+		s.logger.Debug(reason, "source", "stdin", "error", err)
+		return
+	}
+	s.logger.Debug(reason, "source", "stdin")
+}
+
+// Tests returns the tests streamed so far, dropping those that have lapsed
+// by now. With no file to take a modification time from, a test lapses at
+// its expires_at, or else staleAfter after its updated_at or, failing
+// that, after its line was received; the same staleness as status files.
+func (s *StatusStream) Tests(now time.Time, staleAfter time.Duration) []models.ActiveChaosTest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.entries))
+	for key, entry := range s.entries {
+		expired := now.Sub(entry.lastSeen()) > staleAfter
+		if !entry.status.ExpiresAt.IsZero() {
+			expired = now.After(entry.status.ExpiresAt)
+		}
+		if expired {
+			delete(s.entries, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tests := make([]models.ActiveChaosTest, 0, len(keys))
+	for _, key := range keys {
+		entry := s.entries[key]
+		status := entry.status
+		tests = append(tests, models.ActiveChaosTest{
+			Type:      status.TestType,
+			Target:    status.Target,
+			Status:    status.Status,
+			StartTime: status.StartTime,
+			Details:   status.Details,
+			Source:    "stdin",
+			Region:    targetRegion(status.Target),
+			LastSeen:  entry.lastSeen(),
+		})
+	}
+	return tests
+}
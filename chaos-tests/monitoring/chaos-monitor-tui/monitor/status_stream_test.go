@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusStream(t *testing.T) {
+	var logged bytes.Buffer
+	stream := NewStatusStream(slog.New(slog.NewTextHandler(&logged, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	now := time.Now()
+	stamp := func(d time.Duration) string { return now.Add(d).UTC().Format(time.RFC3339) }
+
+	lines := []string{
+		`{"test_type":"region-failure","target":"us-east-1","status":"starting","start_time":"2024-05-01T12:00:00Z"}`,
+		``,
+		`not json at all`,
+		`{"test_type":"service-outage","target":"s3",`, // Cut off mid-object
+		`{"target":"sqs","status":"running"}`,          // No test type
+		strings.Repeat("x", maxStatusLine+10),
+		`{"test_type":"region-failure","target":"us-east-1","status":"running","start_time":"2024-05-01T12:00:00Z","details":"failing over"}`,
+		fmt.Sprintf(`{"test_type":"latency-injection","target":"eu-west-1","status":"running","expires_at":%q}`, stamp(-time.Second)),
+		fmt.Sprintf(`{"test_type":"api-throttling","target":"sqs","status":"running","updated_at":%q}`, stamp(-time.Hour)),
+		fmt.Sprintf(`{"test_type":"cascade-failure","target":"vpc","status":"running","updated_at":%q,"expires_at":%q}`, stamp(-time.Hour), stamp(time.Hour)),
+	}
+	if err := stream.Read(strings.NewReader(strings.Join(lines, "\n"))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := stream.Tests(time.Now(), DefaultStaleAfter)
+	got := make(map[string]string)
+	for _, test := range tests {
+		got[test.Type] = test.Status + ": " + test.Details
+		if test.Source != "stdin" {
+			t.Errorf("%s source = %q", test.Type, test.Source)
+		}
+	}
+	want := map[string]string{
+		"region-failure":  "running: failing over", // The later line wins
+		"cascade-failure": "running: ",             // Old, but expires later
+	}
+	if len(got) != len(want) {
+		t.Errorf("tests = %v, want %v", got, want)
+	}
+	for testType, status := range want {
+		if got[testType] != status {
+			t.Errorf("%s = %q, want %q", testType, got[testType], status)
+		}
+	}
+	if len(tests) > 0 && (tests[0].Type != "cascade-failure" || tests[len(tests)-1].Region != "us-east-1") {
+		t.Errorf("tests out of order or without a region: %+v", tests)
+	}
+
+	for reason, n := range map[string]int{"unreadable status line": 2, "status line without a test type": 1, "line too long": 1} {
+		if c := strings.Count(logged.String(), reason); c != n {
+			t.Errorf("logged %q %d times, want %d:\n%s", reason, c, n, logged.String())
+		}
+	}
+
+	// A test without updated_at lapses staleAfter after its line arrived
+	if err := stream.Read(strings.NewReader(`{"test_type":"network-partition","target":"vpc","status":"running"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(stream.Tests(time.Now(), DefaultStaleAfter)); n != 3 {
+		t.Errorf("%d tests after another line, want 3", n)
+	}
+	for _, test := range stream.Tests(time.Now().Add(DefaultStaleAfter+time.Minute), DefaultStaleAfter) {
+		if test.Type == "network-partition" || test.Type == "region-failure" {
+			t.Errorf("%s didn't lapse", test.Type)
+		}
+	}
+}
@@ -2,85 +2,161 @@ package monitor
 
 import (
 	"chaos-monitor-tui/models"
-	"encoding/json"
-	"io/ioutil"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 )
 
 // TestStatusFile represents the structure of a chaos test status file
 type TestStatusFile struct {
-	TestType    string    `json:"test_type"`
-	Target      string    `json:"target"`
-	Status      string    `json:"status"`
-	StartTime   time.Time `json:"start_time"`
-	Details     string    `json:"details"`
-	PID         int       `json:"pid,omitempty"`
+	SchemaVersion int       `json:"schema_version,omitempty"` // See CurrentSchemaVersion
+	TestType      string    `json:"test_type"`
+	Target        string    `json:"target"`
+	Status        string    `json:"status"`
+	StartTime     time.Time `json:"start_time"`
+	Details       string    `json:"details"`
+	PID           int       `json:"pid,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"` // Used for staleness by remote sources
+	ExpiresAt     time.Time `json:"expires_at,omitempty"` // With -status-stdin, when the test lapses unless updated
 }
 
-// DetectChaosTestFromFiles checks for chaos test status files
-func DetectChaosTestFromFiles() []models.ActiveChaosTest {
-	var tests []models.ActiveChaosTest
-	
+// StatusDirs are the directories chaos test scripts write status files to
+var StatusDirs = []string{
+	"/tmp/chaos-tests",
+	"/var/tmp/chaos-tests",
+	"./chaos-tests/status",
+}
+
+// StatusFileOptions controls how stale test status files are handled
+type StatusFileOptions struct {
+	StaleAfter time.Duration // Files not modified for this long are stale
+	Prune      bool          // Delete stale files instead of archiving them
+	Logger     *slog.Logger  // Receives schema migrations and unreadable files; nil discards
+
+	// OnUnreadableDir is called with a status directory that's a file or
+	// can't be read, once per directory and error like the logged warning
+	OnUnreadableDir func(dir string, err error)
+}
+
+// DefaultStaleAfter is how long a status file may go unmodified before the
+// test is considered finished
+const DefaultStaleAfter = 5 * time.Minute
+
+// archiveDir is the subdirectory of a status directory stale files move to
+const archiveDir = "archive"
+
+// DetectChaosTestFromFiles checks for chaos test status files. Stale files
+// are moved to an archive/ subdirectory (or deleted when pruning) and
+// returned as completed tests, unless their process is still running.
+func DetectChaosTestFromFiles(opts StatusFileOptions) (active, archived []models.ActiveChaosTest) {
 	// Check common locations for test status files
-	statusDirs := []string{
-		"/tmp/chaos-tests",
-		"/var/tmp/chaos-tests",
-		"./chaos-tests/status",
-	}
-	
-	for _, dir := range statusDirs {
-		if _, err := os.Stat(dir); os.IsNotExist(err) {
+	for _, dir := range StatusDirs {
+		entries, ok := readStatusDir(dir, opts)
+		if !ok {
 			continue
 		}
 		
-		files, err := ioutil.ReadDir(dir)
-		if err != nil {
-			continue
-		}
-		
-		for _, file := range files {
-			if strings.HasSuffix(file.Name(), ".status.json") {
-				fullPath := filepath.Join(dir, file.Name())
-				if test := readTestStatusFile(fullPath); test != nil {
-					tests = append(tests, *test)
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".status.json") {
+				fullPath := filepath.Join(dir, entry.Name())
+				test, stale := readTestStatusFile(fullPath, opts)
+				if test == nil {
+					continue
+				}
+				if !stale {
+					active = append(active, *test)
+					continue
+				}
+				if err := archiveStatusFile(fullPath, opts.Prune); err == nil {
+					test.Status = "completed"
+					archived = append(archived, *test)
 				}
 			}
 		}
 	}
 	
-	return tests
+	return active, archived
+}
+
+// unreadableDirs holds the status directories already warned about, with
+// the error, so a misconfigured path is logged once rather than every tick
+var unreadableDirs sync.Map
+
+// readStatusDir lists a status directory. A missing one is skipped
+// silently, as most of StatusDirs usually are; one that exists but is a
+// file or can't be read is logged as a warning and passed to
+// opts.OnUnreadableDir, once until it changes.
+func readStatusDir(dir string, opts StatusFileOptions) ([]os.DirEntry, bool) {
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		unreadableDirs.Delete(dir)
+		return nil, false
+	}
+	var entries []os.DirEntry
+	if err == nil && !info.IsDir() {
+		err = fmt.Errorf("not a directory")
+	} else if err == nil {
+		entries, err = os.ReadDir(dir)
+	}
+	if err != nil {
+		if previous, warned := unreadableDirs.Swap(dir, err.Error()); !warned || previous != err.Error() {
+			if opts.Logger != nil {
+				opts.Logger.Warn("unreadable status directory", "path", dir, "error", err)
+			}
+			if opts.OnUnreadableDir != nil {
+				opts.OnUnreadableDir(dir, err)
+			}
+		}
+		return nil, false
+	}
+	unreadableDirs.Delete(dir)
+	return entries, true
 }
 
-func readTestStatusFile(path string) *models.ActiveChaosTest {
-	data, err := ioutil.ReadFile(path)
+// readTestStatusFile parses a status file, migrating older schema versions.
+// stale is set when the file hasn't been modified within opts.StaleAfter
+// and no process is known to be running it.
+func readTestStatusFile(path string, opts StatusFileOptions) (test *models.ActiveChaosTest, stale bool) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil
+		return nil, false
 	}
-	
-	var status TestStatusFile
-	if err := json.Unmarshal(data, &status); err != nil {
-		return nil
+
+	status, migratedFrom, err := parseStatusFile(data)
+	if err != nil {
+		if opts.Logger != nil {
+			opts.Logger.Debug("unreadable status file", "path", path, "error", err)
+		}
+		return nil, false
+	}
+	if migratedFrom >= 0 && opts.Logger != nil {
+		opts.Logger.Debug("migrated status file", "path", path, "from_version", migratedFrom, "to_version", CurrentSchemaVersion)
 	}
 	
-	// Check if test is still active (file modified within last 5 minutes)
+	// Check if test is still active (file modified recently)
 	info, err := os.Stat(path)
 	if err != nil {
-		return nil
-	}
-	
-	if time.Since(info.ModTime()) > 5*time.Minute {
-		// Test is probably stale
-		return nil
+		return nil, false
 	}
+	stale = time.Since(info.ModTime()) > opts.StaleAfter
 	
 	// Check if process is still running (if PID is provided)
 	if status.PID > 0 {
 		if !isProcessRunning(status.PID) {
 			// Process has ended, test might be complete
 			status.Status = "completed"
+		} else if stale {
+			// Never archive a file whose test is still running; it's just
+			// not being updated
+			return nil, false
 		}
 	}
 	
@@ -90,47 +166,219 @@ func readTestStatusFile(path string) *models.ActiveChaosTest {
 		Status:    status.Status,
 		StartTime: status.StartTime,
 		Details:   status.Details,
+		Source:    "status_file",
+		Region:    targetRegion(status.Target),
+		LastSeen:  info.ModTime(),
+	}, stale
+}
+
+// archiveStatusFile moves a stale status file into the archive/
+// subdirectory next to it, or deletes it when pruning
+func archiveStatusFile(path string, prune bool) error {
+	if prune {
+		return os.Remove(path)
+	}
+	dir := filepath.Join(filepath.Dir(path), archiveDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
 	}
+	return os.Rename(path, filepath.Join(dir, filepath.Base(path)))
 }
 
+// isProcessRunning reports whether a process with the PID exists, by
+// sending it signal 0, which checks the PID without delivering anything.
+// It must be syscall.Signal(0): os.Signal(nil) is rejected as an
+// unsupported signal, making every process look dead, so status files of
+// running tests would be archived. A process owned by another user
+// answers EPERM, which still means it exists.
 func isProcessRunning(pid int) bool {
-	// Check if process exists by sending signal 0
 	process, err := os.FindProcess(pid)
 	if err != nil {
 		return false
 	}
-	
-	err = process.Signal(os.Signal(nil))
-	return err == nil
+
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// DetectFromProcessList checks running processes for chaos test scripts.
+// testTypes adds to or overrides DefaultTestTypes.
+func DetectFromProcessList(testTypes map[string]string) []models.ActiveChaosTest {
+	return DetectFromProcesses(procLister{root: "/proc"}, testTypes)
 }
 
-// DetectFromProcessList checks running processes for chaos test scripts
-func DetectFromProcessList() []models.ActiveChaosTest {
+// DetectFromProcesses matches the scripts run by listed processes against
+// the known chaos test scripts. A wrapper such as sh -c or timeout and the
+// script it starts are one test run, so a match whose parent process
+// matched the same test type is dropped in favour of the parent.
+func DetectFromProcesses(lister ProcessLister, testTypes map[string]string) []models.ActiveChaosTest {
 	var tests []models.ActiveChaosTest
-	
-	// This is a simplified version - in production you'd use proper process listing
-	// For now, we'll check for common python scripts
-	
+
+	processes, err := lister.List()
+	if err != nil {
+		return tests
+	}
+
+	type match struct {
+		proc     ProcessInfo
+		testType string
+		target   string
+	}
+	var matches []match
+	matched := make(map[int]string) // Test types by PID
+	for _, proc := range processes {
+		args := proc.Args
+		if args == nil {
+			args = strings.Fields(proc.Cmdline)
+		}
+		testType, rest, ok := findScript(args, testTypes)
+		if !ok {
+			continue
+		}
+		matches = append(matches, match{proc: proc, testType: testType, target: processTarget(rest)})
+		matched[proc.PID] = testType
+	}
+
+	now := time.Now()
+	for _, m := range matches {
+		if parentType, ok := matched[m.proc.PPID]; ok && m.proc.PPID != 0 && parentType == m.testType {
+			continue
+		}
+
+		startTime := m.proc.StartTime
+		if startTime.IsZero() {
+			startTime = now
+		}
+
+		tests = append(tests, models.ActiveChaosTest{
+			Type:      m.testType,
+			Target:    m.target,
+			Status:    "active",
+			StartTime: startTime,
+			Details:   fmt.Sprintf("PID %d: %s", m.proc.PID, m.proc.Cmdline),
+			Source:    "process",
+			Region:    targetRegion(m.target),
+			LastSeen:  now,
+		})
+	}
+
 	return tests
 }
 
+// findScript returns the test type of the chaos test script a command line
+// runs and the arguments following it. The script is the command itself,
+// or the first non-option argument of an interpreter such as bash, python3
+// or node; a wrapper such as timeout or env is skipped along with its
+// options, and sh -c is searched within its command string. A script that
+// is merely an argument, e.g. to vim, tail or grep, doesn't count.
+func findScript(args []string, testTypes map[string]string) (testType string, rest []string, ok bool) {
+	if len(args) == 0 {
+		return "unknown", nil, false
+	}
+	if testType := MatchTestType(args[0], testTypes); testType != "unknown" {
+		return testType, args[1:], true
+	}
+
+	command := filepath.Base(args[0])
+	switch {
+	case wrapperCommands[command]:
+		i := 1
+		for i < len(args) && (strings.HasPrefix(args[i], "-") || strings.Contains(args[i], "=")) {
+			i++
+		}
+		if command == "timeout" && i < len(args) {
+			i++ // The duration
+		}
+		return findScript(args[i:], testTypes)
+	case interpreterPattern.MatchString(command):
+		for i := 1; i < len(args); i++ {
+			if args[i] == "-c" && shellPattern.MatchString(command) && i+1 < len(args) {
+				return findScript(strings.Fields(args[i+1]), testTypes)
+			}
+			if strings.HasPrefix(args[i], "-") {
+				continue
+			}
+			if testType := MatchTestType(args[i], testTypes); testType != "unknown" {
+				return testType, args[i+1:], true
+			}
+			break
+		}
+	}
+	return "unknown", nil, false
+}
+
+// wrapperCommands run another command given after their options
+var wrapperCommands = map[string]bool{
+	"env": true, "nohup": true, "nice": true, "setsid": true, "stdbuf": true, "sudo": true, "timeout": true,
+}
+
+var (
+	// interpreterPattern matches interpreters that run a script given as
+	// their first argument, e.g. bash, python3.11 or node
+	interpreterPattern = regexp.MustCompile(`^((ba|da|k|z)?sh|python[0-9.]*|node(js)?|deno|bun|ts-node|ruby|perl|pwsh)$`)
+	// shellPattern matches the interpreters that take -c
+	shellPattern = regexp.MustCompile(`^(ba|da|k|z)?sh$`)
+)
+
+// processTarget returns the arguments following the chaos script in a
+// command line, which the scenarios use for the region or service targeted
+func processTarget(args []string) string {
+	if len(args) > 0 {
+		return strings.Join(args, " ")
+	}
+	return "default target"
+}
+
+// DefaultTestTypes maps chaos test script names, without their extension
+// and with "-" normalized to "_", to readable test types
+var DefaultTestTypes = map[string]string{
+	"region_failure":      "region-failure",
+	"latency_injection":   "latency-injection",
+	"service_outage":      "service-outage",
+	"api_throttling":      "api-throttling",
+	"cascade_failure":     "cascade-failure",
+	"network_partition":   "network-partition",
+	"resource_exhaustion": "resource-exhaustion",
+}
+
 // FormatTestType converts test script names to readable test types
 func FormatTestType(scriptName string) string {
-	typeMap := map[string]string{
-		"region_failure.py":     "region-failure",
-		"latency_injection.py":  "latency-injection",
-		"service_outage.py":     "service-outage",
-		"api_throttling.py":     "api-throttling",
-		"cascade_failure.py":    "cascade-failure",
-		"network_partition.py":  "network-partition",
-		"resource_exhaustion.py": "resource-exhaustion",
+	return MatchTestType(scriptName, nil)
+}
+
+// MatchTestType returns the test type of a chaos test script path, or
+// "unknown". Scripts match on their name whatever the directory, so
+// region_failure.py, ./region-failure.sh and region_failure.js are all
+// "region-failure"; files without a script extension, such as
+// region_failure.log, don't match. Overrides take precedence over
+// DefaultTestTypes.
+func MatchTestType(scriptName string, overrides map[string]string) string {
+	if !scriptExtensions[strings.ToLower(filepath.Ext(scriptName))] {
+		return "unknown"
 	}
-	
-	for script, testType := range typeMap {
-		if strings.Contains(scriptName, script) {
+	stem := normalizeScriptName(scriptName)
+	for name, testType := range overrides {
+		if normalizeScriptName(name) == stem {
 			return testType
 		}
 	}
-	
+	if testType, ok := DefaultTestTypes[stem]; ok {
+		return testType
+	}
 	return "unknown"
-}
\ No newline at end of file
+}
+
+// scriptExtensions are the extensions a chaos test script can have; ""
+// allows executables without one
+var scriptExtensions = map[string]bool{
+	"": true, ".sh": true, ".bash": true, ".py": true, ".js": true, ".mjs": true,
+	".cjs": true, ".ts": true, ".rb": true, ".pl": true, ".ps1": true,
+}
+
+// normalizeScriptName reduces a script path to its lowercased base name
+// without extension, using "_" as the word separator
+func normalizeScriptName(name string) string {
+	base := filepath.Base(name)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	return strings.ReplaceAll(strings.ToLower(base), "-", "_")
+}
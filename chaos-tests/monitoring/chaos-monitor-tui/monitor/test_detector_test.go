@@ -0,0 +1,255 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestMatchTestType(t *testing.T) {
+	overrides := map[string]string{"dns-failure": "dns-failure"}
+	tests := []struct {
+		script string
+		want   string
+	}{
+		{"region_failure.sh", "region-failure"},
+		{"./scenarios/latency_injection.sh", "latency-injection"},
+		{"service_outage.js", "service-outage"},
+		{"/opt/chaos/api-throttling.py", "api-throttling"},
+		{"Cascade-Failure.SH", "cascade-failure"},
+		{"network_partition", "network-partition"},
+		{"dns_failure.sh", "dns-failure"},
+		{"region_failure.log", "unknown"},
+		{"region_failure.json", "unknown"},
+		{"smoke_test.sh", "unknown"},
+		{"vim", "unknown"},
+		{"", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := MatchTestType(tt.script, overrides); got != tt.want {
+			t.Errorf("MatchTestType(%q) = %q, want %q", tt.script, got, tt.want)
+		}
+	}
+}
+
+// useStatusDirs points StatusDirs at dirs for the rest of the test
+func useStatusDirs(t *testing.T, dirs ...string) {
+	t.Helper()
+	saved := StatusDirs
+	StatusDirs = dirs
+	t.Cleanup(func() { StatusDirs = saved })
+}
+
+func TestDetectChaosTestFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	useStatusDirs(t, dir, filepath.Join(dir, "missing"))
+	old := time.Now().Add(-time.Hour)
+	write := func(name, data string, modified time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A child that has exited and been reaped no longer exists
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skip("can't run a child process:", err)
+	}
+
+	write("valid.status.json", `{"test_type":"region-failure","target":"us-east-1","status":"running","start_time":"2024-05-01T12:00:00Z"}`, time.Now())
+	write("stale.status.json", `{"test_type":"service-outage","target":"s3","status":"running"}`, old)
+	write("malformed.status.json", `{"test_type": "latency-injection",`, time.Now())
+	write("exited.status.json", fmt.Sprintf(`{"test_type":"api-throttling","target":"sqs","status":"running","pid":%d}`, cmd.Process.Pid), time.Now())
+	write("notes.txt", `{"test_type":"cascade-failure"}`, time.Now())
+
+	active, archived := DetectChaosTestFromFiles(StatusFileOptions{StaleAfter: DefaultStaleAfter})
+	got := make(map[string]string)
+	for _, test := range active {
+		got[test.Type] = test.Status
+	}
+	want := map[string]string{"region-failure": "running", "api-throttling": "completed"}
+	if len(got) != len(want) {
+		t.Errorf("detected %v, want %v", got, want)
+	}
+	for testType, status := range want {
+		if got[testType] != status {
+			t.Errorf("%s status = %q, want %q", testType, got[testType], status)
+		}
+	}
+
+	// The stale file is archived as a completed test
+	if len(archived) != 1 || archived[0].Type != "service-outage" || archived[0].Status != "completed" {
+		t.Errorf("archived %+v", archived)
+	}
+	if _, err := os.Stat(filepath.Join(dir, archiveDir, "stale.status.json")); err != nil {
+		t.Error(err)
+	}
+}
+
+// fakeLister returns a fixed process list
+type fakeLister []ProcessInfo
+
+func (l fakeLister) List() ([]ProcessInfo, error) { return l, nil }
+
+func TestDetectFromProcesses(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	processes := fakeLister{
+		{PID: 10, PPID: 1, Cmdline: "vim region_failure.sh"},
+		{PID: 11, PPID: 1, Cmdline: "tail -f /tmp/latency_injection.log"},
+		{PID: 12, PPID: 1, Cmdline: "grep -r service_outage.sh ."},
+		{PID: 20, PPID: 1, Args: []string{"sh", "-c", "./region_failure.sh us-east-1"}, Cmdline: "sh -c ./region_failure.sh us-east-1", StartTime: start},
+		{PID: 21, PPID: 20, Cmdline: "/bin/bash ./region_failure.sh us-east-1"},
+		{PID: 30, PPID: 1, Cmdline: "timeout 600 ./service-outage.sh s3"},
+		{PID: 31, PPID: 30, Cmdline: "/usr/bin/bash ./service-outage.sh s3"},
+		{PID: 40, PPID: 1, Cmdline: "python3 -u /opt/chaos/latency_injection.py eu-west-1"},
+		{PID: 50, PPID: 1, Cmdline: "node api_throttling.js"},
+	}
+
+	tests := DetectFromProcesses(processes, nil)
+	got := make(map[string]models.ActiveChaosTest)
+	for _, test := range tests {
+		got[test.Type] = test
+	}
+	if len(tests) != 4 || len(got) != 4 {
+		t.Fatalf("detected %d tests, want 4: %+v", len(tests), tests)
+	}
+
+	want := map[string]string{
+		"region-failure":    "us-east-1",
+		"service-outage":    "s3",
+		"latency-injection": "eu-west-1",
+		"api-throttling":    "default target",
+	}
+	for testType, target := range want {
+		if got[testType].Target != target {
+			t.Errorf("%s target = %q, want %q", testType, got[testType].Target, target)
+		}
+	}
+	if test := got["region-failure"]; test.Details != "PID 20: sh -c ./region_failure.sh us-east-1" || !test.StartTime.Equal(start) {
+		t.Errorf("region-failure reported from the child, not the wrapper: %+v", test)
+	}
+}
+
+func TestProcListerReadsParent(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, data string) {
+		t.Helper()
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("stat", "cpu  1 2 3\nbtime 1700000000\n")
+	write("42/cmdline", "/bin/bash\x00./region_failure.sh\x00us east 1\x00")
+	write("42/stat", "42 (region failure) S 7 42 42 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 500 0 0")
+
+	processes, err := procLister{root: root}.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(processes) != 1 {
+		t.Fatalf("listed %+v", processes)
+	}
+	proc := processes[0]
+	if proc.PID != 42 || proc.PPID != 7 || len(proc.Args) != 3 || proc.Args[2] != "us east 1" {
+		t.Errorf("listed %+v", proc)
+	}
+	if want := time.Unix(1700000005, 0); !proc.StartTime.Equal(want) {
+		t.Errorf("start time = %v, want %v", proc.StartTime, want)
+	}
+}
+
+func TestIsProcessRunning(t *testing.T) {
+	if !isProcessRunning(os.Getpid()) {
+		t.Error("the test process isn't running")
+	}
+
+	// A child that has exited and been reaped no longer exists
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skip("can't run a child process:", err)
+	}
+	if isProcessRunning(cmd.Process.Pid) {
+		t.Errorf("exited child %d is running", cmd.Process.Pid)
+	}
+}
+
+func TestUnreadableStatusDir(t *testing.T) {
+	var logged bytes.Buffer
+	var reported []string
+	opts := StatusFileOptions{
+		StaleAfter: DefaultStaleAfter,
+		Logger:     slog.New(slog.NewTextHandler(&logged, nil)),
+		OnUnreadableDir: func(dir string, err error) {
+			reported = append(reported, dir+": "+err.Error())
+		},
+	}
+	root := t.TempDir()
+	file := filepath.Join(root, "status")
+	if err := os.WriteFile(file, []byte("not a directory"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	useStatusDirs(t, filepath.Join(root, "missing"), file)
+
+	// A path that's a file is warned about once, not on every pass
+	DetectChaosTestFromFiles(opts)
+	DetectChaosTestFromFiles(opts)
+	if n := strings.Count(logged.String(), "unreadable status directory"); n != 1 {
+		t.Fatalf("logged %d warnings, want 1:\n%s", n, logged.String())
+	}
+	if !strings.Contains(logged.String(), "path="+file) || !strings.Contains(logged.String(), "not a directory") {
+		t.Errorf("warning = %s", logged.String())
+	}
+	if strings.Contains(logged.String(), "missing") {
+		t.Errorf("missing directory was logged: %s", logged.String())
+	}
+	if !slices.Equal(reported, []string{file + ": not a directory"}) {
+		t.Errorf("reported %q", reported)
+	}
+
+	// Once it's a readable directory it's read, and warned about again if
+	// it breaks later
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(file, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	status := filepath.Join(file, "region.status.json")
+	if err := os.WriteFile(status, []byte(`{"test_type":"region-failure","target":"us-east-1","status":"running"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := DetectChaosTestFromFiles(opts); len(active) != 1 {
+		t.Errorf("active = %+v", active)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("root can read a directory without read permission")
+	}
+	logged.Reset()
+	if err := os.Chmod(file, 0o000); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(file, 0o755) })
+	DetectChaosTestFromFiles(opts)
+	DetectChaosTestFromFiles(opts)
+	if n := strings.Count(logged.String(), "unreadable status directory"); n != 1 || !strings.Contains(logged.String(), "permission denied") {
+		t.Errorf("logged %d warnings:\n%s", n, logged.String())
+	}
+}
@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// DefaultThrottleChecks is how many consecutive checks an endpoint or
+// service must be throttled before API throttling is reported
+const DefaultThrottleChecks = 2
+
+// ThrottleDetector infers API throttling from endpoints and services that
+// keep answering 429 or a throttling error, e.g. when a rate limit is
+// injected at the proxy rather than through the Chaos API. It keeps state
+// between ticks, so use one per target.
+type ThrottleDetector struct {
+	minChecks int
+	streaks   map[string]throttleStreak // Currently throttled endpoints and services, by name
+}
+
+// throttleStreak is how long something has been throttled without a break
+type throttleStreak struct {
+	since  time.Time
+	checks int
+}
+
+// NewThrottleDetector returns a detector that reports throttling seen on
+// minChecks consecutive checks
+func NewThrottleDetector(minChecks int) *ThrottleDetector {
+	return &ThrottleDetector{minChecks: minChecks, streaks: make(map[string]throttleStreak)}
+}
+
+// Observe records the endpoints' and services' statuses as of at, and
+// returns an api-throttling test when any of them has been throttled on
+// each of the last minChecks checks, or nil otherwise. A single throttled
+// check is a blip and doesn't count.
+func (d *ThrottleDetector) Observe(endpoints []models.EndpointStatus, services []models.ServiceStatus, at time.Time) *models.ActiveChaosTest {
+	throttled := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		if endpoint.Status == "throttled" {
+			throttled[endpoint.Name] = true
+		}
+	}
+	for _, service := range services {
+		if service.Status == "throttled" {
+			throttled[service.Label()] = true
+		}
+	}
+
+	for name := range d.streaks {
+		if !throttled[name] {
+			delete(d.streaks, name)
+		}
+	}
+	for name := range throttled {
+		streak, ok := d.streaks[name]
+		if !ok {
+			streak.since = at
+		}
+		streak.checks++
+		d.streaks[name] = streak
+	}
+
+	var affected []string
+	var start time.Time
+	for name, streak := range d.streaks {
+		if streak.checks < d.minChecks {
+			continue
+		}
+		affected = append(affected, name)
+		if start.IsZero() || streak.since.Before(start) {
+			start = streak.since
+		}
+	}
+	if len(affected) == 0 || d.minChecks < 1 {
+		return nil
+	}
+	sort.Strings(affected)
+
+	return &models.ActiveChaosTest{
+		Type:      "api-throttling",
+		Target:    affected[0],
+		Status:    "active",
+		StartTime: start,
+		Details:   fmt.Sprintf("Rate limiting observed for %s, no fault configured", at.Sub(start).Round(time.Second)),
+		Source:    "behavioral",
+		LastSeen:  at,
+		Affected:  affected,
+		Impact:    ImpactLabel(affected),
+	}
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestThrottleDetector(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	d := NewThrottleDetector(DefaultThrottleChecks)
+
+	// Each check: the Main Site endpoint's status, then SQS in us-east-1's
+	steps := []struct {
+		endpoint, service string
+		affected          []string // nil when no test is reported
+	}{
+		{"ok", "healthy", nil},
+		{"throttled", "healthy", nil}, // A single blip
+		{"ok", "healthy", nil},
+		{"throttled", "healthy", nil},
+		{"throttled", "throttled", []string{"Main Site"}},
+		{"throttled", "throttled", []string{"Main Site", "SQS (us-east-1)"}},
+		{"failed", "throttled", []string{"SQS (us-east-1)"}},
+		{"ok", "healthy", nil},
+	}
+	for i, step := range steps {
+		at := start.Add(time.Duration(i) * 30 * time.Second)
+		test := d.Observe(
+			[]models.EndpointStatus{{Name: "Main Site", Status: step.endpoint}},
+			[]models.ServiceStatus{{Name: "SQS", Region: "us-east-1", Status: step.service}},
+			at)
+		if step.affected == nil {
+			if test != nil {
+				t.Errorf("check %d: reported %+v", i, test)
+			}
+			continue
+		}
+		if test == nil {
+			t.Errorf("check %d: nothing reported, want %v", i, step.affected)
+			continue
+		}
+		if test.Type != "api-throttling" || test.Source != "behavioral" || !slices.Equal(test.Affected, step.affected) || test.Target != step.affected[0] {
+			t.Errorf("check %d: reported %+v, want %v", i, test, step.affected)
+		}
+		if !test.LastSeen.Equal(at) {
+			t.Errorf("check %d: last seen %v, want %v", i, test.LastSeen, at)
+		}
+	}
+
+	// The test starts when the longest streak began, not when it was reported
+	d = NewThrottleDetector(3)
+	var test *models.ActiveChaosTest
+	for i := 0; i < 3; i++ {
+		test = d.Observe([]models.EndpointStatus{{Name: "API", Status: "throttled"}}, nil, start.Add(time.Duration(i)*time.Minute))
+		if (test != nil) != (i == 2) {
+			t.Fatalf("after %d throttled checks: %+v", i+1, test)
+		}
+	}
+	if !test.StartTime.Equal(start) || test.Details != "Rate limiting observed for 2m0s, no fault configured" {
+		t.Errorf("started %v: %q", test.StartTime, test.Details)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+
+	"chaos-monitor-tui/models"
+	"chaos-monitor-tui/monitor"
+)
+
+// runOnce performs a single monitoring pass, prints a snapshot and returns
+// the process exit code: 0 when everything is healthy, 1 otherwise.
+func runOnce(ctx context.Context, cfg config, metrics *metricsExporter, logger *slog.Logger) int {
+	m := initialModel(ctx, cfg, logger)
+	m.metrics = metrics
+	if cfg.statusStdin {
+		m.statusStream = monitor.NewStatusStream(logger)
+		if err := m.statusStream.Read(os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: reading test status from stdin: %v\n", err)
+			return 1
+		}
+	}
+	m.updateMonitoringData()
+
+	if cfg.jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(m.state); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	} else {
+		writeSnapshot(os.Stdout, &m.state)
+	}
+
+	if cfg.csvOut != "" {
+		path, err := m.exportCSV(cfg.csvOut)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Statistics exported to %s\n", path)
+	}
+	if cfg.reportPath != "" {
+		if err := m.writeReportFile(cfg.reportPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Incident report written to %s\n", cfg.reportPath)
+	}
+
+	if !isHealthy(&m.state) {
+		return 1
+	}
+	return 0
+}
+
+// isHealthy reports whether all endpoints and services are available. An
+// endpoint served by its failover region is available, as in the stats.
+// A service that couldn't be checked because docker isn't reachable is
+// left out, as the stats leave it out, rather than failing a CI gate over
+// the runner's setup.
+func isHealthy(state *models.MonitorState) bool {
+	for _, endpoint := range state.NginxEndpoints {
+		if !endpoint.Available() {
+			return false
+		}
+	}
+	for _, service := range state.AWSServices {
+		if service.Status != "healthy" && service.FailureType != "docker_unavailable" {
+			return false
+		}
+	}
+	return true
+}
+
+// writeSnapshot prints a plain-text summary of the monitor state
+func writeSnapshot(w io.Writer, state *models.MonitorState) {
+	fmt.Fprintf(w, "Chaos Engineering Monitor snapshot (%s)\n\n", state.LastUpdate.Format("2006-01-02 15:04:05"))
+
+	fmt.Fprintln(w, "ACTIVE CHAOS TESTS")
+	if len(state.ActiveTests) == 0 {
+		fmt.Fprintln(w, "  none")
+	}
+	for _, test := range state.ActiveTests {
+		target := test.Target
+		if test.Impact != "" {
+			target += " → " + test.Impact
+		}
+		fmt.Fprintf(w, "  %s: %s (%s)\n", test.Type, target, test.Details)
+	}
+	fmt.Fprintf(w, "  Chaos API: %d faults, %d effects\n\n", len(state.ChaosAPIFaults), len(state.ChaosAPIEffects))
+
+	fmt.Fprintln(w, "NGINX WEB SERVERS")
+	for _, endpoint := range state.NginxEndpoints {
+		fmt.Fprintf(w, "  %-28s %-8s %.3fs\n", endpoint.Name, endpoint.Status, endpoint.ResponseTime)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "AWS SERVICES")
+	for _, service := range state.AWSServices {
+		fmt.Fprintf(w, "  %-28s %-8s %.3fs\n", service.Name, service.Status, service.ResponseTime)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "STATISTICS")
+	fmt.Fprintf(w, "  %-28s %s\n", "System availability", formatSystemAvailability(state))
+	for _, name := range sortedKeys(state.Stats.NginxStats) {
+		stats := state.Stats.NginxStats[name]
+		fmt.Fprintf(w, "  %-28s %d/%d (%.1f%%)\n", name, stats.TotalChecks-stats.Failures, stats.TotalChecks, stats.SuccessRate)
+	}
+	for _, name := range sortedKeys(state.Stats.ServiceStats) {
+		stats := state.Stats.ServiceStats[name]
+		fmt.Fprintf(w, "  %-28s %.0f%%\n", name, stats.AvailabilityPct)
+	}
+}
+
+// sortedKeys returns the keys of a stats map in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
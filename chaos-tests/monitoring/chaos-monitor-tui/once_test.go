@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestIsHealthy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "<html>Served from %s</html>", r.URL.Query().Get("region"))
+	}))
+	defer server.Close()
+
+	m := newTestModel(t)
+	probe := func(region string) models.EndpointStatus {
+		ep := endpointDef{name: "Main Site", url: server.URL + "?region=" + region, primaryRegion: "us-east-1", fallbackRegion: "us-east-2"}
+		return m.checkHTTPEndpoint(ep)
+	}
+	primary, failover, neither := probe("us-east-1"), probe("us-east-2"), probe("eu-west-1")
+	if primary.Status != "ok" || failover.Status != "serving-fallback" || neither.Status != "failed" {
+		t.Fatalf("statuses = %s, %s, %s", primary.Status, failover.Status, neither.Status)
+	}
+
+	healthy := models.ServiceStatus{Name: "s3", Status: "healthy", FailureType: "ok"}
+	noDocker := models.ServiceStatus{Name: "sqs", Status: "unavailable", FailureType: "docker_unavailable"}
+	outage := models.ServiceStatus{Name: "dynamodb", Status: "outage", FailureType: "service_outage"}
+
+	tests := []struct {
+		name      string
+		endpoints []models.EndpointStatus
+		services  []models.ServiceStatus
+		want      bool
+	}{
+		{"primary body", []models.EndpointStatus{primary}, []models.ServiceStatus{healthy}, true},
+		{"failover body", []models.EndpointStatus{primary, failover}, []models.ServiceStatus{healthy}, true},
+		{"body naming neither region", []models.EndpointStatus{primary, neither}, []models.ServiceStatus{healthy}, false},
+		{"service unchecked without docker", []models.EndpointStatus{primary}, []models.ServiceStatus{healthy, noDocker}, true},
+		{"service outage", []models.EndpointStatus{primary}, []models.ServiceStatus{healthy, outage}, false},
+	}
+	for _, tt := range tests {
+		state := models.MonitorState{NginxEndpoints: tt.endpoints, AWSServices: tt.services}
+		if got := isHealthy(&state); got != tt.want {
+			t.Errorf("%s: isHealthy = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
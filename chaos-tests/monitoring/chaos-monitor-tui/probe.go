@@ -0,0 +1,540 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+const (
+	probeTimeout = 5 * time.Second
+	maxDrainSize = 64 << 10 // Bytes read from a body to allow connection reuse
+	maxBodySize  = 1 << 20  // Bytes of a body read for content validation or -timing body
+)
+
+// What an HTTP endpoint's response time measures, set with -timing
+const (
+	timingHeaders = "headers" // Until the response headers arrive
+	timingBody    = "body"    // Until the whole body, up to maxBodySize, has arrived
+)
+
+// retryBackoff is the wait before the first -retries attempt; it doubles
+// for each further attempt
+const retryBackoff = 100 * time.Millisecond
+
+// newHTTPClient returns a client for probing. The monitor keeps a single
+// client so its transport can hold connections open between ticks; during
+// latency injection connection setup would otherwise dominate the timings.
+// Requests go through proxy when set, otherwise through the proxy named by
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY. HTTPS servers are verified with
+// tlsConfig, or against the system roots when it's nil.
+func newHTTPClient(proxy *url.URL, tlsConfig *tls.Config) *http.Client {
+	return &http.Client{
+		Transport: newHTTPTransport("", proxy, tlsConfig),
+		Timeout:   probeTimeout,
+	}
+}
+
+// newHTTPTransport returns a pooling transport. A network of "tcp4" or
+// "tcp6" restricts it to one address family; pools are per transport, so
+// each family needs its own to avoid reusing the other family's connections.
+func newHTTPTransport(network string, proxy *url.URL, tlsConfig *tls.Config) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   probeTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	dial := dialer.DialContext
+	if network != "" {
+		dial = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+
+	proxyFunc := http.ProxyFromEnvironment
+	if proxy != nil {
+		proxyFunc = http.ProxyURL(proxy)
+	}
+
+	return &http.Transport{
+		Proxy:                 proxyFunc,
+		DialContext:           dial,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   probeTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// drainAndClose discards what's left of a response body so the underlying
+// connection can go back to the pool
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(io.Discard, io.LimitReader(body, maxDrainSize))
+	body.Close()
+}
+
+// Address families an endpoint can be restricted to
+const (
+	familyIPv4 = "tcp4"
+	familyIPv6 = "tcp6"
+	familyBoth = "both" // Probe each family as a separate endpoint
+)
+
+// Probe kinds
+const (
+	probeHTTP = "http" // GET the URL and check the status code
+	probeTCP  = "tcp"  // Connect to host:port
+	probeDNS  = "dns"  // Resolve the host name
+	probeGRPC = "grpc" // Call the gRPC health checking service
+)
+
+// endpointDef describes an endpoint to monitor
+type endpointDef struct {
+	name          string
+	url           string        // http(s)://..., tcp://host:port, dns://hostname or grpc://host:port[/service]
+	kind          string        // Probe kind; derived from the URL scheme when empty
+	expectedCodes []int         // Acceptable HTTP status codes; empty means 200
+	headers       http.Header   // Extra request headers; "Host" overrides the request host
+	timeout       time.Duration // Probe timeout; zero means probeTimeout
+	network       string        // familyIPv4 or familyIPv6; empty lets the resolver choose
+	fullBody      bool          // Time the response to the end of the body rather than the headers
+
+	// Optional response body validation
+	bodyContains string         // Substring that must appear in the body
+	bodyPattern  *regexp.Regexp // Pattern that must match the body
+
+	// Region whose content the body should name, and the one a failover
+	// serves instead; matched case-insensitively
+	primaryRegion  string
+	fallbackRegion string
+}
+
+// timeoutOrDefault returns the endpoint's probe timeout
+func (ep endpointDef) timeoutOrDefault() time.Duration {
+	if ep.timeout > 0 {
+		return ep.timeout
+	}
+	return probeTimeout
+}
+
+// validatesBody reports whether the endpoint checks response content
+func (ep endpointDef) validatesBody() bool {
+	return ep.bodyContains != "" || ep.bodyPattern != nil || ep.primaryRegion != ""
+}
+
+// matchBody checks a response body against the expected content and returns
+// a description of what was expected when it doesn't match
+func (ep endpointDef) matchBody(body []byte) (bool, string) {
+	if ep.bodyContains != "" && !bytes.Contains(body, []byte(ep.bodyContains)) {
+		return false, fmt.Sprintf("body missing %q", ep.bodyContains)
+	}
+	if ep.bodyPattern != nil && !ep.bodyPattern.Match(body) {
+		return false, fmt.Sprintf("body doesn't match /%s/", ep.bodyPattern)
+	}
+	if ep.primaryRegion != "" && !containsFold(body, ep.primaryRegion) &&
+		(ep.fallbackRegion == "" || !containsFold(body, ep.fallbackRegion)) {
+		if ep.fallbackRegion == "" {
+			return false, fmt.Sprintf("body doesn't name %s", ep.primaryRegion)
+		}
+		return false, fmt.Sprintf("body names neither %s nor %s", ep.primaryRegion, ep.fallbackRegion)
+	}
+	return true, ""
+}
+
+// servesFallback reports whether a body that matched names the fallback
+// region rather than the primary one
+func (ep endpointDef) servesFallback(body []byte) bool {
+	return ep.primaryRegion != "" && !containsFold(body, ep.primaryRegion)
+}
+
+func containsFold(body []byte, text string) bool {
+	return bytes.Contains(bytes.ToLower(body), bytes.ToLower([]byte(text)))
+}
+
+// probeKind returns the endpoint's probe kind
+func (ep endpointDef) probeKind() string {
+	if ep.kind != "" {
+		return ep.kind
+	}
+	switch {
+	case strings.HasPrefix(ep.url, "tcp://"):
+		return probeTCP
+	case strings.HasPrefix(ep.url, "dns://"):
+		return probeDNS
+	case strings.HasPrefix(ep.url, "grpc://"):
+		return probeGRPC
+	default:
+		return probeHTTP
+	}
+}
+
+// withAddressFamilies replaces each endpoint set to probe both families with
+// an IPv4 and an IPv6 endpoint, so a partition of one family shows up
+// separately from the other
+func withAddressFamilies(endpoints []endpointDef) []endpointDef {
+	var result []endpointDef
+	for _, ep := range endpoints {
+		if ep.network != familyBoth {
+			result = append(result, ep)
+			continue
+		}
+		v4, v6 := ep, ep
+		v4.name, v4.network = ep.name+" [IPv4]", familyIPv4
+		v6.name, v6.network = ep.name+" [IPv6]", familyIPv6
+		result = append(result, v4, v6)
+	}
+	return result
+}
+
+// withNetworkProbes adds TCP-connect and DNS-resolution companions for each
+// HTTP endpoint. During a network partition these show whether the failure
+// is name resolution, the L4 connection, or the application itself.
+func withNetworkProbes(endpoints []endpointDef) []endpointDef {
+	var result []endpointDef
+	for _, ep := range endpoints {
+		result = append(result, ep)
+		if ep.probeKind() != probeHTTP {
+			continue
+		}
+
+		u, err := url.Parse(ep.url)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		port := u.Port()
+		if port == "" {
+			port = "80"
+			if u.Scheme == "https" {
+				port = "443"
+			}
+		}
+
+		result = append(result,
+			endpointDef{name: ep.name + " [TCP]", url: "tcp://" + net.JoinHostPort(u.Hostname(), port), timeout: ep.timeout, network: ep.network},
+			endpointDef{name: ep.name + " [DNS]", url: "dns://" + u.Hostname(), timeout: ep.timeout, network: ep.network},
+		)
+	}
+	return result
+}
+
+// checkEndpoint runs the probe matching the endpoint's kind
+func (m *model) checkEndpoint(ep endpointDef) models.EndpointStatus {
+	switch ep.probeKind() {
+	case probeTCP:
+		return m.checkTCPEndpoint(ep)
+	case probeDNS:
+		return m.checkDNSEndpoint(ep)
+	case probeGRPC:
+		return m.checkGRPCEndpoint(ep)
+	default:
+		return m.checkHTTPEndpoint(ep)
+	}
+}
+
+// checkTCPEndpoint measures how long it takes to open a TCP connection
+func (m *model) checkTCPEndpoint(ep endpointDef) models.EndpointStatus {
+	start := time.Now()
+	status := models.EndpointStatus{
+		LastChecked: start,
+	}
+
+	network := ep.network
+	if network == "" {
+		network = "tcp"
+	}
+	dialer := net.Dialer{Timeout: ep.timeoutOrDefault()}
+	conn, err := dialer.DialContext(m.ctx, network, strings.TrimPrefix(ep.url, "tcp://"))
+	status.ResponseTime = time.Since(start).Seconds()
+	if err != nil {
+		status.Status = networkErrorStatus(err)
+		return status
+	}
+	status.Address, status.Family = remoteAddress(conn.RemoteAddr())
+	conn.Close()
+
+	status.Status = "ok"
+	return status
+}
+
+// checkDNSEndpoint measures how long it takes to resolve a host name
+func (m *model) checkDNSEndpoint(ep endpointDef) models.EndpointStatus {
+	start := time.Now()
+	status := models.EndpointStatus{
+		LastChecked: start,
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, ep.timeoutOrDefault())
+	defer cancel()
+
+	network := "ip"
+	switch ep.network {
+	case familyIPv4:
+		network = "ip4"
+	case familyIPv6:
+		network = "ip6"
+	}
+	ips, err := net.DefaultResolver.LookupIP(ctx, network, strings.TrimPrefix(ep.url, "dns://"))
+	status.ResponseTime = time.Since(start).Seconds()
+	if err != nil || len(ips) == 0 {
+		status.Status = networkErrorStatus(err)
+		return status
+	}
+	status.Address, status.Family = ips[0].String(), ipFamily(ips[0])
+
+	status.Status = "ok"
+	return status
+}
+
+// remoteAddress returns the IP a connection reached and its family
+func remoteAddress(addr net.Addr) (string, string) {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return addr.String(), ""
+	}
+	return tcpAddr.IP.String(), ipFamily(tcpAddr.IP)
+}
+
+// ipFamily returns "ipv4" or "ipv6"
+func ipFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// networkErrorStatus maps a dial or lookup error to an endpoint status
+func networkErrorStatus(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	return "failed"
+}
+
+// acceptedCodes returns the status codes that count as "ok" for the endpoint
+func (ep endpointDef) acceptedCodes() []int {
+	if len(ep.expectedCodes) == 0 {
+		return []int{http.StatusOK}
+	}
+	return ep.expectedCodes
+}
+
+// checkHTTPEndpoint probes an HTTP endpoint, retrying requests that got no
+// response or a 5xx up to -retries times with a doubling backoff. Any
+// other status code, or a wrong body, is a definite answer and isn't
+// retried. All attempts share the endpoint's timeout, so retrying never
+// makes a check take longer.
+func (m *model) checkHTTPEndpoint(ep endpointDef) models.EndpointStatus {
+	if m.cfg.retries == 0 {
+		return m.checkHTTPOnce(m.ctx, ep)
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(m.ctx, ep.timeoutOrDefault())
+	defer cancel()
+
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		status := m.checkHTTPOnce(ctx, ep)
+		status.LastChecked = start
+		status.Retries = attempt
+		definite := status.HTTPCode != 0 && status.HTTPCode < http.StatusInternalServerError
+		if definite || status.Status == "ok" || attempt == m.cfg.retries {
+			return status
+		}
+		// Give up rather than start an attempt the budget can't cover
+		if deadline, _ := ctx.Deadline(); time.Until(deadline) <= backoff {
+			return status
+		}
+		select {
+		case <-ctx.Done():
+			return status
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// checkHTTPOnce makes a single request to an HTTP endpoint
+func (m *model) checkHTTPOnce(ctx context.Context, ep endpointDef) models.EndpointStatus {
+	start := time.Now()
+	status := models.EndpointStatus{
+		LastChecked:   start,
+		ExpectedCodes: ep.acceptedCodes(),
+	}
+
+	client := m.client
+	if expectsRedirect(status.ExpectedCodes) || ep.timeoutOrDefault() != m.client.Timeout || ep.network != "" {
+		// Copies share the transport, so pooled connections are still reused
+		c := *m.client
+		c.Timeout = ep.timeoutOrDefault()
+		if ep.network != "" {
+			c.Transport = m.familyTransport(ep.network)
+		}
+		if expectsRedirect(status.ExpectedCodes) {
+			// Don't follow redirects so a 301/302 can be asserted directly
+			c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+		}
+		client = &c
+	}
+
+	// Note which address the request went to, whether dialed or pooled
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			status.Address, status.Family = remoteAddress(info.Conn.RemoteAddr())
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodGet, ep.url, nil)
+	if err != nil {
+		status.Status = "failed"
+		return status
+	}
+	for key, values := range ep.headers {
+		// The Host header is ignored by the transport; it must be set on the request
+		if http.CanonicalHeaderKey(key) == "Host" {
+			req.Host = values[0]
+			continue
+		}
+		req.Header[http.CanonicalHeaderKey(key)] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The retry budget's deadline can expire before the client's own timeout
+		if strings.Contains(err.Error(), "timeout") || errors.Is(err, context.DeadlineExceeded) {
+			status.Status = "timeout"
+		} else {
+			status.Status = "failed"
+		}
+		if isTLSError(err) {
+			status.ErrorKind = "tls"
+			status.Reason = "TLS: " + tlsErrorMessage(err)
+		}
+		status.ResponseTime = time.Since(start).Seconds()
+		return status
+	}
+	defer drainAndClose(resp.Body)
+
+	status.HTTPCode = resp.StatusCode
+
+	// A body streamed slowly after quick headers only shows when it's read
+	var body []byte
+	if ep.fullBody {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	}
+	status.ResponseTime = time.Since(start).Seconds()
+	if err != nil {
+		if strings.Contains(err.Error(), "timeout") || errors.Is(err, context.DeadlineExceeded) {
+			status.Status = "timeout"
+		} else {
+			status.Status = "failed"
+		}
+		status.Reason = "error reading body: " + err.Error()
+		return status
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests && !isExpectedStatus(resp.StatusCode, status.ExpectedCodes) {
+		// Classify like the AWS layer does for SlowDown/ThrottlingException
+		status.Status = "throttled"
+		status.RetryAfter = resp.Header.Get("Retry-After")
+		status.Reason = "throttled (429)"
+		if status.RetryAfter != "" {
+			status.Reason += ", retry after " + formatRetryAfter(status.RetryAfter)
+		}
+		return status
+	}
+
+	if !isExpectedStatus(resp.StatusCode, status.ExpectedCodes) {
+		status.Status = "failed"
+		status.Reason = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+		return status
+	}
+
+	if ep.validatesBody() {
+		if !ep.fullBody {
+			if body, err = io.ReadAll(io.LimitReader(resp.Body, maxBodySize)); err != nil {
+				status.Status = "failed"
+				status.Reason = "error reading body: " + err.Error()
+				return status
+			}
+		}
+		if ok, reason := ep.matchBody(body); !ok {
+			status.Status = "failed"
+			status.ContentMatch = "mismatched"
+			status.Reason = "content mismatch: " + reason
+			return status
+		}
+		status.ContentMatch = "matched"
+		if ep.servesFallback(body) {
+			// Up, but only because the failover region took over
+			status.Status = "serving-fallback"
+			status.Reason = fmt.Sprintf("serving %s content instead of %s", ep.fallbackRegion, ep.primaryRegion)
+			return status
+		}
+	}
+
+	status.Status = "ok"
+	return status
+}
+
+// familyTransport returns the shared transport restricted to network,
+// creating it on first use
+func (m *model) familyTransport(network string) *http.Transport {
+	if m.familyTransports == nil {
+		m.familyTransports = make(map[string]*http.Transport)
+	}
+	transport, ok := m.familyTransports[network]
+	if !ok {
+		transport = newHTTPTransport(network, m.cfg.proxy, m.cfg.tlsConfig)
+		m.familyTransports[network] = transport
+	}
+	return transport
+}
+
+// formatRetryAfter renders a Retry-After value, which is either a number of
+// seconds or an HTTP date
+func formatRetryAfter(value string) string {
+	if secs, err := strconv.Atoi(value); err == nil {
+		return (time.Duration(secs) * time.Second).String()
+	}
+	if when, err := http.ParseTime(value); err == nil {
+		return time.Until(when).Round(time.Second).String()
+	}
+	return value
+}
+
+// expectsRedirect reports whether any expected code is a 3xx redirect
+func expectsRedirect(expected []int) bool {
+	for _, c := range expected {
+		if c >= 300 && c < 400 {
+			return true
+		}
+	}
+	return false
+}
+
+// isExpectedStatus reports whether code is one of the expected status codes
+func isExpectedStatus(code int, expected []int) bool {
+	for _, c := range expected {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
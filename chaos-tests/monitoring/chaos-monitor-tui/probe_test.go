@@ -0,0 +1,450 @@
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestCheckHTTPEndpointExpectedCodes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/no-content", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/missing", http.StatusMovedPermanently)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		codes      []int
+		wantStatus string
+		wantCode   int
+	}{
+		{"204 expected", "/no-content", []int{204}, "ok", 204},
+		{"204 unexpected", "/no-content", nil, "failed", 204},
+		{"301 expected isn't followed", "/moved", []int{200, 301}, "ok", 301},
+		{"301 unexpected is followed", "/moved", nil, "failed", 404},
+	}
+	m := newTestModel(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := m.checkHTTPEndpoint(endpointDef{name: tt.name, url: server.URL + tt.path, expectedCodes: tt.codes})
+			if status.Status != tt.wantStatus || status.HTTPCode != tt.wantCode {
+				t.Errorf("got %s (%d), want %s (%d)", status.Status, status.HTTPCode, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestExpectStatusFlag(t *testing.T) {
+	codes := statusCodesFlag{}
+	if err := codes.Set("Health = 200, 204"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(codes["Health"], []int{200, 204}) || codes.String() != "Health=200,204" {
+		t.Errorf("parsed %v", codes)
+	}
+	for _, bad := range []string{"Health=200,abc", "Health=99", "Health", "=200"} {
+		if err := codes.Set(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+
+	ep := endpointDef{name: "Health", url: "http://localhost/health"}
+	config{expectStatus: codes}.applyEndpointSettings(&ep)
+	if !slices.Equal(ep.acceptedCodes(), []int{200, 204}) {
+		t.Errorf("-expect-status not applied: %v", ep.expectedCodes)
+	}
+	other := endpointDef{name: "Main Site"}
+	config{expectStatus: codes}.applyEndpointSettings(&other)
+	if !slices.Equal(other.acceptedCodes(), []int{200}) {
+		t.Errorf("default codes = %v, want 200", other.acceptedCodes())
+	}
+}
+
+func TestExpectedCodesSettings(t *testing.T) {
+	ep, err := parseUserEndpoint("Health=http://localhost/health 200,204")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.url != "http://localhost/health" || !slices.Equal(ep.expectedCodes, []int{200, 204}) {
+		t.Errorf("parsed %+v", ep)
+	}
+	if _, err := parseUserEndpoint("Health=http://localhost/health 200,abc"); err == nil {
+		t.Error("invalid status code accepted")
+	}
+
+	endpoints := withUserEndpoints([]endpointDef{{name: "Main Site", url: "http://old"}}, []userEndpoint{ep})
+	if !slices.Equal(endpoints[1].expectedCodes, []int{200, 204}) {
+		t.Errorf("endpoints file codes not applied: %+v", endpoints[1])
+	}
+
+	// -expect-status overrides the endpoints file
+	cfg := config{expectStatus: statusCodesFlag{"Health": {301}}}
+	cfg.applyEndpointSettings(&endpoints[1])
+	if !slices.Equal(endpoints[1].expectedCodes, []int{301}) {
+		t.Errorf("-expect-status not applied: %v", endpoints[1].expectedCodes)
+	}
+}
+
+// probeAllocs returns the allocations per HTTP probe, with a client per
+// probe as before the shared client or with the model's shared one
+func probeAllocs(m *model, url string, perProbe bool, runs int) float64 {
+	ep := endpointDef{name: "Main Site", url: url}
+	return testing.AllocsPerRun(runs, func() {
+		if perProbe {
+			m.client = newHTTPClient(nil, nil)
+			defer m.client.CloseIdleConnections()
+		}
+		m.checkHTTPEndpoint(ep)
+	})
+}
+
+func TestSharedClientAllocatesLess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	m := newTestModel(t)
+	shared := probeAllocs(&m, server.URL, false, 50)
+	perProbe := probeAllocs(&m, server.URL, true, 50)
+	if shared >= perProbe {
+		t.Errorf("shared client allocates %.0f per probe, a client per probe %.0f", shared, perProbe)
+	}
+}
+
+func BenchmarkProbeSharedClient(b *testing.B) {
+	benchmarkProbe(b, false)
+}
+
+func BenchmarkProbeClientPerProbe(b *testing.B) {
+	benchmarkProbe(b, true)
+}
+
+func benchmarkProbe(b *testing.B, perProbe bool) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	m := newTestModel(b)
+	ep := endpointDef{name: "Main Site", url: server.URL}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if perProbe {
+			m.client = newHTTPClient(nil, nil)
+		}
+		m.checkHTTPEndpoint(ep)
+		if perProbe {
+			m.client.CloseIdleConnections()
+		}
+	}
+}
+
+func TestCheckHTTPEndpointBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html><title>Maintenance</title></html>")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		ep         endpointDef
+		wantStatus string
+		wantMatch  string
+		wantReason string
+	}{
+		{"contains", endpointDef{bodyContains: "Maintenance"}, "ok", "matched", ""},
+		{"missing text", endpointDef{bodyContains: "Welcome"}, "failed", "mismatched", `content mismatch: body missing "Welcome"`},
+		{"pattern", endpointDef{bodyPattern: regexp.MustCompile(`<title>\w+</title>`)}, "ok", "matched", ""},
+		{"pattern mismatch", endpointDef{bodyPattern: regexp.MustCompile(`Welcome \d+`)}, "failed", "mismatched", `content mismatch: body doesn't match /Welcome \d+/`},
+		{"no validation", endpointDef{}, "ok", "", ""},
+	}
+	m := newTestModel(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ep.name, tt.ep.url = tt.name, server.URL
+			status := m.checkHTTPEndpoint(tt.ep)
+			if status.Status != tt.wantStatus || status.ContentMatch != tt.wantMatch || status.Reason != tt.wantReason || status.HTTPCode != 200 {
+				t.Errorf("got %s, match %q, reason %q (%d)", status.Status, status.ContentMatch, status.Reason, status.HTTPCode)
+			}
+		})
+	}
+}
+
+func TestCustomHeadersSent(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+	}))
+	defer server.Close()
+
+	headers := headerFlag{}
+	for _, value := range []string{
+		"*=X-Chaos-Monitor: yes",
+		"*=Authorization: Bearer shared",
+		"Main Site=Authorization: Bearer site",
+		"Main Site=Host: www.example.com",
+		"Main Site=Accept: text/html",
+		"Main Site=Accept: application/json",
+	} {
+		if err := headers.Set(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := config{headers: headers}
+	ep := endpointDef{name: "Main Site", url: server.URL}
+	cfg.applyEndpointSettings(&ep)
+
+	m := newTestModel(t)
+	if status := m.checkHTTPEndpoint(ep); status.Status != "ok" {
+		t.Fatalf("probe %s: %s", status.Status, status.Reason)
+	}
+	r := <-received
+	if r.Host != "www.example.com" {
+		t.Errorf("Host = %q", r.Host)
+	}
+	for key, want := range map[string][]string{
+		"X-Chaos-Monitor": {"yes"},
+		"Authorization":   {"Bearer site"}, // The endpoint's own header wins over "*"
+		"Accept":          {"text/html", "application/json"},
+	} {
+		if got := r.Header.Values(key); !slices.Equal(got, want) {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestCheckHTTPEndpointThrottled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if retryAfter := r.URL.Query().Get("retry-after"); retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		query          string
+		codes          []int
+		wantStatus     string
+		wantRetryAfter string
+		wantReason     string
+	}{
+		{"without Retry-After", "", nil, "throttled", "", "throttled (429)"},
+		{"with Retry-After seconds", "?retry-after=30", nil, "throttled", "30", "throttled (429), retry after 30s"},
+		{"unparseable Retry-After", "?retry-after=soon", nil, "throttled", "soon", "throttled (429), retry after soon"},
+		{"429 expected", "?retry-after=30", []int{429}, "ok", "", ""},
+	}
+	m := newTestModel(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := m.checkHTTPEndpoint(endpointDef{name: tt.name, url: server.URL + tt.query, expectedCodes: tt.codes})
+			if status.Status != tt.wantStatus || status.RetryAfter != tt.wantRetryAfter || status.Reason != tt.wantReason || status.HTTPCode != 429 {
+				t.Errorf("got %s, retry after %q, reason %q (%d)", status.Status, status.RetryAfter, status.Reason, status.HTTPCode)
+			}
+		})
+	}
+
+	// An HTTP date is shown as the time left
+	if got := formatRetryAfter(time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat)); got != "1m30s" && got != "1m29s" {
+		t.Errorf("formatRetryAfter(date) = %q", got)
+	}
+}
+
+func TestPerEndpointTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	cfg := config{timeouts: map[string]time.Duration{"Fast": 100 * time.Millisecond, "Slow": 2 * time.Second}}
+	fast := endpointDef{name: "Fast", url: server.URL}
+	slow := endpointDef{name: "Slow", url: server.URL}
+	cfg.applyEndpointSettings(&fast)
+	cfg.applyEndpointSettings(&slow)
+
+	m := newTestModel(t)
+	start := time.Now()
+	status := m.checkHTTPEndpoint(fast)
+	if elapsed := time.Since(start); status.Status != "timeout" || elapsed > 250*time.Millisecond {
+		t.Errorf("100ms timeout: %s after %v", status.Status, elapsed)
+	}
+	if status := m.checkHTTPEndpoint(slow); status.Status != "ok" {
+		t.Errorf("2s timeout: %s (%s)", status.Status, status.Reason)
+	}
+	// The shared client keeps the default
+	if m.client.Timeout != probeTimeout {
+		t.Errorf("shared client timeout changed to %v", m.client.Timeout)
+	}
+}
+
+// newLoopbackServer starts a test server on the loopback address of
+// network, or skips the test if that family isn't available
+func newLoopbackServer(t *testing.T, network, addr string) *httptest.Server {
+	t.Helper()
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		t.Skipf("no %s loopback: %v", network, err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Listener.Close()
+	server.Listener = ln
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAddressFamilies(t *testing.T) {
+	endpoints := withAddressFamilies([]endpointDef{{name: "Main Site", network: familyBoth}, {name: "API"}})
+	if len(endpoints) != 3 || endpoints[0].name != "Main Site [IPv4]" || endpoints[0].network != familyIPv4 ||
+		endpoints[1].name != "Main Site [IPv6]" || endpoints[1].network != familyIPv6 || endpoints[2].network != "" {
+		t.Errorf("withAddressFamilies = %+v", endpoints)
+	}
+
+	m := newTestModel(t)
+	probe := func(url, network string) models.EndpointStatus {
+		return m.checkHTTPEndpoint(endpointDef{name: network, url: url, network: network})
+	}
+
+	t.Run("IPv4", func(t *testing.T) {
+		server := newLoopbackServer(t, "tcp4", "127.0.0.1:0")
+		_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		url := "http://localhost:" + port
+		if status := probe(url, familyIPv4); status.Status != "ok" || status.Address != "127.0.0.1" || status.Family != "ipv4" {
+			t.Errorf("tcp4: %s at %s (%s)", status.Status, status.Address, status.Family)
+		}
+		if status := probe(url, familyIPv6); status.Status == "ok" || status.Family == "ipv4" {
+			t.Errorf("tcp6 reached the IPv4 listener: %s at %s", status.Status, status.Address)
+		}
+	})
+	t.Run("IPv6", func(t *testing.T) {
+		server := newLoopbackServer(t, "tcp6", "[::1]:0")
+		url := "http://" + server.Listener.Addr().String()
+		if status := probe(url, familyIPv6); status.Status != "ok" || status.Address != "::1" || status.Family != "ipv6" {
+			t.Errorf("tcp6: %s at %s (%s)", status.Status, status.Address, status.Family)
+		}
+		if status := probe(url, familyIPv4); status.Status == "ok" {
+			t.Errorf("tcp4 reached the IPv6 listener at %s", status.Address)
+		}
+	})
+}
+
+func TestProbeThroughProxy(t *testing.T) {
+	requested := make(chan string, 2)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxy is sent the absolute URL to fetch
+		requested <- r.URL.String()
+		io.WriteString(w, "proxied")
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newTestModel(t)
+	m.cfg.proxy = proxyURL
+	m.client = newHTTPClient(proxyURL, nil)
+
+	// The host doesn't resolve, so only the proxy can answer
+	for _, ep := range []endpointDef{
+		{name: "Shared client", url: "http://site.chaos.invalid/health", bodyContains: "proxied"},
+		{name: "IPv4 client", url: "http://site.chaos.invalid/v4", bodyContains: "proxied", network: familyIPv4},
+	} {
+		if status := m.checkHTTPEndpoint(ep); status.Status != "ok" {
+			t.Errorf("%s: %s (%s)", ep.name, status.Status, status.Reason)
+			continue
+		}
+		if got, want := <-requested, ep.url; got != want {
+			t.Errorf("%s: proxy was asked for %q, want %q", ep.name, got, want)
+		}
+	}
+}
+
+func TestCheckHTTPEndpointRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		firstCode   int
+		wantStatus  string
+		wantCode    int
+		wantRetries int
+	}{
+		{"503 is retried", http.StatusServiceUnavailable, "ok", 200, 1},
+		{"500 is retried", http.StatusInternalServerError, "ok", 200, 1},
+		{"404 is definite", http.StatusNotFound, "failed", 404, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 {
+					w.WriteHeader(tt.firstCode)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			m := newTestModel(t)
+			m.cfg.retries = 2
+			status := m.checkHTTPEndpoint(endpointDef{name: tt.name, url: server.URL, timeout: 5 * time.Second})
+			if status.Status != tt.wantStatus || status.HTTPCode != tt.wantCode || status.Retries != tt.wantRetries {
+				t.Errorf("got %s (%d) after %d retries, want %s (%d) after %d", status.Status, status.HTTPCode, status.Retries, tt.wantStatus, tt.wantCode, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestBodyTiming(t *testing.T) {
+	const bodyDelay = 300 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(bodyDelay):
+			w.Write([]byte("<title>Welcome</title>"))
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	m := newTestModel(t)
+	m.cfg.timing = keyValueFlag{"*": timingBody, "Fast Site": timingHeaders}
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		status  string
+		slow    bool // Whether the body delay counts
+	}{
+		{"Fast Site", 5 * time.Second, "ok", false},
+		{"Slow Site", 5 * time.Second, "ok", true},
+		{"Stalled Site", 100 * time.Millisecond, "timeout", false},
+	}
+	for _, tt := range tests {
+		ep := endpointDef{name: tt.name, url: server.URL, timeout: tt.timeout, bodyContains: "Welcome"}
+		m.cfg.applyEndpointSettings(&ep)
+		status := m.checkHTTPEndpoint(ep)
+		if status.Status != tt.status {
+			t.Errorf("%s: %s (%s), want %s", tt.name, status.Status, status.Reason, tt.status)
+		}
+		if slow := status.ResponseTime >= bodyDelay.Seconds(); slow != tt.slow {
+			t.Errorf("%s: response time %.3fs, body delay counted %v, want %v", tt.name, status.ResponseTime, slow, tt.slow)
+		}
+	}
+}
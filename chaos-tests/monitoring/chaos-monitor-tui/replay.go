@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// maxRecordLine bounds a single recorded state; long sessions with many
+// services produce large lines
+const maxRecordLine = 16 * 1024 * 1024
+
+// recordedState is one line of a -record file
+type recordedState struct {
+	Time  time.Time           `json:"time"`
+	State models.MonitorState `json:"state"`
+}
+
+// recorder appends each tick's state to a JSON lines file
+type recorder struct {
+	file *os.File
+	enc  *json.Encoder
+}
+
+func newRecorder(path string) (*recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &recorder{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+// write appends state; a failed write is dropped rather than interrupting
+// the dashboard
+func (r *recorder) write(state *models.MonitorState) {
+	r.enc.Encode(recordedState{Time: time.Now(), State: *state})
+}
+
+func (r *recorder) close() error {
+	return r.file.Close()
+}
+
+// replaySession steps through the states of a recorded session
+type replaySession struct {
+	frames  []recordedState
+	pos     int
+	speed   float64 // Playback speed multiplier
+	skipped int     // Corrupt or truncated lines that were ignored
+}
+
+// loadReplay reads a -record file, skipping lines that don't parse so a
+// session cut off mid-write can still be replayed
+func loadReplay(path string, speed float64) (*replaySession, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	session := &replaySession{speed: speed}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordLine)
+	for scanner.Scan() {
+		var frame recordedState
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			session.skipped++
+			continue
+		}
+		session.frames = append(session.frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		// An oversized final line is treated like a truncated one
+		session.skipped++
+	}
+
+	if len(session.frames) == 0 {
+		return nil, fmt.Errorf("no recorded states in %s", path)
+	}
+	return session, nil
+}
+
+// current returns the state at the playback position
+func (r *replaySession) current() models.MonitorState {
+	return r.frames[r.pos].State
+}
+
+// step moves the playback position by delta, staying within the recording
+func (r *replaySession) step(delta int) {
+	r.pos += delta
+	if r.pos < 0 {
+		r.pos = 0
+	}
+	if r.pos >= len(r.frames) {
+		r.pos = len(r.frames) - 1
+	}
+}
+
+// done reports whether playback has reached the last recorded state
+func (r *replaySession) done() bool {
+	return r.pos == len(r.frames)-1
+}
+
+// nextDelay is how long to wait before showing the next state, following
+// the recorded timestamps scaled by the playback speed
+func (r *replaySession) nextDelay() time.Duration {
+	if r.done() {
+		return updateInterval
+	}
+	delay := r.frames[r.pos+1].Time.Sub(r.frames[r.pos].Time)
+	if delay <= 0 {
+		return updateInterval
+	}
+	return time.Duration(float64(delay) / r.speed)
+}
+
+// label describes the playback position for the title bar
+func (r *replaySession) label() string {
+	label := fmt.Sprintf("REPLAY %d/%d @ %s", r.pos+1, len(r.frames), r.frames[r.pos].Time.Format("15:04:05"))
+	if r.skipped > 0 {
+		label += fmt.Sprintf(" (%d bad lines skipped)", r.skipped)
+	}
+	return label
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// reportFormats maps each -report format to its file extension
+var reportFormats = map[string]string{
+	"md":   ".md",
+	"html": ".html",
+}
+
+// writeReport writes a Markdown incident report of the session so far.
+// Percentiles cover the recent check history, like the CSV export.
+func (m *model) writeReport(w io.Writer, now time.Time) error {
+	var b strings.Builder
+	start := m.state.Stats.StartTime
+
+	b.WriteString("# Chaos Engineering Incident Report\n\n")
+	fmt.Fprintf(&b, "- **Generated:** %s\n", now.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(&b, "- **Session:** %s – %s (%s)\n",
+		start.Format("2006-01-02 15:04:05"), now.Format("2006-01-02 15:04:05"), now.Sub(start).Round(time.Second))
+	fmt.Fprintf(&b, "- **Target:** %s (%s)\n", m.currentTarget().name, m.currentTarget().baseURL)
+	fmt.Fprintf(&b, "- **Refreshes:** %d\n", m.state.UpdateCount)
+	if m.peakIntensityAt.IsZero() {
+		b.WriteString("- **Peak chaos intensity:** 0/100\n")
+	} else {
+		fmt.Fprintf(&b, "- **Peak chaos intensity:** %.0f/100 at %s\n", m.peakIntensity, m.peakIntensityAt.Format("15:04:05"))
+	}
+	fmt.Fprintf(&b, "- **System availability:** %s\n", formatSystemAvailability(&m.state))
+
+	b.WriteString("\n## Service availability\n\n")
+	if len(m.state.Stats.ServiceStats) == 0 {
+		b.WriteString("No services were checked.\n")
+	} else {
+		b.WriteString("| Service | Checks | Availability | Downtime | p50 (s) | p90 (s) | p99 (s) |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+		for _, name := range sortedKeys(m.state.Stats.ServiceStats) {
+			stats := m.state.Stats.ServiceStats[name]
+			writeReportRow(&b, name, stats.TotalChecks, stats.AvailabilityPct, stats.Downtime, m.history["service|"+name])
+		}
+	}
+
+	b.WriteString("\n## Endpoint availability\n\n")
+	if len(m.state.Stats.NginxStats) == 0 {
+		b.WriteString("No endpoints were checked.\n")
+	} else {
+		b.WriteString("| Endpoint | Checks | Success rate | Downtime | p50 (s) | p90 (s) | p99 (s) |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+		for _, name := range sortedKeys(m.state.Stats.NginxStats) {
+			stats := m.state.Stats.NginxStats[name]
+			writeReportRow(&b, name, stats.TotalChecks, stats.SuccessRate, stats.Downtime, m.history["endpoint|"+name])
+		}
+	}
+
+	b.WriteString("\n## Chaos test timeline\n\n")
+	tests := reportTests(&m.state)
+	if len(tests) == 0 {
+		b.WriteString("No chaos tests were detected.\n")
+	} else {
+		b.WriteString("| Start | End | Duration | Type | Target | Source | Details |\n")
+		b.WriteString("|---|---|---:|---|---|---|---|\n")
+		for _, test := range tests {
+			end, duration := "ongoing", now.Sub(test.StartTime)
+			if !test.EndTime.IsZero() {
+				end, duration = test.EndTime.Format("15:04:05"), test.Duration()
+			}
+			target := test.Target
+			if test.Impact != "" {
+				target += " → " + test.Impact
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				test.StartTime.Format("15:04:05"), end, duration.Round(time.Second),
+				markdownCell(test.Type), markdownCell(target), markdownCell(test.Source), markdownCell(test.Details))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatSystemAvailability renders the weighted availability of the whole
+// system, or n/a before anything has been checked
+func formatSystemAvailability(state *models.MonitorState) string {
+	if state.SystemAvailability == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f%%", *state.SystemAvailability)
+}
+
+// writeReportRow writes one service or endpoint row of an availability table
+func writeReportRow(b *strings.Builder, name string, total int, pct float64, downtime time.Duration, samples []models.CheckSample) {
+	times := sortedResponseTimes(samples)
+	fmt.Fprintf(b, "| %s | %d | %.2f%% | %s |", markdownCell(name), total, pct, downtime.Round(time.Second))
+	for _, p := range []float64{50, 90, 99} {
+		value := formatPercentile(times, p)
+		if value == "" {
+			value = "–"
+		}
+		b.WriteString(" " + value + " |")
+	}
+	b.WriteString("\n")
+}
+
+// reportTests returns the active and completed tests, oldest first
+func reportTests(state *models.MonitorState) []models.ActiveChaosTest {
+	tests := append(append([]models.ActiveChaosTest{}, state.CompletedTests...), state.ActiveTests...)
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].StartTime.Before(tests[j].StartTime)
+	})
+	return tests
+}
+
+// markdownCell makes text safe to place in a table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// writeReportFile writes the report to path in the -report format
+func (m *model) writeReportFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	write := m.writeReport
+	if m.cfg.reportFormat == "html" {
+		write = m.writeHTMLReport
+	}
+	if err := write(f, time.Now()); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// reportPath returns the -report path, or a timestamped file in the
+// current directory when none was given
+func (m *model) reportPath() string {
+	if m.cfg.reportPath != "" {
+		return m.cfg.reportPath
+	}
+	ext, ok := reportFormats[m.cfg.reportFormat]
+	if !ok {
+		ext = reportFormats["md"]
+	}
+	return filepath.Join(".", "chaos-report-"+time.Now().Format("20060102-150405")+ext)
+}
+
+// generateReport writes a report and records the result in the event log
+func (m *model) generateReport() {
+	path := m.reportPath()
+	event := models.Event{Time: time.Now(), Severity: "info", Kind: "report"}
+	if err := m.writeReportFile(path); err != nil {
+		event.Severity = "error"
+		event.Message = fmt.Sprintf("Report failed: %v", err)
+	} else {
+		event.Message = "Incident report written to " + path
+	}
+	m.appendEvents([]models.Event{event})
+}
+
+// trackPeakIntensity remembers the highest chaos intensity seen
+func (m *model) trackPeakIntensity() {
+	if m.state.ChaosIntensity > m.peakIntensity || m.peakIntensityAt.IsZero() {
+		m.peakIntensity = m.state.ChaosIntensity
+		m.peakIntensityAt = m.state.LastUpdate
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+	"chaos-monitor-tui/ui"
+)
+
+// Sparkline and timeline sizes, in SVG user units
+const (
+	sparkWidth    = 160
+	sparkHeight   = 28
+	timelineWidth = 720
+	timelineRow   = 18
+)
+
+// htmlReport is the data behind htmlReportTemplate
+type htmlReport struct {
+	Generated string
+	Session   string
+	Target    string
+	Refreshes int
+	Peak      string
+	System    string // Weighted availability of all endpoints and services
+	Services  []htmlReportRow
+	Endpoints []htmlReportRow
+	Tests     []htmlReportTest
+	Timeline  template.HTML
+}
+
+// htmlReportRow is one row of an availability table
+type htmlReportRow struct {
+	Name         string
+	Checks       int
+	Availability string
+	Class        string // good, warn or bad, for the availability colour
+	Downtime     string
+	P50, P90     string
+	P99          string
+	Sparkline    template.HTML
+}
+
+// htmlReportTest is one row of the chaos test timeline
+type htmlReportTest struct {
+	Start, End string
+	Duration   string
+	Type       string
+	Target     string
+	Source     string
+	Details    string
+}
+
+// writeHTMLReport writes the same report as writeReport as a single HTML
+// page. The CSS and charts are inline so it opens offline.
+func (m *model) writeHTMLReport(w io.Writer, now time.Time) error {
+	start := m.state.Stats.StartTime
+	report := htmlReport{
+		Generated: now.Format("2006-01-02 15:04:05 MST"),
+		Session: fmt.Sprintf("%s – %s (%s)",
+			start.Format("2006-01-02 15:04:05"), now.Format("2006-01-02 15:04:05"), now.Sub(start).Round(time.Second)),
+		Target:    fmt.Sprintf("%s (%s)", m.currentTarget().name, m.currentTarget().baseURL),
+		Refreshes: m.state.UpdateCount,
+		Peak:      "0/100",
+		System:    formatSystemAvailability(&m.state),
+	}
+	if !m.peakIntensityAt.IsZero() {
+		report.Peak = fmt.Sprintf("%.0f/100 at %s", m.peakIntensity, m.peakIntensityAt.Format("15:04:05"))
+	}
+
+	for _, name := range sortedKeys(m.state.Stats.ServiceStats) {
+		stats := m.state.Stats.ServiceStats[name]
+		report.Services = append(report.Services,
+			htmlRow(name, stats.TotalChecks, stats.AvailabilityPct, stats.Downtime, m.history["service|"+name]))
+	}
+	for _, name := range sortedKeys(m.state.Stats.NginxStats) {
+		stats := m.state.Stats.NginxStats[name]
+		report.Endpoints = append(report.Endpoints,
+			htmlRow(name, stats.TotalChecks, stats.SuccessRate, stats.Downtime, m.history["endpoint|"+name]))
+	}
+
+	tests := reportTests(&m.state)
+	for _, test := range tests {
+		end, duration := "ongoing", now.Sub(test.StartTime)
+		if !test.EndTime.IsZero() {
+			end, duration = test.EndTime.Format("15:04:05"), test.Duration()
+		}
+		target := test.Target
+		if test.Impact != "" {
+			target += " → " + test.Impact
+		}
+		report.Tests = append(report.Tests, htmlReportTest{
+			Start:    test.StartTime.Format("15:04:05"),
+			End:      end,
+			Duration: duration.Round(time.Second).String(),
+			Type:     test.Type,
+			Target:   target,
+			Source:   test.Source,
+			Details:  test.Details,
+		})
+	}
+	report.Timeline = timelineSVG(tests, start, now)
+
+	return htmlReportTemplate.Execute(w, report)
+}
+
+// htmlRow builds an availability table row
+func htmlRow(name string, total int, pct float64, downtime time.Duration, samples []models.CheckSample) htmlReportRow {
+	times := sortedResponseTimes(samples)
+	row := htmlReportRow{
+		Name:         name,
+		Checks:       total,
+		Availability: fmt.Sprintf("%.2f%%", pct),
+		Class:        ui.AvailabilityLevel(pct),
+		Downtime:     downtime.Round(time.Second).String(),
+		Sparkline:    sparklineSVG(samples),
+	}
+	for _, p := range []struct {
+		pct float64
+		dst *string
+	}{{50, &row.P50}, {90, &row.P90}, {99, &row.P99}} {
+		*p.dst = formatPercentile(times, p.pct)
+		if *p.dst == "" {
+			*p.dst = "–"
+		}
+	}
+	return row
+}
+
+// sparklineSVG draws the response times of the recent checks as a line,
+// with failed checks marked in red. It holds only numbers, so it's safe to
+// embed unescaped.
+func sparklineSVG(samples []models.CheckSample) template.HTML {
+	if len(samples) == 0 {
+		return ""
+	}
+	peak := 0.0
+	for _, s := range samples {
+		peak = max(peak, s.ResponseTime)
+	}
+	if peak == 0 {
+		peak = 1
+	}
+
+	step := 0.0
+	if len(samples) > 1 {
+		step = float64(sparkWidth) / float64(len(samples)-1)
+	}
+	var points, failures strings.Builder
+	for i, s := range samples {
+		x := float64(i) * step
+		y := sparkHeight - 2 - s.ResponseTime/peak*(sparkHeight-4)
+		fmt.Fprintf(&points, "%.1f,%.1f ", x, y)
+		if !s.OK {
+			fmt.Fprintf(&failures, `<circle cx="%.1f" cy="%.1f" r="2" class="fail"/>`, x, y)
+		}
+	}
+	return template.HTML(fmt.Sprintf(
+		`<svg class="spark" width="%d" height="%d" viewBox="0 0 %d %d"><polyline points="%s"/>%s</svg>`,
+		sparkWidth, sparkHeight, sparkWidth, sparkHeight, strings.TrimSpace(points.String()), failures.String()))
+}
+
+// timelineSVG draws each test as a bar across the session, one per row.
+// Labels are left to the table below it, so only numbers are embedded.
+func timelineSVG(tests []models.ActiveChaosTest, start, now time.Time) template.HTML {
+	span := now.Sub(start)
+	if len(tests) == 0 || span <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	height := len(tests) * timelineRow
+	fmt.Fprintf(&b, `<svg class="timeline" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		timelineWidth, height, timelineWidth, height)
+	for i, test := range tests {
+		end := now
+		class := "active"
+		if !test.EndTime.IsZero() {
+			end, class = test.EndTime, "done"
+		}
+		x := max(0, float64(test.StartTime.Sub(start))/float64(span)*timelineWidth)
+		width := max(2, float64(end.Sub(test.StartTime))/float64(span)*timelineWidth)
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%d" rx="3" class="%s"/>`,
+			x, i*timelineRow+3, width, timelineRow-6, class)
+	}
+	b.WriteString("</svg>")
+	return template.HTML(b.String())
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Chaos Engineering Incident Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; color: #222; padding: 0 1em; }
+h1 { border-bottom: 2px solid #7d56f4; padding-bottom: .3em; }
+h2 { margin-top: 1.8em; color: #444; }
+dl { display: grid; grid-template-columns: max-content auto; gap: .3em 1em; }
+dt { font-weight: bold; }
+dd { margin: 0; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #ddd; padding: .4em .6em; text-align: left; vertical-align: middle; }
+th { background: #f4f2fb; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.good { color: #1a7f37; }
+.warn { color: #b08800; }
+.bad { color: #cf222e; font-weight: bold; }
+svg.spark polyline { fill: none; stroke: #7d56f4; stroke-width: 1.5; }
+svg.spark .fail { fill: #cf222e; }
+svg.timeline { border: 1px solid #ddd; background: #fafafa; margin-bottom: 1em; }
+svg.timeline .done { fill: #7d56f4; }
+svg.timeline .active { fill: #cf222e; }
+footer { margin-top: 3em; color: #888; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>Chaos Engineering Incident Report</h1>
+<dl id="summary">
+<dt>Generated</dt><dd>{{.Generated}}</dd>
+<dt>Session</dt><dd>{{.Session}}</dd>
+<dt>Target</dt><dd>{{.Target}}</dd>
+<dt>Refreshes</dt><dd>{{.Refreshes}}</dd>
+<dt>Peak chaos intensity</dt><dd>{{.Peak}}</dd>
+<dt>System availability</dt><dd>{{.System}}</dd>
+</dl>
+
+<h2 id="services">Service availability</h2>
+{{if .Services}}{{template "rows" .Services}}{{else}}<p>No services were checked.</p>
+{{end}}
+
+<h2 id="endpoints">Endpoint availability</h2>
+{{if .Endpoints}}{{template "rows" .Endpoints}}{{else}}<p>No endpoints were checked.</p>
+{{end}}
+
+<h2 id="timeline">Chaos test timeline</h2>
+{{if .Tests}}{{.Timeline}}
+<table>
+<tr><th>Start</th><th>End</th><th>Duration</th><th>Type</th><th>Target</th><th>Source</th><th>Details</th></tr>
+{{range .Tests}}<tr><td>{{.Start}}</td><td>{{.End}}</td><td class="num">{{.Duration}}</td><td>{{.Type}}</td><td>{{.Target}}</td><td>{{.Source}}</td><td>{{.Details}}</td></tr>
+{{end}}</table>
+{{else}}<p>No chaos tests were detected.</p>
+{{end}}
+<footer>Latency charts and percentiles cover the recent check history; failed checks are marked in red.</footer>
+</body>
+</html>
+{{define "rows"}}<table>
+<tr><th>Name</th><th>Checks</th><th>Availability</th><th>Downtime</th><th>p50 (s)</th><th>p90 (s)</th><th>p99 (s)</th><th>Latency</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td class="num">{{.Checks}}</td><td class="num {{.Class}}">{{.Availability}}</td><td class="num">{{.Downtime}}</td><td class="num">{{.P50}}</td><td class="num">{{.P90}}</td><td class="num">{{.P99}}</td><td>{{.Sparkline}}</td></tr>
+{{end}}</table>
+{{end}}
+`))
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// update rewrites the golden files under testdata with the current output
+var update = flag.Bool("update", false, "update golden files")
+
+// checkGolden compares got with testdata/name, or rewrites it with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file; got:\n%s", name, got)
+	}
+}
+
+// newReportModel returns a model with a fixed session to report on: one
+// service and endpoint each, a finished test and one still running
+func newReportModel(t *testing.T) (model, time.Time) {
+	t.Helper()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Minute)
+
+	m := newTestModel(t)
+	m.state.Stats.StartTime = start
+	m.state.UpdateCount = 300
+	m.peakIntensity = 72
+	m.peakIntensityAt = start.Add(4 * time.Minute)
+	availability := 93.75
+	m.state.SystemAvailability = &availability
+
+	m.state.Stats.ServiceStats["S3"] = &models.ServiceStats{TotalChecks: 300, OKCount: 255, AvailabilityPct: 85, Downtime: time.Minute}
+	m.state.Stats.NginxStats["Main Site"] = &models.EndpointStats{TotalChecks: 300, Failures: 6, SuccessRate: 98, Downtime: 12 * time.Second}
+	for _, seconds := range []float64{0.4, 0.1, 0.3, 0.2, 1.5} {
+		m.addSample("endpoint|Main Site", models.CheckSample{ResponseTime: seconds, OK: true})
+	}
+
+	m.state.CompletedTests = []models.ActiveChaosTest{{
+		Type:      "service-outage",
+		Target:    "s3",
+		Status:    "completed",
+		StartTime: start.Add(2 * time.Minute),
+		EndTime:   start.Add(5 * time.Minute),
+		Source:    "status_file",
+		Details:   "Outage | phase 2",
+	}}
+	m.state.ActiveTests = []models.ActiveChaosTest{{
+		Type:      "latency-injection",
+		Target:    "us-east-1",
+		Status:    "active",
+		StartTime: start.Add(7 * time.Minute),
+		Source:    "chaos_api",
+		Impact:    "2 endpoints",
+	}}
+	return m, now
+}
+
+func TestWriteReport(t *testing.T) {
+	m, now := newReportModel(t)
+	var buf bytes.Buffer
+	if err := m.writeReport(&buf, now); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "report.md", buf.Bytes())
+}
+
+func TestWriteReportEmpty(t *testing.T) {
+	m := newTestModel(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m.state.Stats.StartTime = now
+	var buf bytes.Buffer
+	if err := m.writeReport(&buf, now); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "report-empty.md", buf.Bytes())
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	m, now := newReportModel(t)
+	m.state.ActiveTests[0].Details = `<script>alert("x")</script>`
+	m.addSample("endpoint|Main Site", models.CheckSample{ResponseTime: 5, OK: false})
+	var buf bytes.Buffer
+	if err := m.writeHTMLReport(&buf, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// Parse it as HTML and collect what's in it. Raw tokens are matched up
+	// here, as the decoder quietly closes unbalanced tags outside strict mode.
+	decoder := xml.NewDecoder(&buf)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	var open []string
+	var sections []string
+	elements := make(map[string]int)
+	classes := make(map[string]int)
+	var text strings.Builder
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("report doesn't parse: %v", err)
+		}
+		switch token := token.(type) {
+		case xml.EndElement:
+			if len(open) == 0 || open[len(open)-1] != token.Name.Local {
+				t.Fatalf("</%s> closes %q", token.Name.Local, open)
+			}
+			open = open[:len(open)-1]
+		case xml.StartElement:
+			if !slices.Contains(xml.HTMLAutoClose, token.Name.Local) {
+				open = append(open, token.Name.Local)
+			}
+			elements[token.Name.Local]++
+			for _, attr := range token.Attr {
+				switch attr.Name.Local {
+				case "id":
+					sections = append(sections, attr.Value)
+				case "class":
+					for _, class := range strings.Fields(attr.Value) {
+						classes[class]++
+					}
+				}
+			}
+		case xml.CharData:
+			text.Write(token)
+		}
+	}
+
+	if len(open) != 0 {
+		t.Errorf("unclosed elements %q", open)
+	}
+	if want := []string{"summary", "services", "endpoints", "timeline"}; !slices.Equal(sections, want) {
+		t.Errorf("sections = %q, want %q", sections, want)
+	}
+	if elements["script"] != 0 || !strings.Contains(text.String(), `<script>alert("x")</script>`) {
+		t.Error("test details weren't escaped")
+	}
+	// Two availability tables of one row and a timeline of two, each with a header
+	if elements["table"] != 3 || elements["tr"] != 2+2+3 {
+		t.Errorf("%d tables with %d rows", elements["table"], elements["tr"])
+	}
+	// A sparkline with the failed check marked, and a bar per test
+	if elements["polyline"] != 1 || classes["fail"] != 1 || classes["done"] != 1 || classes["active"] != 1 {
+		t.Errorf("charts: elements %v, classes %v", elements, classes)
+	}
+	// S3 at 85% is only degraded by default, Main Site at 98% is good
+	if classes["warn"] != 1 || classes["good"] != 1 {
+		t.Errorf("availability classes = %v", classes)
+	}
+	for _, want := range []string{"93.75%", "72/100 at 12:04:00", "Outage | phase 2", "us-east-1 → 2 endpoints"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("report lacks %q", want)
+		}
+	}
+}
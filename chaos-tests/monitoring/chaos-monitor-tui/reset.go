@@ -0,0 +1,46 @@
+package main
+
+import (
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// requestReset handles 'R'. The first press asks for confirmation in the
+// title bar; pressing it again while that's shown resets the statistics.
+func (m *model) requestReset() {
+	if time.Now().Before(m.resetConfirmUntil) {
+		m.resetConfirmUntil = time.Time{}
+		m.resetStatistics()
+		m.setNotice("✓ Statistics reset")
+		return
+	}
+	m.resetConfirmUntil = time.Now().Add(noticeDuration)
+	m.setNotice("Reset all statistics? Press 'R' again to confirm")
+}
+
+// resetStatistics starts the statistics and check history afresh, as if
+// the session began now. The endpoint and service lists are kept, so the
+// tables stay in place until the next refresh fills them in.
+func (m *model) resetStatistics() {
+	m.state.Stats = newMonitorState().Stats
+	m.history = make(map[string][]models.CheckSample)
+	m.peakIntensity, m.peakIntensityAt = 0, time.Time{}
+
+	m.appendEvents([]models.Event{{
+		Time:     time.Now(),
+		Severity: "info",
+		Kind:     "reset",
+		Message:  "Statistics reset",
+	}})
+	m.logger.Info("statistics reset")
+
+	// Readers outside the dashboard see the reset without waiting a tick
+	m.store.publish(&m.state)
+	if m.stream != nil {
+		m.stream.publish(&m.state)
+	}
+	if m.recorder != nil {
+		m.recorder.write(&m.state)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestResetZeroesStatistics(t *testing.T) {
+	m := newTestModel(t)
+	start := time.Now().Add(-time.Hour)
+	m.state.Stats.StartTime = start
+	for i := 0; i < 5; i++ {
+		m.state.LastUpdate = start.Add(time.Duration(i) * updateInterval)
+		m.state.NginxEndpoints = []models.EndpointStatus{{Name: "Main Site", Status: "failed", LastChecked: m.state.LastUpdate}}
+		m.state.AWSServices = []models.ServiceStatus{{Name: "S3", Region: "us-east-1", Status: "outage", FailureType: "service_outage", LastChecked: m.state.LastUpdate}}
+		m.updateStatistics()
+		m.recordHistory()
+	}
+	m.peakIntensity, m.peakIntensityAt = 80, start
+
+	press := func() {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+		m = updated.(model)
+	}
+
+	// The first press only asks
+	press()
+	if m.state.Stats.NginxStats["Main Site"].TotalChecks != 5 || len(m.history) == 0 {
+		t.Fatal("reset without confirmation")
+	}
+
+	press()
+	if len(m.state.Stats.NginxStats) != 0 || len(m.state.Stats.ServiceStats) != 0 || len(m.state.Stats.RegionStats) != 0 {
+		t.Errorf("stats after reset = %+v", m.state.Stats)
+	}
+	if len(m.history) != 0 || m.peakIntensity != 0 || !m.peakIntensityAt.IsZero() {
+		t.Errorf("history %v, peak %v at %v after reset", m.history, m.peakIntensity, m.peakIntensityAt)
+	}
+	if !m.state.Stats.StartTime.After(start) {
+		t.Errorf("session clock not restarted: %v", m.state.Stats.StartTime)
+	}
+	if len(m.state.NginxEndpoints) != 1 || len(m.state.AWSServices) != 1 {
+		t.Error("reset dropped the endpoint and service lists")
+	}
+	if last := m.events[len(m.events)-1]; last.Kind != "reset" {
+		t.Errorf("last event = %+v", last)
+	}
+	if published := m.store.snapshot(); len(published.Stats.NginxStats) != 0 {
+		t.Error("reset wasn't published")
+	}
+
+	// Counting starts again from the next tick
+	m.state.LastUpdate = time.Now()
+	m.updateStatistics()
+	if stats := m.state.Stats.NginxStats["Main Site"]; stats == nil || stats.TotalChecks != 1 || stats.Failures != 1 {
+		t.Errorf("stats after the next tick = %+v", stats)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// writeScenarioScript writes a shell script that recreates the faults and
+// effects with curl. Both replace whatever is configured when it runs, so
+// the scenario is reproduced exactly; LOCALSTACK_URL overrides the
+// instance they were read from.
+func writeScenarioScript(w io.Writer, faults []models.ChaosAPIFault, effects []models.ChaosAPIEffect, baseURL string, now time.Time) error {
+	var b bytes.Buffer
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Chaos scenario exported by chaos-monitor-tui at %s\n", now.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(&b, "# %d fault(s), %d network effect(s)\n", len(faults), len(effects))
+	b.WriteString("set -e\n\n")
+	fmt.Fprintf(&b, "LOCALSTACK_URL=\"${LOCALSTACK_URL:-%s}\"\n\n", baseURL)
+
+	// One fault per line, so the script reads and diffs well
+	var entries []string
+	for _, fault := range faults {
+		entry, err := json.Marshal(fault)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, "  "+string(entry))
+	}
+	body := "[]"
+	if len(entries) > 0 {
+		body = "[\n" + strings.Join(entries, ",\n") + "\n]"
+	}
+	b.WriteString("# Faults\n")
+	writeCurlPost(&b, "/_localstack/chaos/faults", body)
+
+	b.WriteString("\n# Network effects\n")
+	switch len(effects) {
+	case 0:
+		// A zero latency clears any effect set when the script runs, as
+		// the faults are replaced
+		b.WriteString("# None were active, so clear any\n")
+		writeCurlPost(&b, "/_localstack/chaos/effects", `{"latency":0}`)
+	case 1:
+		// LocalStack takes a single global effect as an object
+		effect, err := json.Marshal(effects[0])
+		if err != nil {
+			return err
+		}
+		writeCurlPost(&b, "/_localstack/chaos/effects", string(effect))
+	default:
+		effect, err := json.Marshal(effects)
+		if err != nil {
+			return err
+		}
+		writeCurlPost(&b, "/_localstack/chaos/effects", string(effect))
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeCurlPost writes a curl command posting a JSON body to a LocalStack path
+func writeCurlPost(b *bytes.Buffer, path, body string) {
+	fmt.Fprintf(b, "curl -fsS -X POST \"$LOCALSTACK_URL%s\" \\\n", path)
+	b.WriteString("  -H 'Content-Type: application/json' \\\n")
+	fmt.Fprintf(b, "  -d %s\n", shellQuote(body))
+}
+
+// shellQuote single-quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// exportScenario writes the current faults and effects as a script next
+// to the CSV exports and reports the result in the event log
+func (m *model) exportScenario() {
+	dir := m.cfg.csvOut
+	if dir == "" {
+		dir = "."
+	}
+	now := time.Now()
+	path := filepath.Join(dir, "chaos-scenario-"+now.Format("20060102-150405")+".sh")
+	event := models.Event{Time: now, Severity: "info", Kind: "export"}
+
+	var script bytes.Buffer
+	err := writeScenarioScript(&script, m.state.ChaosAPIFaults, m.state.ChaosAPIEffects, m.currentTarget().baseURL, now)
+	if err == nil {
+		err = os.WriteFile(path, script.Bytes(), 0o755)
+	}
+	if err != nil {
+		event.Severity = "error"
+		event.Message = fmt.Sprintf("Scenario export failed: %v", err)
+	} else {
+		event.Message = fmt.Sprintf("Scenario with %d fault(s) and %d effect(s) exported to %s",
+			len(m.state.ChaosAPIFaults), len(m.state.ChaosAPIEffects), path)
+	}
+	m.appendEvents([]models.Event{event})
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestWriteScenarioScript(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fault := models.ChaosAPIFault{Service: "s3", Region: "us-east-1", Probability: 0.5}
+	fault.Error.Code = "ServiceUnavailable"
+	fault.Error.StatusCode = 503
+
+	tests := []struct {
+		golden  string
+		faults  []models.ChaosAPIFault
+		effects []models.ChaosAPIEffect
+	}{
+		{"scenario-empty.sh", nil, nil},
+		{"scenario-one-effect.sh", []models.ChaosAPIFault{fault}, []models.ChaosAPIEffect{{Latency: 500, Jitter: 50}}},
+		{"scenario-effects.sh", nil, []models.ChaosAPIEffect{{Latency: 200, Service: "s3"}, {Latency: 0, Probability: 0.1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var script bytes.Buffer
+			if err := writeScenarioScript(&script, tt.faults, tt.effects, "http://localhost:4566", now); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tt.golden, script.Bytes())
+		})
+	}
+}
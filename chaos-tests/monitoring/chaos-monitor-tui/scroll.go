@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+
+	"chaos-monitor-tui/ui"
+
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// newBodyViewport returns the viewport the dashboard body scrolls in. Keys
+// are routed to it by Update, since they're shared with the selection and
+// the event log; only the mouse wheel is handled by the viewport itself.
+func newBodyViewport() viewport.Model {
+	vp := viewport.New(0, 0)
+	vp.KeyMap = viewport.KeyMap{}
+	vp.MouseWheelEnabled = true
+	return vp
+}
+
+// resize lays the dashboard out for a new terminal size, keeping the
+// selection valid and on screen and the scroll offset in range
+func (m *model) resize(width, height int) {
+	m.width, m.height = width, height
+	m.logView.Width = max(0, width-6)
+	m.resizeLog()
+	if m.selected >= len(m.selectableRows()) {
+		m.selected = -1
+	}
+	if m.selectedKey() != "" {
+		m.scrollToSelection()
+	} else {
+		m.syncBody()
+	}
+}
+
+// setBody fills the body viewport with the space left below header
+func (m *model) setBody(header, body string) {
+	m.body.Width = m.width
+	m.body.Height = max(1, m.height-strings.Count(header, "\n")-1)
+	m.body.SetContent(body)
+	// Keep the offset in range when the content or screen shrinks
+	m.body.SetYOffset(m.body.YOffset)
+}
+
+// syncBody renders the dashboard into the body viewport so scrolling works
+// against the current content, and returns the body
+func (m *model) syncBody() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+	opts := m.dashboardOptions()
+	body := ui.RenderDashboardBody(&m.state, opts, m.width)
+	m.setBody(ui.RenderDashboardHeader(&m.state, opts, m.width), body)
+	return body
+}
+
+// scrollBody moves the dashboard body by delta lines
+func (m *model) scrollBody(delta int) {
+	m.syncBody()
+	if delta < 0 {
+		m.body.LineUp(-delta)
+	} else {
+		m.body.LineDown(delta)
+	}
+}
+
+// pageBody handles the pgup, pgdown, home and end keys for the body
+func (m *model) pageBody(key string) {
+	m.syncBody()
+	switch key {
+	case "pgup":
+		m.body.ViewUp()
+	case "pgdown":
+		m.body.ViewDown()
+	case "home":
+		m.body.GotoTop()
+	case "end":
+		m.body.GotoBottom()
+	}
+}
+
+// scrollToSelection scrolls the body just enough to show the selected row,
+// found by its marker
+func (m *model) scrollToSelection() {
+	lines := strings.Split(ansiPattern.ReplaceAllString(m.syncBody(), ""), "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, "▶ ") {
+			continue
+		}
+		if i < m.body.YOffset {
+			m.body.SetYOffset(i)
+		} else if i >= m.body.YOffset+m.body.Height {
+			m.body.SetYOffset(i - m.body.Height + 1)
+		}
+		return
+	}
+}
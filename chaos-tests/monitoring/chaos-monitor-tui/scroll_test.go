@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestResizeKeepsSelectionVisible(t *testing.T) {
+	m := newTestModel(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	m.state.LastUpdate = now
+	for i := 0; i < 20; i++ {
+		m.state.NginxEndpoints = append(m.state.NginxEndpoints, models.EndpointStatus{Name: fmt.Sprintf("Endpoint %02d", i), Status: "ok", LastChecked: now})
+	}
+	for _, name := range []string{"S3", "SQS", "DYNAMODB", "LAMBDA"} {
+		m.state.AWSServices = append(m.state.AWSServices, models.ServiceStatus{Name: name, Status: "healthy", FailureType: "ok", LastChecked: now})
+	}
+	m.updateStatistics()
+	rows := m.selectableRows()
+	m.selected = len(rows) - 1
+
+	var current tea.Model = m
+	for _, size := range []struct{ width, height int }{
+		{120, 40}, {80, 30}, {200, 60}, {60, 25}, {100, 24}, {120, 40},
+	} {
+		current, _ = current.Update(tea.WindowSizeMsg{Width: size.width, Height: size.height})
+		m = current.(model)
+		if m.width != size.width || m.height != size.height {
+			t.Fatalf("size = %dx%d after resizing to %dx%d", m.width, m.height, size.width, size.height)
+		}
+		if m.selectedKey() != rows[len(rows)-1].key {
+			t.Errorf("%dx%d: selected %q", size.width, size.height, m.selectedKey())
+		}
+		if view := ansiPattern.ReplaceAllString(m.body.View(), ""); !strings.Contains(view, "▶ ") {
+			t.Errorf("%dx%d: selection scrolled out of view at offset %d", size.width, size.height, m.body.YOffset)
+		}
+		if m.body.YOffset < 0 || m.body.YOffset > max(0, m.body.TotalLineCount()-m.body.Height) {
+			t.Errorf("%dx%d: offset %d out of range for %d lines in %d", size.width, size.height, m.body.YOffset, m.body.TotalLineCount(), m.body.Height)
+		}
+		if m.logView.Width != max(0, size.width-6) {
+			t.Errorf("%dx%d: log width %d", size.width, size.height, m.logView.Width)
+		}
+	}
+
+	// A selection past the rows left is dropped rather than kept dangling
+	m.state.AWSServices = nil
+	current, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	if m = current.(model); m.selected != -1 {
+		t.Errorf("selected %d of %d rows", m.selected, len(m.selectableRows()))
+	}
+}
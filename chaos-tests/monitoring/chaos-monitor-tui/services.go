@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+const defaultServices = "s3,dynamodb,lambda"
+
+// awsServiceDef describes how to probe an AWS service with the AWS CLI
+type awsServiceDef struct {
+	name string   // Identifier used with -services, e.g. "s3"
+	args []string // AWS CLI arguments for a cheap read-only call
+}
+
+// builtinServices are the services that can be selected by name alone
+var builtinServices = map[string]awsServiceDef{
+	"s3":       {name: "s3", args: []string{"s3", "ls"}},
+	"dynamodb": {name: "dynamodb", args: []string{"dynamodb", "list-tables"}},
+	"lambda":   {name: "lambda", args: []string{"lambda", "list-functions"}},
+	"sqs":      {name: "sqs", args: []string{"sqs", "list-queues"}},
+	"sns":      {name: "sns", args: []string{"sns", "list-topics"}},
+}
+
+// parseServices parses a comma-separated service list. Each entry is either
+// a built-in service name or a custom "name=aws cli args" definition, e.g.
+// "kinesis=kinesis list-streams".
+func parseServices(spec string) ([]awsServiceDef, error) {
+	var services []awsServiceDef
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if name, args, ok := strings.Cut(entry, "="); ok {
+			fields := strings.Fields(args)
+			if strings.TrimSpace(name) == "" || len(fields) == 0 {
+				return nil, fmt.Errorf("invalid service definition %q, expected name=aws cli args", entry)
+			}
+			services = append(services, awsServiceDef{name: strings.TrimSpace(name), args: fields})
+			continue
+		}
+
+		def, ok := builtinServices[strings.ToLower(entry)]
+		if !ok {
+			return nil, fmt.Errorf("unknown service %q (built-in: %s)", entry, strings.Join(builtinServiceNames(), ", "))
+		}
+		services = append(services, def)
+	}
+
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services configured")
+	}
+	return services, nil
+}
+
+func builtinServiceNames() []string {
+	names := make([]string, 0, len(builtinServices))
+	for name := range builtinServices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *model) updateAWSServices() {
+	m.state.AWSServices = nil
+
+	for _, region := range m.cfg.regions {
+		for _, service := range m.cfg.services {
+			status := m.checkAWSService(service, region)
+			status.Name = strings.ToUpper(service.name)
+			// Only tag the region when there's a breakdown to show
+			if len(m.cfg.regions) > 1 {
+				status.Region = region
+			}
+			m.state.AWSServices = append(m.state.AWSServices, status)
+		}
+	}
+}
+
+func (m *model) checkAWSService(service awsServiceDef, region string) models.ServiceStatus {
+	start := time.Now()
+	status := models.ServiceStatus{
+		LastChecked: start,
+	}
+
+	args := dockerAWSArgs(m.cfg.aws, region, m.currentTarget().baseURL)
+	cmd := exec.CommandContext(m.ctx, "docker", append(args, service.args...)...)
+	// Interrupt rather than kill so docker run stops (and removes) the container
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = 2 * time.Second
+
+	var out bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	status.ResponseTime = time.Since(start).Seconds()
+
+	if errors.Is(err, exec.ErrNotFound) {
+		// Without docker nothing was probed, so this says nothing about the service
+		status.Status = "unavailable"
+		status.FailureType = "docker_unavailable"
+	} else if err != nil {
+		output := out.String() + stderr.String()
+		status.Status, status.FailureType = classifyAWSError(output, m.cfg.classifyRules)
+		status.Error = awsErrorMessage(output, err)
+	} else {
+		status.Status = "healthy"
+		status.FailureType = "ok"
+	}
+
+	return status
+}
+
+// awsCredentials selects the credentials service probes use. By default
+// they use LocalStack's dummy test/test keys.
+type awsCredentials struct {
+	profile    string // Named profile from the host's ~/.aws, mounted into the container
+	inheritEnv bool   // Pass the monitor's own AWS_* credentials through
+}
+
+// inheritedAWSEnv are the variables passed through with inheritEnv
+var inheritedAWSEnv = []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"}
+
+// dockerAWSArgs returns the docker arguments that run the AWS CLI against
+// endpointURL in region; the CLI command itself follows them
+func dockerAWSArgs(creds awsCredentials, region, endpointURL string) []string {
+	args := []string{"run", "--rm", "--network", "host", "-e", "AWS_DEFAULT_REGION=" + region}
+	switch {
+	case creds.profile != "":
+		home, _ := os.UserHomeDir()
+		args = append(args,
+			"-v", filepath.Join(home, ".aws")+":/root/.aws:ro",
+			"-e", "AWS_PROFILE="+creds.profile,
+		)
+	case creds.inheritEnv:
+		// "-e NAME" makes docker copy the variable from its own environment
+		for _, name := range inheritedAWSEnv {
+			args = append(args, "-e", name)
+		}
+	default:
+		args = append(args, "-e", "AWS_ACCESS_KEY_ID=test", "-e", "AWS_SECRET_ACCESS_KEY=test")
+	}
+	return append(args, awsCLIImage, "--endpoint-url", endpointURL)
+}
+
+// classifyRule maps error output containing keyword to a failure type
+type classifyRule struct {
+	keyword     string
+	failureType string
+}
+
+// failureStatuses maps each failure type to the status shown for it
+var failureStatuses = map[string]string{
+	"service_outage":     "outage",
+	"throttled":          "throttled",
+	"resource_exhausted": "exhausted",
+	"error":              "outage",
+}
+
+// defaultClassifyRules are checked after any user rules, in order
+var defaultClassifyRules = []classifyRule{
+	{"ServiceUnavailable", "service_outage"},
+	{"InternalError", "service_outage"},
+	{"SlowDown", "throttled"},
+	{"TooManyRequests", "throttled"},
+	{"ThrottlingException", "throttled"},
+	{"QuotaExceeded", "resource_exhausted"},
+	{"ResourceInUseException", "resource_exhausted"},
+	{"LimitExceeded", "resource_exhausted"},
+}
+
+// classifyFlag is a repeatable flag of keyword=failure_type rules, kept in
+// the order given
+type classifyFlag []classifyRule
+
+func (f *classifyFlag) String() string {
+	var rules []string
+	for _, rule := range *f {
+		rules = append(rules, rule.keyword+"="+rule.failureType)
+	}
+	return strings.Join(rules, ",")
+}
+
+func (f *classifyFlag) Set(value string) error {
+	keyword, failureType, ok := strings.Cut(value, "=")
+	if !ok || keyword == "" {
+		return fmt.Errorf("expected keyword=failure_type, got %q", value)
+	}
+	failureType = strings.TrimSpace(failureType)
+	if _, known := failureStatuses[failureType]; !known {
+		return fmt.Errorf("unknown failure type %q (available: %s)", failureType, strings.Join(sortedKeys(failureStatuses), ", "))
+	}
+	*f = append(*f, classifyRule{keyword: keyword, failureType: failureType})
+	return nil
+}
+
+// maxErrorLength caps the error message kept from a failed probe
+const maxErrorLength = 120
+
+// awsCLIError matches the AWS CLI's message for an error returned by the API
+var awsCLIError = regexp.MustCompile(`An error occurred \(([^)]+)\) when calling the \w+ operation(?: \([^)]*\))?: (.*)`)
+
+// awsErrorMessage extracts a one-line message from failed AWS CLI output,
+// e.g. "ServiceUnavailable: Service is unavailable". Output without an API
+// error falls back to its last line, or to the command's error.
+func awsErrorMessage(output string, err error) string {
+	message := err.Error()
+	if match := awsCLIError.FindStringSubmatch(output); match != nil {
+		message = match[1] + ": " + strings.TrimSpace(match[2])
+	} else if output = strings.TrimSpace(output); output != "" {
+		message = strings.TrimSpace(output[strings.LastIndex(output, "\n")+1:])
+	}
+	if runes := []rune(message); len(runes) > maxErrorLength {
+		message = string(runes[:maxErrorLength-1]) + "…"
+	}
+	return message
+}
+
+// classifyAWSError maps AWS CLI error output to a status and failure type.
+// Rules are checked in order and the first keyword found wins; output
+// matching no rule is a generic error.
+func classifyAWSError(output string, rules []classifyRule) (status, failureType string) {
+	for _, rule := range rules {
+		if strings.Contains(output, rule.keyword) {
+			return failureStatuses[rule.failureType], rule.failureType
+		}
+	}
+	return "outage", "error"
+}
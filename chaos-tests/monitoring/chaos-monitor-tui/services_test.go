@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// fakeDocker puts a docker executable running script first on PATH for
+// the rest of the test, so service probes run it instead
+func fakeDocker(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestServiceProbeStopsOnCancel(t *testing.T) {
+	fakeDocker(t, "exec sleep 30")
+	m := newTestModel(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.ctx = ctx
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	m.checkAWSService(builtinServices["s3"], "us-east-1")
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("probe took %v after its context was cancelled", elapsed)
+	}
+}
+
+func TestClassifyRuleOrder(t *testing.T) {
+	var classify classifyFlag
+	for _, rule := range []string{"ServiceUnavailable=throttled", "Maintenance=service_outage", "SlowDown=resource_exhausted"} {
+		if err := classify.Set(rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := classify.Set("Oops=unknown_type"); err == nil {
+		t.Error("unknown failure type accepted")
+	}
+	rules := append(append([]classifyRule{}, classify...), defaultClassifyRules...)
+
+	tests := []struct {
+		output      string
+		wantStatus  string
+		wantFailure string
+	}{
+		// User rules override the defaults for the same keyword
+		{"An error occurred (ServiceUnavailable)", "throttled", "throttled"},
+		{"An error occurred (SlowDown)", "exhausted", "resource_exhausted"},
+		{"Site under Maintenance", "outage", "service_outage"},
+		// The first rule listed wins when several keywords appear
+		{"Maintenance then ServiceUnavailable", "throttled", "throttled"},
+		// Keywords no user rule mentions fall through to the defaults
+		{"An error occurred (ThrottlingException)", "throttled", "throttled"},
+		{"An error occurred (QuotaExceeded)", "exhausted", "resource_exhausted"},
+		{"An error occurred (InternalError)", "outage", "service_outage"},
+		{"Could not connect to the endpoint URL", "outage", "error"},
+	}
+	for _, tt := range tests {
+		status, failureType := classifyAWSError(tt.output, rules)
+		if status != tt.wantStatus || failureType != tt.wantFailure {
+			t.Errorf("%q: got %s/%s, want %s/%s", tt.output, status, failureType, tt.wantStatus, tt.wantFailure)
+		}
+	}
+}
+
+func TestServiceProbeWithoutDocker(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	m := newTestModel(t)
+	status := m.checkAWSService(builtinServices["s3"], "us-east-1")
+	if status.Status != "unavailable" || status.FailureType != "docker_unavailable" || status.Error != "" {
+		t.Fatalf("got %s/%s, error %q", status.Status, status.FailureType, status.Error)
+	}
+
+	// It's left out of the stats rather than counted as an outage
+	m.state.LastUpdate = time.Now()
+	m.state.AWSServices = []models.ServiceStatus{status}
+	m.updateStatistics()
+	if _, ok := m.state.Stats.ServiceStats[status.Name]; ok {
+		t.Error("an unchecked service has stats")
+	}
+}
+
+// recordDockerEnv fakes docker with a script that appends the environment
+// each run would give the container, as NAME=value lines ending in "---",
+// and returns the file it writes to
+func recordDockerEnv(t *testing.T) string {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "env")
+	fakeDocker(t, `while [ $# -gt 0 ]; do
+  case "$1" in
+  -e) case "$2" in
+      *=*) echo "$2" ;;
+      *) eval "echo \"$2=\${$2}\"" ;; # Copied from docker's own environment
+      esac
+      shift ;;
+  -v) echo "volume $2"; shift ;;
+  esac
+  shift
+done >> '`+log+`'
+echo --- >> '`+log+`'`)
+	return log
+}
+
+// containerEnvs reads the environments recordDockerEnv logged, one per run
+func containerEnvs(t *testing.T, log string) [][]string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envs [][]string
+	var env []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "---" {
+			envs = append(envs, env)
+			env = nil
+			continue
+		}
+		env = append(env, line)
+	}
+	return envs
+}
+
+func TestServiceProbeEnvironment(t *testing.T) {
+	log := recordDockerEnv(t)
+	m := newTestModel(t)
+	m.cfg.services = []awsServiceDef{builtinServices["s3"]}
+	m.cfg.regions = []string{"eu-west-1", "us-west-2"}
+
+	m.updateAWSServices()
+	envs := containerEnvs(t, log)
+	if len(envs) != 2 {
+		t.Fatalf("ran docker %d times, want once per region: %q", len(envs), envs)
+	}
+	for i, region := range m.cfg.regions {
+		want := []string{"AWS_DEFAULT_REGION=" + region, "AWS_ACCESS_KEY_ID=test", "AWS_SECRET_ACCESS_KEY=test"}
+		if !slices.Equal(envs[i], want) {
+			t.Errorf("%s environment = %q, want %q", region, envs[i], want)
+		}
+		if got := m.state.AWSServices[i]; got.Region != region || got.Status != "healthy" {
+			t.Errorf("%s status = %+v", region, got)
+		}
+	}
+
+	// The monitor's own credentials are passed through with -aws-env
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "token")
+	m.cfg.regions = []string{"ap-south-1"}
+	m.cfg.aws = awsCredentials{inheritEnv: true}
+	os.Remove(log)
+	m.updateAWSServices()
+	want := []string{"AWS_DEFAULT_REGION=ap-south-1", "AWS_ACCESS_KEY_ID=AKIAEXAMPLE", "AWS_SECRET_ACCESS_KEY=secret", "AWS_SESSION_TOKEN=token"}
+	if envs := containerEnvs(t, log); len(envs) != 1 || !slices.Equal(envs[0], want) {
+		t.Errorf("-aws-env environment = %q, want %q", envs, want)
+	}
+
+	// A profile mounts the host's ~/.aws instead
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	m.cfg.aws = awsCredentials{profile: "staging"}
+	os.Remove(log)
+	m.updateAWSServices()
+	want = []string{"AWS_DEFAULT_REGION=ap-south-1", "volume " + filepath.Join(home, ".aws") + ":/root/.aws:ro", "AWS_PROFILE=staging"}
+	if envs := containerEnvs(t, log); len(envs) != 1 || !slices.Equal(envs[0], want) {
+		t.Errorf("-aws-profile environment = %q, want %q", envs, want)
+	}
+}
+
+func TestServiceProbeErrorMessage(t *testing.T) {
+	tests := []struct {
+		output      string
+		failureType string
+		message     string
+	}{
+		{
+			"\nAn error occurred (ServiceUnavailable) when calling the ListBuckets operation: Service is unavailable\n",
+			"service_outage", "ServiceUnavailable: Service is unavailable",
+		},
+		{
+			"An error occurred (ThrottlingException) when calling the ListQueues operation (reached max retries: 2): Rate exceeded",
+			"throttled", "ThrottlingException: Rate exceeded",
+		},
+		{
+			"An error occurred (InternalError) when calling the ListTables operation: We encountered an internal error",
+			"service_outage", "InternalError: We encountered an internal error",
+		},
+		{
+			"\nCould not connect to the endpoint URL: \"http://localhost:4566/\"\n",
+			"error", `Could not connect to the endpoint URL: "http://localhost:4566/"`,
+		},
+		{"", "error", "exit status 254"},
+	}
+
+	m := newTestModel(t)
+	m.cfg.classifyRules = defaultClassifyRules
+	for _, tt := range tests {
+		fakeDocker(t, "printf '%s' '"+strings.ReplaceAll(tt.output, "'", `'\''`)+"' >&2; exit 254")
+		status := m.checkAWSService(builtinServices["s3"], "us-east-1")
+		if status.FailureType != tt.failureType || status.Error != tt.message {
+			t.Errorf("%q: got %s, %q; want %s, %q", tt.output, status.FailureType, status.Error, tt.failureType, tt.message)
+		}
+	}
+
+	// Long messages are cut short
+	fakeDocker(t, "printf 'An error occurred (InternalError) when calling the ListTables operation: %0200d' 0 >&2; exit 254")
+	status := m.checkAWSService(builtinServices["s3"], "us-east-1")
+	if n := len([]rune(status.Error)); n != maxErrorLength || !strings.HasSuffix(status.Error, "…") {
+		t.Errorf("long message is %d runes: %q", n, status.Error)
+	}
+
+	// The stats keep the last error through later passing checks
+	m.state.LastUpdate = time.Now()
+	status.Name = "S3"
+	m.state.AWSServices = []models.ServiceStatus{status}
+	m.updateStatistics()
+	m.state.LastUpdate = m.state.LastUpdate.Add(2 * time.Second)
+	m.state.AWSServices = []models.ServiceStatus{{Name: "S3", Status: "healthy", FailureType: "ok"}}
+	m.updateStatistics()
+	if got := m.state.Stats.ServiceStats["S3"].LastError; got != status.Error {
+		t.Errorf("last error = %q, want %q", got, status.Error)
+	}
+}
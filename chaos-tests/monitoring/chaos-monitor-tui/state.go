@@ -0,0 +1,40 @@
+package main
+
+import (
+	"sync"
+
+	"chaos-monitor-tui/models"
+)
+
+// stateStore shares the monitor state with readers outside the Bubble Tea
+// goroutine, such as the REST API handlers.
+//
+// Locking contract: m.state belongs to the Bubble Tea update goroutine and
+// is only read or written there, so the update loop needs no locks. Once a
+// refresh is complete the model calls publish, which stores a deep copy
+// under the write lock. Other goroutines must never touch m.state; they
+// call snapshot, which returns their own deep copy under the read lock.
+// Nothing is shared between the copies, so callers can keep or modify them.
+type stateStore struct {
+	mu    sync.RWMutex
+	state models.MonitorState
+}
+
+func newStateStore() *stateStore {
+	return &stateStore{}
+}
+
+// publish replaces the shared state with a copy of state
+func (s *stateStore) publish(state *models.MonitorState) {
+	clone := state.Clone()
+	s.mu.Lock()
+	s.state = clone
+	s.mu.Unlock()
+}
+
+// snapshot returns a copy of the most recently published state
+func (s *stateStore) snapshot() models.MonitorState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.Clone()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// TestStateStoreSharesNothing updates the state and publishes it on one
+// goroutine while another modifies its snapshots, as the API handlers may.
+// Run with -race to catch any slice or map the copies still share.
+func TestStateStoreSharesNothing(t *testing.T) {
+	m := newTestModel(t)
+	store := newStateStore()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			m.state.LastUpdate = start.Add(time.Duration(i) * updateInterval)
+			m.state.NginxEndpoints = []models.EndpointStatus{{Name: "Main Site", Status: "ok", ExpectedCodes: []int{200}}}
+			m.state.AWSServices = []models.ServiceStatus{{Name: "s3", Region: "us-east-1", Status: "healthy", FailureType: "ok"}}
+			m.updateStatistics()
+			store.publish(&m.state)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			snapshot := store.snapshot()
+			if snapshot.Stats.ServiceStats == nil {
+				continue // Nothing published yet
+			}
+			if stats, ok := snapshot.Stats.NginxStats["Main Site"]; ok {
+				for i := range stats.Histogram {
+					stats.Histogram[i] = -1
+				}
+				stats.Histogram = append(stats.Histogram, -1)
+				mutate(stats.Recent)
+				stats.Recent = append(stats.Recent, "mutated")
+			}
+			if stats, ok := snapshot.Stats.ServiceStats["s3"]; ok {
+				mutate(stats.Recent)
+				stats.Recent = append(stats.Recent, "mutated")
+			}
+			if stats, ok := snapshot.Stats.RegionStats["s3"]["us-east-1"]; ok {
+				mutate(stats.Recent)
+			}
+			snapshot.Stats.ServiceStats["mutated"] = &models.ServiceStats{}
+			if regions, ok := snapshot.Stats.RegionStats["s3"]; ok {
+				regions["mutated"] = &models.ServiceStats{}
+			}
+		}
+	}()
+	wg.Wait()
+
+	for _, count := range m.state.Stats.NginxStats["Main Site"].Histogram {
+		if count < 0 {
+			t.Error("endpoint histogram changed through a snapshot")
+		}
+	}
+	if _, ok := m.state.Stats.ServiceStats["mutated"]; ok {
+		t.Error("service stats changed through a snapshot")
+	}
+	if _, ok := m.state.Stats.RegionStats["s3"]["mutated"]; ok {
+		t.Error("region stats changed through a snapshot")
+	}
+	for name, recent := range map[string][]string{
+		"endpoint": m.state.Stats.NginxStats["Main Site"].Recent,
+		"service":  m.state.Stats.ServiceStats["s3"].Recent,
+		"region":   m.state.Stats.RegionStats["s3"]["us-east-1"].Recent,
+	} {
+		if slices.Contains(recent, "mutated") {
+			t.Errorf("%s history changed through a snapshot", name)
+		}
+	}
+}
+
+func mutate(recent []string) {
+	for i := range recent {
+		recent[i] = "mutated"
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"chaos-monitor-tui/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// streamWriteTimeout drops clients that stop reading
+const streamWriteTimeout = 10 * time.Second
+
+// streamServer pushes the monitor state to WebSocket clients after every
+// refresh. New clients receive the latest snapshot as soon as they connect.
+type streamServer struct {
+	server   *http.Server
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	latest  []byte // MonitorState as JSON; nil before the first refresh
+	clients map[*streamClient]bool
+}
+
+// streamClient is one connection. send holds at most one pending snapshot;
+// a slow client skips intermediate states rather than queueing them.
+type streamClient struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// newStreamServer listens on addr and starts serving in the background
+func newStreamServer(addr string) (*streamServer, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &streamServer{
+		clients: make(map[*streamClient]bool),
+		upgrader: websocket.Upgrader{
+			// Browser dashboards are usually served from another origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleConnect)
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	// Serve only fails once the listener is closed, which shutdown does
+	go s.server.Serve(ln)
+	return s, nil
+}
+
+// publish broadcasts a snapshot of state to every connected client
+func (s *streamServer) publish(state *models.MonitorState) {
+	snapshot, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest = snapshot
+	for client := range s.clients {
+		client.offer(snapshot)
+	}
+}
+
+func (s *streamServer) handleConnect(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied with an error
+		return
+	}
+	client := &streamClient{conn: conn, send: make(chan []byte, 1)}
+
+	s.mu.Lock()
+	s.clients[client] = true
+	if s.latest != nil {
+		client.offer(s.latest)
+	}
+	s.mu.Unlock()
+
+	go client.writeLoop()
+	client.readLoop()
+
+	// The client went away; stop its writer
+	s.mu.Lock()
+	delete(s.clients, client)
+	close(client.send)
+	s.mu.Unlock()
+}
+
+// offer queues a snapshot, replacing one the client hasn't picked up yet.
+// Callers hold the server lock.
+func (c *streamClient) offer(snapshot []byte) {
+	select {
+	case <-c.send:
+	default:
+	}
+	c.send <- snapshot
+}
+
+// readLoop discards incoming messages until the connection closes; reading
+// is needed to process close and ping frames
+func (c *streamClient) readLoop() {
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *streamClient) writeLoop() {
+	defer c.conn.Close()
+	for snapshot := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if err := c.conn.WriteMessage(websocket.TextMessage, snapshot); err != nil {
+			return
+		}
+	}
+	c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+}
+
+// shutdown closes every client and stops the server
+func (s *streamServer) shutdown() error {
+	s.mu.Lock()
+	for client := range s.clients {
+		client.conn.Close()
+	}
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
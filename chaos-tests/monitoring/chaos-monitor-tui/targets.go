@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+	"chaos-monitor-tui/monitor"
+)
+
+// target is a LocalStack instance to monitor
+type target struct {
+	name     string
+	baseURL  string // LocalStack edge URL, for the Chaos API and AWS CLI
+	nginxURL string // Where the nginx pages are served from when there's no ALB
+}
+
+// defaultTarget is the local instance monitored unless -target is given
+var defaultTarget = target{name: "local", baseURL: baseURL, nginxURL: nginxURL}
+
+// targetFlag is a repeatable flag of "name=base URL[,nginx URL]" entries,
+// kept in the order given
+type targetFlag []target
+
+func (f *targetFlag) String() string {
+	var entries []string
+	for _, t := range *f {
+		entries = append(entries, t.name+"="+t.baseURL+","+t.nginxURL)
+	}
+	return strings.Join(entries, " ")
+}
+
+func (f *targetFlag) Set(value string) error {
+	name, urls, ok := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return fmt.Errorf("expected 'name=http://host:4566', got %q", value)
+	}
+	base, nginx, _ := strings.Cut(urls, ",")
+	base = strings.TrimSuffix(strings.TrimSpace(base), "/")
+	if u, err := url.Parse(base); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid LocalStack URL for target %s: %q", name, base)
+	}
+	nginx = strings.TrimSuffix(strings.TrimSpace(nginx), "/")
+	if nginx == "" {
+		nginx = base + strings.TrimPrefix(nginxURL, baseURL)
+	}
+	*f = append(*f, target{name: name, baseURL: base, nginxURL: nginx})
+	return nil
+}
+
+// targetState is the monitoring data kept for a target while another one
+// is shown. Only the shown target is probed.
+type targetState struct {
+	state      models.MonitorState
+	prevState  models.MonitorState
+	history    map[string][]models.CheckSample
+	cascade    *monitor.CascadeDetector
+	faults     []models.FaultCount
+	throttling *monitor.ThrottleDetector
+}
+
+// currentTarget returns the target being monitored
+func (m model) currentTarget() target {
+	return m.cfg.targets[m.target]
+}
+
+// switchTarget shows the next or previous target, keeping the data
+// collected for the one being left so switching back resumes it
+func (m *model) switchTarget(delta int) {
+	if len(m.cfg.targets) < 2 {
+		return
+	}
+	if m.targetStates == nil {
+		m.targetStates = make([]targetState, len(m.cfg.targets))
+	}
+	key := m.selectedKey()
+	m.targetStates[m.target] = targetState{state: m.state, prevState: m.prevState, history: m.history, cascade: m.cascade, faults: m.faultCounts, throttling: m.throttling}
+
+	m.target = (m.target + delta + len(m.cfg.targets)) % len(m.cfg.targets)
+	saved := m.targetStates[m.target]
+	if saved.history == nil {
+		saved = targetState{
+			state:      newMonitorState(),
+			history:    make(map[string][]models.CheckSample),
+			cascade:    monitor.NewCascadeDetector(m.cfg.cascade),
+			throttling: monitor.NewThrottleDetector(m.cfg.throttleChecks),
+		}
+	}
+	m.state, m.prevState, m.history, m.cascade = saved.state, saved.prevState, saved.history, saved.cascade
+	m.faultCounts, m.throttling = saved.faults, saved.throttling
+	m.flashUntil = make(map[string]time.Time)
+	m.selectKey(key)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+	"chaos-monitor-tui/monitor"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const otlpExportInterval = 10 * time.Second
+
+// metricsExporter records the measurements shown on the dashboard through the
+// OpenTelemetry metric SDK and pushes them to an OTLP/HTTP collector
+type metricsExporter struct {
+	provider *sdkmetric.MeterProvider
+
+	endpointUp       metric.Int64Gauge
+	endpointLatency  metric.Float64Histogram
+	serviceUp        metric.Int64Gauge
+	serviceLatency   metric.Float64Histogram
+	serviceAvailable metric.Float64Gauge
+	activeFaults     metric.Int64Gauge
+	activeEffects    metric.Int64Gauge
+	activeTests      metric.Int64Gauge
+}
+
+// newMetricsExporter creates an exporter sending to endpoint, e.g.
+// "http://localhost:4318". The standard /v1/metrics path is used when the
+// URL has no path.
+func newMetricsExporter(ctx context.Context, endpoint string) (*metricsExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q", endpoint)
+	}
+	if strings.Trim(u.Path, "/") == "" {
+		u.Path = "/v1/metrics"
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(u.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	return newReaderMetricsExporter(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(otlpExportInterval)))
+}
+
+// newReaderMetricsExporter creates an exporter whose measurements are
+// collected by reader
+func newReaderMetricsExporter(reader sdkmetric.Reader) (*metricsExporter, error) {
+	// The monitored instance isn't a resource attribute: -target can
+	// switch between several, so each data point names its own
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName("chaos-monitor-tui"),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithResource(res), sdkmetric.WithReader(reader))
+	meter := provider.Meter("chaos-monitor-tui")
+
+	e := &metricsExporter{provider: provider}
+	if e.endpointUp, err = meter.Int64Gauge("chaos_monitor.endpoint.up",
+		metric.WithDescription("1 if the endpoint's last check passed, 0 otherwise")); err != nil {
+		return nil, err
+	}
+	if e.endpointLatency, err = meter.Float64Histogram("chaos_monitor.endpoint.response_time",
+		metric.WithDescription("Endpoint response time"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if e.serviceUp, err = meter.Int64Gauge("chaos_monitor.service.up",
+		metric.WithDescription("1 if the AWS service's last check was healthy, 0 otherwise")); err != nil {
+		return nil, err
+	}
+	if e.serviceLatency, err = meter.Float64Histogram("chaos_monitor.service.response_time",
+		metric.WithDescription("AWS service check time"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if e.serviceAvailable, err = meter.Float64Gauge("chaos_monitor.service.availability",
+		metric.WithDescription("AWS service availability since start"), metric.WithUnit("%")); err != nil {
+		return nil, err
+	}
+	if e.activeFaults, err = meter.Int64Gauge("chaos_monitor.chaos.active_faults",
+		metric.WithDescription("Faults configured in the Chaos API")); err != nil {
+		return nil, err
+	}
+	if e.activeEffects, err = meter.Int64Gauge("chaos_monitor.chaos.active_effects",
+		metric.WithDescription("Network effects configured in the Chaos API")); err != nil {
+		return nil, err
+	}
+	if e.activeTests, err = meter.Int64Gauge("chaos_monitor.chaos.active_tests",
+		metric.WithDescription("Detected active chaos tests")); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// record captures the state of one tick of the LocalStack at
+// localstackURL, which every data point carries as localstack.url. While
+// a chaos test is active the response times also carry its type, so
+// latency can be split by test.
+func (e *metricsExporter) record(ctx context.Context, localstackURL string, state *models.MonitorState) {
+	instance := attribute.String("localstack.url", localstackURL)
+	var testAttrs []attribute.KeyValue
+	if testType := activeTestLabel(state.ActiveTests); testType != "" {
+		testAttrs = append(testAttrs, attribute.String("chaos.test", testType))
+	}
+
+	for _, endpoint := range state.NginxEndpoints {
+		attrs := []attribute.KeyValue{instance, attribute.String("endpoint", endpoint.Name)}
+		e.endpointUp.Record(ctx, boolToInt(endpoint.Available()), metric.WithAttributes(attrs...))
+		e.endpointLatency.Record(ctx, endpoint.ResponseTime, metric.WithAttributes(append(attrs, testAttrs...)...))
+	}
+
+	for _, service := range state.AWSServices {
+		attrs := []attribute.KeyValue{
+			instance,
+			attribute.String("service", service.Name),
+			attribute.String("region", service.Region),
+		}
+		e.serviceUp.Record(ctx, boolToInt(service.Status == "healthy"), metric.WithAttributes(attrs...))
+		e.serviceLatency.Record(ctx, service.ResponseTime, metric.WithAttributes(append(attrs, testAttrs...)...))
+	}
+	for name, stats := range state.Stats.ServiceStats {
+		e.serviceAvailable.Record(ctx, stats.AvailabilityPct, metric.WithAttributes(instance, attribute.String("service", name)))
+	}
+
+	e.activeFaults.Record(ctx, int64(len(state.ChaosAPIFaults)), metric.WithAttributes(instance))
+	e.activeEffects.Record(ctx, int64(len(state.ChaosAPIEffects)), metric.WithAttributes(instance))
+	e.activeTests.Record(ctx, int64(len(state.ActiveTests)), metric.WithAttributes(instance))
+}
+
+// shutdown flushes pending measurements and stops the exporter
+func (e *metricsExporter) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return e.provider.Shutdown(ctx)
+}
+
+// activeTestLabel is the type of the longest-running active test, or ""
+// when none is active. Types outside the known set, such as ones named in
+// -test-type or a remote status file, become "other" so the label's
+// cardinality stays bounded.
+func activeTestLabel(tests []models.ActiveChaosTest) string {
+	if len(tests) == 0 {
+		return ""
+	}
+	oldest := tests[0]
+	for _, test := range tests[1:] {
+		if test.StartTime.Before(oldest.StartTime) {
+			oldest = test
+		}
+	}
+	for _, testType := range monitor.DefaultTestTypes {
+		if oldest.Type == testType {
+			return testType
+		}
+	}
+	return "other"
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
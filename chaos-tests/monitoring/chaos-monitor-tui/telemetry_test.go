@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chaos-monitor-tui/models"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collectAttributes records each state against its LocalStack URL and
+// returns the attribute sets of every data point collected, by metric name
+func collectAttributes(t *testing.T, ticks map[string]*models.MonitorState) map[string][]attribute.Set {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	exporter, err := newReaderMetricsExporter(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for url, state := range ticks {
+		exporter.record(ctx, url, state)
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &data); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := data.Resource.Set().Value("localstack.url"); ok {
+		t.Error("localstack.url is a resource attribute")
+	}
+
+	points := make(map[string][]attribute.Set)
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch d := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				for _, p := range d.DataPoints {
+					points[m.Name] = append(points[m.Name], p.Attributes)
+				}
+			case metricdata.Gauge[float64]:
+				for _, p := range d.DataPoints {
+					points[m.Name] = append(points[m.Name], p.Attributes)
+				}
+			case metricdata.Histogram[float64]:
+				for _, p := range d.DataPoints {
+					points[m.Name] = append(points[m.Name], p.Attributes)
+				}
+			}
+		}
+	}
+	return points
+}
+
+func TestMetricsCarryTarget(t *testing.T) {
+	state := func(endpoint string) *models.MonitorState {
+		s := newTestModel(t).state
+		s.NginxEndpoints = []models.EndpointStatus{{Name: endpoint, Status: "ok", ResponseTime: 0.1}}
+		s.AWSServices = []models.ServiceStatus{{Name: "s3", Region: "us-east-1", Status: "healthy"}}
+		s.Stats.ServiceStats["s3"] = &models.ServiceStats{AvailabilityPct: 100}
+		return &s
+	}
+	points := collectAttributes(t, map[string]*models.MonitorState{
+		"http://primary:4566":   state("Main Site"),
+		"http://secondary:4566": state("Main Site"),
+	})
+
+	for _, name := range []string{
+		"chaos_monitor.endpoint.up",
+		"chaos_monitor.endpoint.response_time",
+		"chaos_monitor.service.up",
+		"chaos_monitor.service.response_time",
+		"chaos_monitor.service.availability",
+		"chaos_monitor.chaos.active_faults",
+		"chaos_monitor.chaos.active_effects",
+		"chaos_monitor.chaos.active_tests",
+	} {
+		urls := make(map[string]bool)
+		for _, attrs := range points[name] {
+			url, _ := attrs.Value("localstack.url")
+			urls[url.AsString()] = true
+		}
+		if len(points[name]) != 2 || !urls["http://primary:4566"] || !urls["http://secondary:4566"] {
+			t.Errorf("%s points don't each name their target: %v", name, points[name])
+		}
+	}
+}
+
+func TestChaosTestAttribute(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	state := func(tests ...models.ActiveChaosTest) *models.MonitorState {
+		s := newMonitorState()
+		s.NginxEndpoints = []models.EndpointStatus{{Name: "Main Site", Status: "ok", ResponseTime: 0.1}}
+		s.AWSServices = []models.ServiceStatus{{Name: "s3", Region: "us-east-1", Status: "healthy"}}
+		s.ActiveTests = tests
+		return &s
+	}
+	tests := []struct {
+		name   string
+		active []models.ActiveChaosTest
+		want   string // "" for no attribute
+	}{
+		{"no test", nil, ""},
+		{"one test", []models.ActiveChaosTest{{Type: "region-failure", StartTime: start}}, "region-failure"},
+		{"longest running", []models.ActiveChaosTest{
+			{Type: "latency-injection", StartTime: start.Add(time.Minute)},
+			{Type: "service-outage", StartTime: start},
+		}, "service-outage"},
+		{"unknown type", []models.ActiveChaosTest{{Type: "my-custom-test", StartTime: start}}, "other"},
+	}
+	for _, tt := range tests {
+		points := collectAttributes(t, map[string]*models.MonitorState{"http://localhost:4566": state(tt.active...)})
+		for _, name := range []string{"chaos_monitor.endpoint.response_time", "chaos_monitor.service.response_time"} {
+			if len(points[name]) != 1 {
+				t.Fatalf("%s: %d %s points", tt.name, len(points[name]), name)
+			}
+			got, ok := points[name][0].Value("chaos.test")
+			if ok != (tt.want != "") || got.AsString() != tt.want {
+				t.Errorf("%s: %s chaos.test = %q (present %v), want %q", tt.name, name, got.AsString(), ok, tt.want)
+			}
+		}
+		// Only the latencies are split by test
+		for _, name := range []string{"chaos_monitor.endpoint.up", "chaos_monitor.service.up"} {
+			for _, attrs := range points[name] {
+				if attrs.HasValue("chaos.test") {
+					t.Errorf("%s: %s has chaos.test", tt.name, name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+#!/bin/sh
+# Chaos scenario exported by chaos-monitor-tui at 2024-05-01 12:00:00 UTC
+# 0 fault(s), 2 network effect(s)
+set -e
+
+LOCALSTACK_URL="${LOCALSTACK_URL:-http://localhost:4566}"
+
+# Faults
+curl -fsS -X POST "$LOCALSTACK_URL/_localstack/chaos/faults" \
+  -H 'Content-Type: application/json' \
+  -d '[]'
+
+# Network effects
+curl -fsS -X POST "$LOCALSTACK_URL/_localstack/chaos/effects" \
+  -H 'Content-Type: application/json' \
+  -d '[{"id":"","latency":200,"service":"s3"},{"id":"","latency":0,"probability":0.1}]'
@@ -0,0 +1,18 @@
+#!/bin/sh
+# Chaos scenario exported by chaos-monitor-tui at 2024-05-01 12:00:00 UTC
+# 1 fault(s), 1 network effect(s)
+set -e
+
+LOCALSTACK_URL="${LOCALSTACK_URL:-http://localhost:4566}"
+
+# Faults
+curl -fsS -X POST "$LOCALSTACK_URL/_localstack/chaos/faults" \
+  -H 'Content-Type: application/json' \
+  -d '[
+  {"service":"s3","region":"us-east-1","probability":0.5,"error":{"statusCode":503,"code":"ServiceUnavailable"}}
+]'
+
+# Network effects
+curl -fsS -X POST "$LOCALSTACK_URL/_localstack/chaos/effects" \
+  -H 'Content-Type: application/json' \
+  -d '{"id":"","latency":500,"jitter":50}'
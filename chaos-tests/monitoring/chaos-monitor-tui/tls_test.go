@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTTPSProbeVerification(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	// The rejected handshake is expected, not worth logging
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	// The test server's self-signed certificate, as a -ca-cert file
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(block), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		insecure  bool
+		caFile    string
+		status    string
+		errorKind string
+	}{
+		{"system roots", false, "", "failed", "tls"},
+		{"-insecure", true, "", "ok", ""},
+		{"-ca-cert", false, caFile, "ok", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := newTLSConfig(tt.insecure, tt.caFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := newTestModel(t)
+			m.cfg.tlsConfig = tlsConfig
+			m.client = newHTTPClient(nil, tlsConfig)
+
+			status := m.checkHTTPOnce(m.ctx, endpointDef{name: "Secure Site", url: server.URL})
+			if status.Status != tt.status || status.ErrorKind != tt.errorKind {
+				t.Errorf("got %s (%q, %s), want %s (%s)", status.Status, status.ErrorKind, status.Reason, tt.status, tt.errorKind)
+			}
+			if tt.errorKind == "tls" && (!strings.HasPrefix(status.Reason, "TLS: ") || strings.Contains(status.Reason, server.URL)) {
+				t.Errorf("reason = %q", status.Reason)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(dir, "missing.pem"), notPEM} {
+		if _, err := newTLSConfig(false, path); err == nil || !strings.HasPrefix(err.Error(), "-ca-cert: ") {
+			t.Errorf("%s: error %v", path, err)
+		}
+	}
+}
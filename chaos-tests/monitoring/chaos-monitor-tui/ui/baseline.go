@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"chaos-monitor-tui/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Deltas smaller than these are shown as unchanged
+const (
+	availabilityNoise = 0.05  // Percentage points
+	responseTimeNoise = 0.001 // Seconds
+)
+
+// renderBaseline compares each endpoint and service also in the baseline
+// with it, e.g. "Main Site: 72.0% (−27.1%), 1.800s (+1.600s)"
+func renderBaseline(state *models.MonitorState, baseline *models.Statistics) string {
+	endpoints := make([]string, 0, len(state.Stats.NginxStats))
+	for name := range state.Stats.NginxStats {
+		endpoints = append(endpoints, name)
+	}
+	sort.Strings(endpoints)
+
+	var parts []string
+	for _, name := range endpoints {
+		if base, ok := baseline.NginxStats[name]; ok {
+			stats := state.Stats.NginxStats[name]
+			parts = append(parts, formatBaselineDelta(name, stats.SuccessRate, stats.ResponseTimeEMA, models.EndpointDelta(stats, base)))
+		}
+	}
+	for _, name := range sortedStatNames(state.Stats.ServiceStats) {
+		if base, ok := baseline.ServiceStats[name]; ok {
+			stats := state.Stats.ServiceStats[name]
+			parts = append(parts, formatBaselineDelta(name, stats.AvailabilityPct, stats.ResponseTimeEMA, models.ServiceDelta(stats, base)))
+		}
+	}
+	if len(parts) == 0 {
+		return styles.dim.Render("Nothing in common with the baseline")
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatBaselineDelta renders a row's availability and average response
+// time with their change from the baseline, colored by whether it's worse
+func formatBaselineDelta(name string, availability, responseTime float64, delta models.BaselineDelta) string {
+	return fmt.Sprintf("%s: %.1f%% %s, %.3fs %s",
+		name,
+		availability, deltaStyle(delta.Availability, availabilityNoise, false).Render(fmt.Sprintf("(%s%%)", signed(delta.Availability, "%.1f"))),
+		responseTime, deltaStyle(delta.ResponseTime, responseTimeNoise, true).Render(fmt.Sprintf("(%ss)", signed(delta.ResponseTime, "%.3f"))))
+}
+
+// deltaStyle colors a change red when it's a degradation and green when
+// it's an improvement; for response times higher is worse
+func deltaStyle(delta, noise float64, higherIsWorse bool) lipgloss.Style {
+	if math.Abs(delta) < noise {
+		return styles.dim
+	}
+	if (delta > 0) == higherIsWorse {
+		return styles.statusError
+	}
+	return styles.statusOK
+}
+
+// signed formats a change with an explicit sign, using a minus sign rather
+// than a hyphen
+func signed(delta float64, format string) string {
+	if delta < 0 {
+		return "−" + fmt.Sprintf(format, -delta)
+	}
+	return "+" + fmt.Sprintf(format, delta)
+}
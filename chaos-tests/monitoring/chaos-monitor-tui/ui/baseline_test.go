@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"regexp"
+	"testing"
+
+	"chaos-monitor-tui/models"
+)
+
+// ansi matches the color escapes lipgloss adds
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestRenderBaseline(t *testing.T) {
+	state := &models.MonitorState{Stats: models.Statistics{
+		NginxStats: map[string]*models.EndpointStats{
+			"Main Site": {SuccessRate: 72, ResponseTimeEMA: 1.8},
+			"New Site":  {SuccessRate: 100, ResponseTimeEMA: 0.1},
+		},
+		ServiceStats: map[string]*models.ServiceStats{
+			"S3": {AvailabilityPct: 99.5, ResponseTimeEMA: 0.2004},
+		},
+	}}
+	baseline := &models.Statistics{
+		NginxStats: map[string]*models.EndpointStats{
+			"Main Site": {SuccessRate: 99.1, ResponseTimeEMA: 0.2},
+		},
+		ServiceStats: map[string]*models.ServiceStats{
+			"S3":  {AvailabilityPct: 98, ResponseTimeEMA: 0.2},
+			"SQS": {AvailabilityPct: 100},
+		},
+	}
+
+	// Only rows in both runs are compared
+	want := "Main Site: 72.0% (−27.1%), 1.800s (+1.600s) | S3: 99.5% (+1.5%), 0.200s (+0.000s)"
+	if got := ansi.ReplaceAllString(renderBaseline(state, baseline), ""); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+
+	if got := ansi.ReplaceAllString(renderBaseline(state, &models.Statistics{}), ""); got != "Nothing in common with the baseline" {
+		t.Errorf("with nothing in common: %q", got)
+	}
+}
+
+func TestDeltaStyle(t *testing.T) {
+	tests := []struct {
+		delta         float64
+		noise         float64
+		higherIsWorse bool
+		want          string
+	}{
+		{-27.1, availabilityNoise, false, "worse"},
+		{1.5, availabilityNoise, false, "better"},
+		{0.04, availabilityNoise, false, "unchanged"},
+		{-0.04, availabilityNoise, false, "unchanged"},
+		{1.6, responseTimeNoise, true, "worse"},
+		{-0.5, responseTimeNoise, true, "better"},
+		{0.0004, responseTimeNoise, true, "unchanged"},
+	}
+	want := map[string]string{"worse": "error", "better": "ok", "unchanged": "dim"}
+	for _, tt := range tests {
+		got := deltaStyle(tt.delta, tt.noise, tt.higherIsWorse)
+		var name string
+		switch {
+		case sameStyle(got, styles.statusError):
+			name = "error"
+		case sameStyle(got, styles.statusOK):
+			name = "ok"
+		case sameStyle(got, styles.dim):
+			name = "dim"
+		}
+		if name != want[tt.want] {
+			t.Errorf("deltaStyle(%v, higher is worse %v) = %s, want %s", tt.delta, tt.higherIsWorse, name, want[tt.want])
+		}
+	}
+}
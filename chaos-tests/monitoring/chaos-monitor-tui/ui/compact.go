@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CompactHeightThreshold is the terminal height below which the compact
+// summary is used automatically
+const CompactHeightThreshold = 24
+
+// RenderCompact collapses the dashboard into a title and a couple of summary lines
+func RenderCompact(state *models.MonitorState, opts DashboardOptions, width int) string {
+	var lines []string
+
+	title := fmt.Sprintf("🔍 Chaos Monitor | %s | Updates: %d", time.Now().Format("15:04:05"), state.UpdateCount)
+	if countdown := formatCountdown(opts.NextRefresh); countdown != "" {
+		title += " | " + countdown
+	}
+	if opts.Target != "" {
+		title += " | " + opts.Target
+	}
+	if opts.Replay != "" {
+		title += " | " + opts.Replay
+	}
+	if opts.Paused {
+		title += " | PAUSED"
+	}
+	if opts.Notice != "" {
+		title += " | " + opts.Notice
+	}
+	lines = append(lines, renderTitleBar(title, state.ChaosIntensity, width))
+
+	// Overall availability and failing counts
+	availText := styles.dim.Render("Availability: n/a")
+	if avail := state.SystemAvailability; avail != nil {
+		availText = availabilityStyle(*avail).Render(fmt.Sprintf("Availability: %.1f%%", *avail))
+	}
+
+	failingEndpoints := 0
+	for _, endpoint := range state.NginxEndpoints {
+		if endpoint.Status != "ok" {
+			failingEndpoints++
+		}
+	}
+	failingServices := 0
+	for _, service := range state.AWSServices {
+		if service.Status != "healthy" {
+			failingServices++
+		}
+	}
+
+	lines = append(lines, strings.Join([]string{
+		availText,
+		countStyle(failingEndpoints).Render(fmt.Sprintf("Endpoints failing: %d/%d", failingEndpoints, len(state.NginxEndpoints))),
+		countStyle(failingServices).Render(fmt.Sprintf("Services failing: %d/%d", failingServices, len(state.AWSServices))),
+		countStyle(len(state.ChaosAPIFaults) + len(state.ChaosAPIEffects)).Render(
+			fmt.Sprintf("Faults: %d Effects: %d", len(state.ChaosAPIFaults), len(state.ChaosAPIEffects))),
+	}, " | "))
+
+	// Most severe active test
+	if test, ok := mostSevereTest(state.ActiveTests); ok {
+		icon, style := getTestDisplay(test.Type)
+		extra := ""
+		if len(state.ActiveTests) > 1 {
+			extra = styles.dim.Render(fmt.Sprintf(" (+%d more)", len(state.ActiveTests)-1))
+		}
+		lines = append(lines, fmt.Sprintf("%s %s: %s%s", icon, style.Render(strings.ToUpper(test.Type)), test.Target, extra))
+	} else {
+		lines = append(lines, styles.statusOK.Render("✓ No active chaos tests detected"))
+	}
+
+	lines = append(lines, styles.dim.Render("'c' full view | 'q' quit"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// mostSevereTest picks the active test with the highest severity
+func mostSevereTest(tests []models.ActiveChaosTest) (models.ActiveChaosTest, bool) {
+	if len(tests) == 0 {
+		return models.ActiveChaosTest{}, false
+	}
+	worst := tests[0]
+	for _, test := range tests[1:] {
+		if testSeverity(test.Type) > testSeverity(worst.Type) {
+			worst = test
+		}
+	}
+	return worst, true
+}
+
+// countStyle colors a count of problems: OK when zero, error otherwise
+func countStyle(n int) lipgloss.Style {
+	if n == 0 {
+		return styles.statusOK
+	}
+	return styles.statusError
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,147 +11,125 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-var (
-	// Colors
-	successColor = lipgloss.Color("#00ff00")  // Bright green
-	warningColor = lipgloss.Color("#ffaa00")  // Orange/yellow
-	errorColor   = lipgloss.Color("#ff0000")  // Bright red
-	infoColor    = lipgloss.Color("#00aaff")  // Light blue
-	dimColor     = lipgloss.Color("#666666")  // Gray
-	purpleColor  = lipgloss.Color("#aa00ff")  // Purple
-
-	// Styles
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#ffffff")).
-			Background(lipgloss.Color("#5a56e0")).
-			Padding(0, 1)
-
-	sectionStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#5a56e0")).
-			Padding(0, 1)
-
-	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(infoColor).
-			MarginBottom(1)
-
-	statusOKStyle = lipgloss.NewStyle().
-			Foreground(successColor).
-			Bold(true)
-
-	statusWarningStyle = lipgloss.NewStyle().
-				Foreground(warningColor).
-				Bold(true)
-
-	statusErrorStyle = lipgloss.NewStyle().
-			Foreground(errorColor).
-			Bold(true)
-
-	statusExhaustedStyle = lipgloss.NewStyle().
-				Foreground(purpleColor).
-				Bold(true)
-
-	dimStyle = lipgloss.NewStyle().
-			Foreground(dimColor)
-			
-	// Availability styles based on percentage
-	availHighStyle = lipgloss.NewStyle().
-			Foreground(successColor)  // Green for > 90%
-			
-	availMedStyle = lipgloss.NewStyle().
-			Foreground(warningColor)  // Yellow for 50-90%
-			
-	availLowStyle = lipgloss.NewStyle().
-			Foreground(errorColor)    // Red for < 50%
-)
+// DashboardOptions carries view settings that aren't part of the monitored state
+type DashboardOptions struct {
+	Paused   bool // Refresh loop is paused
+	Control  ControlPanel
+	EventLog string           // Rendered event log viewport; empty when hidden
+	Changed  models.ChangeSet // Rows to highlight because they just changed
+	Replay   string           // Playback position when replaying a recording
+	Target   string           // Shown LocalStack instance, when there's more than one
+	Filter   Filter           // Rows to show in the endpoint and service tables
+	Notice   string           // Short-lived message, e.g. a copy confirmation
 
-// RenderDashboard creates the complete dashboard view
-func RenderDashboard(state *models.MonitorState, width, height int) string {
-	var sections []string
+	NextRefresh time.Time  // When the next data refresh is due; zero hides the countdown
+	SLO         models.SLO // Error budget objective; hidden unless enabled
+	Selected    string     // Selected row, as "endpoint|<name>" or "service|<label>"
+	Tab         int        // Active tab, one of the Tab* constants
+	Sort        SortOrder  // Order of the endpoint and service tables
 
-	// Title bar
-	title := titleStyle.Width(width - 2).Render(
-		fmt.Sprintf("🔍 Chaos Engineering Monitor | %s | Updates: %d | Press 'q' to quit",
-			time.Now().Format("15:04:05"),
-			state.UpdateCount,
-		),
-	)
-	sections = append(sections, title)
+	Baseline *models.Statistics // Earlier run to show changes against; nil hides them
 
-	// Chaos API Status
-	chaosSection := renderChaosAPIStatus(state, width)
-	sections = append(sections, chaosSection)
+	Maintenance bool // Alerts are suppressed, by 'm' or -quiet-hours
 
-	// Nginx Web Servers
-	nginxSection := renderNginxStatus(state, width)
-	sections = append(sections, nginxSection)
+	FaultTrend []models.FaultCount // Faults and effects configured at recent refreshes, oldest first
+}
 
-	// AWS Services
-	servicesSection := renderServicesStatus(state, width)
-	sections = append(sections, servicesSection)
+// FormField is a single labelled input in a form
+type FormField struct {
+	Label string
+	Value string
+}
 
-	// Statistics
-	statsSection := renderStatistics(state, width)
-	sections = append(sections, statsSection)
+// ControlPanel describes the fault injection controls
+type ControlPanel struct {
+	Enabled bool
+	Form    []FormField // Non-nil while the add-fault form is open
+	Focus   int         // Index of the focused form field
+	Message string      // Result of the last control action
+	IsError bool
+}
+
+// RenderDashboardHeader renders the title and tab bars, which stay on
+// screen while the body scrolls
+func RenderDashboardHeader(state *models.MonitorState, opts DashboardOptions, width int) string {
+	titleText := fmt.Sprintf("🔍 Chaos Engineering Monitor | %s | Updates: %d | Press 'q' to quit",
+		time.Now().Format("15:04:05"),
+		state.UpdateCount,
+	)
+	if countdown := formatCountdown(opts.NextRefresh); countdown != "" {
+		titleText += " | " + countdown
+	}
+	if opts.Target != "" {
+		titleText += " | " + opts.Target + " ('t' to switch)"
+	}
+	if opts.Filter.Active() || opts.Filter.Editing {
+		titleText += " | " + opts.Filter.String() + " (esc to clear)"
+	}
+	if opts.Replay != "" {
+		titleText += " | " + opts.Replay + " ('←'/'→' to step)"
+	}
+	if opts.Paused {
+		titleText += " | PAUSED ('p' to resume)"
+	}
+	if opts.Maintenance {
+		titleText += " | 🔕 MAINTENANCE, alerts suppressed"
+	}
+	if opts.Notice != "" {
+		titleText += " | " + opts.Notice
+	}
+	title := renderTitleBar(titleText, state.ChaosIntensity, width-2)
+	return lipgloss.JoinVertical(lipgloss.Left, title, renderTabBar(opts.Tab, width))
+}
 
-	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+// RenderDashboardBody renders the active tab's sections below the header
+func RenderDashboardBody(state *models.MonitorState, opts DashboardOptions, width int) string {
+	return lipgloss.JoinVertical(lipgloss.Left, renderTab(state, opts, width)...)
 }
 
-func renderChaosAPIStatus(state *models.MonitorState, width int) string {
+func renderChaosAPIStatus(state *models.MonitorState, opts DashboardOptions, width int) string {
 	var content strings.Builder
 
-	content.WriteString(headerStyle.Render("ACTIVE CHAOS TESTS"))
+	// The fault trend goes on the title's line, leaving the rest of the
+	// section where it was
+	title := "ACTIVE CHAOS TESTS"
+	header := styles.header.Render(title)
+	if trend := renderFaultTrend(opts.FaultTrend, width-6-len(title)); trend != "" {
+		first, margin, _ := strings.Cut(header, "\n")
+		header = first + trend + "\n" + margin
+	}
+	content.WriteString(header)
+
+	if state.ChaosAPIWarning != "" {
+		content.WriteString(styles.statusWarning.Render("⚠ Chaos API format unexpected: "+state.ChaosAPIWarning) + "\n")
+		content.WriteString(styles.dim.Render("   Showing the last configuration read successfully") + "\n\n")
+	}
 
 	// Show detected active tests first
 	if len(state.ActiveTests) > 0 {
 		for _, test := range state.ActiveTests {
-			var testStyle lipgloss.Style
-			var icon string
-			
-			switch test.Type {
-			case "region-failure":
-				testStyle = statusErrorStyle
-				icon = "🔥"
-			case "service-outage":
-				testStyle = statusErrorStyle
-				icon = "⚠️"
-			case "api-throttling":
-				testStyle = statusWarningStyle
-				icon = "🚦"
-			case "network-latency":
-				testStyle = statusWarningStyle
-				icon = "🌐"
-			case "cascade-failure":
-				testStyle = statusErrorStyle
-				icon = "📉"
-			case "resource-exhaustion":
-				testStyle = statusExhaustedStyle
-				icon = "💾"
-			default:
-				testStyle = statusWarningStyle
-				icon = "🧪"
-			}
-			
-			content.WriteString(fmt.Sprintf("%s %s: %s\n", 
+			icon, testStyle := getTestDisplay(test.Type)
+
+			content.WriteString(fmt.Sprintf("%s %s: %s%s\n", 
 				icon,
 				testStyle.Render(strings.ToUpper(test.Type)),
-				test.Target))
-			content.WriteString(fmt.Sprintf("   └─ %s\n", dimStyle.Render(test.Details)))
+				test.Target,
+				renderImpact(test)))
+			content.WriteString(fmt.Sprintf("   └─ %s\n", styles.dim.Render(test.Details)))
 		}
 		content.WriteString("\n")
 	}
 
 	// Then show raw Chaos API data
-	if len(state.ChaosAPIFaults) == 0 && len(state.ChaosAPIEffects) == 0 && len(state.ActiveTests) == 0 {
-		content.WriteString(statusOKStyle.Render("✓ No active chaos tests detected\n"))
+	if len(state.ChaosAPIFaults) == 0 && len(state.ChaosAPIEffects) == 0 && len(state.ActiveTests) == 0 && state.ChaosAPIWarning == "" {
+		content.WriteString(styles.statusOK.Render("✓ No active chaos tests detected\n"))
 	} else if len(state.ChaosAPIFaults) > 0 || len(state.ChaosAPIEffects) > 0 {
-		content.WriteString(dimStyle.Render("Chaos API Configurations:\n"))
+		content.WriteString(styles.dim.Render("Chaos API Configurations:\n"))
 		
 		if len(state.ChaosAPIFaults) > 0 {
-			faultStyle := statusWarningStyle
+			faultStyle := styles.statusWarning
 			content.WriteString(faultStyle.Render(fmt.Sprintf("├─ Service Faults: %d active\n", len(state.ChaosAPIFaults))))
+			content.WriteString("│  " + renderAffectedRegions(state.ChaosAPIFaults) + "\n")
 			for i, fault := range state.ChaosAPIFaults {
 				prefix := "│  ├─"
 				if i == len(state.ChaosAPIFaults)-1 {
@@ -160,124 +139,528 @@ func renderChaosAPIStatus(state *models.MonitorState, width int) string {
 				// Color based on probability
 				var probStyle lipgloss.Style
 				if fault.Probability >= 0.8 {
-					probStyle = statusErrorStyle
+					probStyle = styles.statusError
 				} else if fault.Probability >= 0.5 {
-					probStyle = statusWarningStyle
+					probStyle = styles.statusWarning
 				} else {
-					probStyle = dimStyle
+					probStyle = styles.dim
 				}
 				
-				content.WriteString(fmt.Sprintf("%s %s (%s): %s\n",
-					prefix, fault.Service, fault.Region, 
+				faultName := fmt.Sprintf("%s (%s)", fault.Service, fault.Region)
+				if opts.Changed.Faults[fault.Key()] {
+					faultName = styles.flash.Render(faultName)
+				}
+
+				content.WriteString(fmt.Sprintf("%s %s: %s\n",
+					prefix, faultName,
 					probStyle.Render(fmt.Sprintf("%.0f%% failure rate", fault.Probability*100))))
 			}
 		}
 
 		if len(state.ChaosAPIEffects) > 0 {
-			effectStyle := statusWarningStyle
+			effectStyle := styles.statusWarning
 			content.WriteString(effectStyle.Render(fmt.Sprintf("└─ Network Effects: %d active\n", len(state.ChaosAPIEffects))))
 			for _, effect := range state.ChaosAPIEffects {
 				// Color based on latency severity
-				var latencyStyle lipgloss.Style
-				if effect.Latency >= 5000 {
-					latencyStyle = statusErrorStyle
-				} else if effect.Latency >= 1000 {
-					latencyStyle = statusWarningStyle
-				} else {
-					latencyStyle = dimStyle
-				}
-				
-				content.WriteString(fmt.Sprintf("   └─ Latency: %s\n", 
-					latencyStyle.Render(fmt.Sprintf("%dms", effect.Latency))))
+				content.WriteString(fmt.Sprintf("   └─ %s: %s\n",
+					effect.Target(), latencyStyle(effect.Latency).Render(effect.Summary())))
 			}
 		}
 	}
 
-	return sectionStyle.Width(width - 2).Render(content.String())
+	if len(state.CompletedTests) > 0 {
+		content.WriteString(styles.dim.Render("\nRecently completed:\n"))
+		for _, test := range state.CompletedTests {
+			icon, _ := getTestDisplay(test.Type)
+			content.WriteString(styles.dim.Render(fmt.Sprintf("%s %s: %s (%s)\n",
+				icon, strings.ToUpper(test.Type), test.Target, completedSummary(test))))
+		}
+	}
+
+	if opts.Control.Enabled {
+		content.WriteString(renderControlPanel(opts.Control))
+	}
+
+	return styles.section.Width(width - 2).Render(content.String())
+}
+
+func renderControlPanel(control ControlPanel) string {
+	var content strings.Builder
+
+	content.WriteString("\n")
+	if control.Form != nil {
+		content.WriteString(styles.header.Render("INJECT FAULT"))
+		for i, field := range control.Form {
+			cursor := "  "
+			value := field.Value
+			if i == control.Focus {
+				cursor = "▸ "
+				value += "█"
+			}
+			content.WriteString(fmt.Sprintf("%s%-12s %s\n", cursor, field.Label+":", value))
+		}
+		content.WriteString(styles.dim.Render("tab: next field | enter: submit | esc: cancel\n"))
+	} else {
+		content.WriteString(styles.dim.Render("Controls: 'a' add fault | 'x' clear all faults\n"))
+	}
+
+	if control.Message != "" {
+		style := styles.statusOK
+		if control.IsError {
+			style = styles.statusError
+		}
+		content.WriteString(style.Render(control.Message))
+	}
+
+	return content.String()
 }
 
-func renderNginxStatus(state *models.MonitorState, width int) string {
+func renderNginxStatus(state *models.MonitorState, opts DashboardOptions, width int) string {
 	var content strings.Builder
 
-	content.WriteString(headerStyle.Render("NGINX WEB SERVERS"))
-	content.WriteString(fmt.Sprintf("%-30s %-10s %s\n", "Endpoint", "Status", "Response"))
+	content.WriteString(styles.header.Render("NGINX WEB SERVERS"))
+	content.WriteString(fmt.Sprintf("%-30s %-6s %-10s %-11s %s", "Endpoint", "Probe", "Status", "Last OK", "Response"))
+	content.WriteString(renderSortHint(opts.Sort) + "\n")
 
 	// Check if main site is down
 	mainSiteDown := false
 	for _, endpoint := range state.NginxEndpoints {
-		if endpoint.Name == "Main Site" && endpoint.Status != "ok" {
+		if endpoint.Name == "Main Site" && !endpoint.Available() {
 			mainSiteDown = true
 			break
 		}
 	}
 
-	for _, endpoint := range state.NginxEndpoints {
+	endpoints := FilterEndpoints(SortEndpoints(state, opts.Sort), opts.Filter)
+	if len(endpoints) == 0 && opts.Filter.Active() {
+		content.WriteString(styles.dim.Render("No endpoints match the filter") + "\n")
+	}
+	for _, endpoint := range endpoints {
 		statusIcon, statusStyle := getStatusDisplay(endpoint.Status)
 		
 		// Special handling for main site - always red if down
-		if endpoint.Name == "Main Site" && endpoint.Status != "ok" {
-			statusStyle = statusErrorStyle
+		if endpoint.Name == "Main Site" && !endpoint.Available() {
+			statusStyle = styles.statusError
 		}
 		
 		// Color the endpoint name based on importance
 		endpointStyle := lipgloss.NewStyle()
 		if mainSiteDown && endpoint.Name == "Main Site" {
-			endpointStyle = statusErrorStyle
+			endpointStyle = styles.statusError
+		}
+		if opts.Changed.Endpoints[endpoint.Name] {
+			endpointStyle = styles.flash
 		}
 		
-		content.WriteString(fmt.Sprintf("├─ %-28s %s %-8s %s\n",
+		content.WriteString(fmt.Sprintf("%s %-28s %s %s %-8s %s %s\n",
+			rowMarker(opts.Selected == "endpoint|"+endpoint.Name),
 			endpointStyle.Render(endpoint.Name),
+			styles.dim.Render(fmt.Sprintf("%-6s", strings.ToUpper(endpoint.ProbeKind))),
 			statusStyle.Render(statusIcon),
-			statusStyle.Render(strings.ToUpper(endpoint.Status)),
-			dimStyle.Render(fmt.Sprintf("%.3fs", endpoint.ResponseTime)),
+			statusStyle.Render(endpointStatusLabel(endpoint.Status)),
+			styles.dim.Render(fmt.Sprintf("%-11s", formatLastOK(endpointLastOK(state, endpoint.Name)))),
+			responseTimeStyle(endpoint.ResponseTime).Render(fmt.Sprintf("%.3fs", endpoint.ResponseTime))+
+				styles.dim.Render(formatEMA(endpointEMA(state, endpoint.Name))+formatRetries(endpoint.Retries))+
+				formatFailStreak(endpointFailStreak(state, endpoint.Name)),
 		))
+		if endpoint.Status != "ok" && endpoint.Reason != "" {
+			content.WriteString(styles.dim.Render("│  └─ "+endpoint.Reason) + "\n")
+		} else if endpoint.ContentMatch == "matched" {
+			content.WriteString(styles.dim.Render("│  └─ ✓ content matched") + "\n")
+		}
 	}
 
+	content.WriteString(renderEndpointGroups(state.EndpointGroups))
+
 	// Calculate and display availability with colors
 	if len(state.Stats.NginxStats) > 0 {
 		content.WriteString("\nAvailability: ")
 		var availParts []string
 		for name, stats := range state.Stats.NginxStats {
 			// Color based on availability percentage
-			var style lipgloss.Style
-			if stats.SuccessRate >= 90 {
-				style = availHighStyle
-			} else if stats.SuccessRate >= 50 {
-				style = availMedStyle
-			} else {
-				style = availLowStyle
-			}
+			style := availabilityStyle(stats.SuccessRate)
 			availParts = append(availParts, style.Render(fmt.Sprintf("%s: %.1f%%", name, stats.SuccessRate)))
 		}
 		content.WriteString(strings.Join(availParts, " | "))
 	}
 
-	return sectionStyle.Width(width - 2).Render(content.String())
+	// Recent checks as a heatmap, so flapping and sustained outages stand out
+	var heatmap strings.Builder
+	for _, endpoint := range endpoints {
+		if stats, ok := state.Stats.NginxStats[endpoint.Name]; ok {
+			heatmap.WriteString(renderHeatmapRow(endpoint.Name, stats.Recent, width-4))
+		}
+	}
+	if heatmap.Len() > 0 {
+		content.WriteString("\n\n" + styles.dim.Render("Recent checks, oldest first") + "\n")
+		content.WriteString(strings.TrimSuffix(heatmap.String(), "\n"))
+	}
+
+	return styles.section.Width(width - 2).Render(content.String())
 }
 
-func renderServicesStatus(state *models.MonitorState, width int) string {
+func renderServicesStatus(state *models.MonitorState, opts DashboardOptions, width int) string {
 	var content strings.Builder
 
-	content.WriteString(headerStyle.Render("AWS SERVICES"))
-	content.WriteString(fmt.Sprintf("%-20s %-10s %s\n", "Service", "Status", "Response"))
+	content.WriteString(styles.header.Render("AWS SERVICES"))
+	content.WriteString(renderLocalStackBackends(state.LocalStack))
 
-	for _, service := range state.AWSServices {
+	if hasRegionBreakdown(state) {
+		content.WriteString(renderRegionMatrix(state, opts))
+		return styles.section.Width(width - 2).Render(content.String())
+	}
+
+	content.WriteString(fmt.Sprintf("%-20s %-10s %-11s %s", "Service", "Status", "Last OK", "Response"))
+	content.WriteString(renderSortHint(opts.Sort) + "\n")
+
+	services := FilterServices(SortServices(state, opts.Sort), opts.Filter)
+	if len(services) == 0 && opts.Filter.Active() {
+		content.WriteString(styles.dim.Render("No services match the filter") + "\n")
+	}
+	for _, service := range services {
 		statusIcon, statusStyle := getServiceStatusDisplay(service.Status)
-		content.WriteString(fmt.Sprintf("├─ %-18s %s %-8s %s\n",
-			service.Name,
+		name := fmt.Sprintf("%-18s", service.Name)
+		if opts.Changed.Services[service.Label()] {
+			name = styles.flash.Render(name)
+		}
+		content.WriteString(fmt.Sprintf("%s %s %s %-8s %s %s\n",
+			rowMarker(opts.Selected == "service|"+service.Label()),
+			name,
 			statusStyle.Render(statusIcon),
 			statusStyle.Render(strings.ToUpper(service.Status[:6])),
-			dimStyle.Render(fmt.Sprintf("%.3fs", service.ResponseTime)),
+			styles.dim.Render(fmt.Sprintf("%-11s", formatLastOK(serviceLastOK(state, service)))),
+			responseTimeStyle(service.ResponseTime).Render(fmt.Sprintf("%.3fs", service.ResponseTime))+
+				styles.dim.Render(formatEMA(serviceEMA(state, service)))+
+				formatFailStreak(serviceFailStreak(state, service)),
 		))
+		if service.Status != "healthy" && service.Error != "" {
+			content.WriteString(styles.dim.Render("│  └─ "+service.Error) + "\n")
+		}
+	}
+
+	var heatmap strings.Builder
+	for _, service := range services {
+		heatmap.WriteString(renderHeatmapRow(service.Label(), serviceRecent(state, service), width-4))
+	}
+	if heatmap.Len() > 0 {
+		content.WriteString("\n" + styles.dim.Render("Recent checks, oldest first") + "\n" + heatmap.String())
+	}
+
+	return styles.section.Width(width - 2).Render(content.String())
+}
+
+// renderLocalStackBackends shows the backend states LocalStack reports for
+// the monitored services, or that its health endpoint is unreachable
+func renderLocalStackBackends(health models.LocalStackHealth) string {
+	if !health.Reachable {
+		return styles.statusError.Render("✗ LocalStack health endpoint unreachable") + "\n"
+	}
+	if len(health.Services) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(health.Services))
+	for name := range health.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		state := health.Services[name]
+		var icon string
+		var style lipgloss.Style
+		switch state {
+		case "running", "available":
+			icon, style = "✓", styles.statusOK
+		case "starting", "initialized":
+			icon, style = "⏳", styles.statusWarning
+		case "error":
+			icon, style = "✗", styles.statusError
+		default:
+			icon, style = "?", styles.dim
+		}
+		parts = append(parts, style.Render(icon+" "+name+" "+state))
+	}
+
+	label := "LocalStack"
+	if health.Version != "" {
+		label += " " + health.Version
+	}
+	return styles.dim.Render(label+": ") + strings.Join(parts, "  ") + "\n\n"
+}
+
+// endpointEMA returns an endpoint's smoothed response time, if known
+func endpointEMA(state *models.MonitorState, name string) (float64, bool) {
+	stats, ok := state.Stats.NginxStats[name]
+	if !ok || stats.TotalChecks == 0 {
+		return 0, false
+	}
+	return stats.ResponseTimeEMA, true
+}
+
+// serviceEMA returns a service's smoothed response time, per region when
+// the service is probed in several
+func serviceEMA(state *models.MonitorState, service models.ServiceStatus) (float64, bool) {
+	stats, ok := state.Stats.ServiceStats[service.Name]
+	if service.Region != "" {
+		stats, ok = state.Stats.RegionStats[service.Name][service.Region]
+	}
+	if !ok || stats.TotalChecks == 0 {
+		return 0, false
+	}
+	return stats.ResponseTimeEMA, true
+}
+
+// endpointLastOK returns when an endpoint last passed, or zero if never
+func endpointLastOK(state *models.MonitorState, name string) time.Time {
+	if stats, ok := state.Stats.NginxStats[name]; ok {
+		return stats.LastSuccess
 	}
+	return time.Time{}
+}
+
+// serviceLastOK returns when a service last passed, per region when the
+// service is probed in several, or zero if never
+func serviceLastOK(state *models.MonitorState, service models.ServiceStatus) time.Time {
+	stats, ok := state.Stats.ServiceStats[service.Name]
+	if service.Region != "" {
+		stats, ok = state.Stats.RegionStats[service.Name][service.Region]
+	}
+	if !ok {
+		return time.Time{}
+	}
+	return stats.LastSuccess
+}
+
+// endpointFailStreak returns how many checks in a row an endpoint has failed
+func endpointFailStreak(state *models.MonitorState, name string) int {
+	if stats, ok := state.Stats.NginxStats[name]; ok {
+		return stats.ConsecutiveFailures
+	}
+	return 0
+}
 
-	return sectionStyle.Width(width - 2).Render(content.String())
+// serviceFailStreak returns how many checks in a row a service has failed,
+// per region when the service is probed in several
+func serviceFailStreak(state *models.MonitorState, service models.ServiceStatus) int {
+	stats, ok := state.Stats.ServiceStats[service.Name]
+	if service.Region != "" {
+		stats, ok = state.Stats.RegionStats[service.Name][service.Region]
+	}
+	if !ok {
+		return 0
+	}
+	return stats.ConsecutiveFailures
+}
+
+// serviceRecent returns a service's recent check statuses, per region when
+// the service is probed in several
+func serviceRecent(state *models.MonitorState, service models.ServiceStatus) []string {
+	stats, ok := state.Stats.ServiceStats[service.Name]
+	if service.Region != "" {
+		stats, ok = state.Stats.RegionStats[service.Name][service.Region]
+	}
+	if !ok {
+		return nil
+	}
+	return stats.Recent
 }
 
-func renderStatistics(state *models.MonitorState, width int) string {
+// formatFailStreak notes a run of failed checks, so a one-off blip can be
+// told from an outage
+func formatFailStreak(failures int) string {
+	if failures == 0 {
+		return ""
+	}
+	return " " + styles.statusError.Render(fmt.Sprintf("failing ×%d", failures))
+}
+
+// formatLastOK renders how long ago a check last passed
+func formatLastOK(at time.Time) string {
+	if at.IsZero() {
+		return "never"
+	}
+	return time.Since(at).Round(time.Second).String() + " ago"
+}
+
+// formatEMA renders a smoothed response time after the instantaneous one
+func formatEMA(ema float64, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (avg %.3fs)", ema)
+}
+
+// regionImpact is a region named by the active faults and the services
+// faulted in it
+type regionImpact struct {
+	region   string
+	services map[string]bool // An empty name means every service
+}
+
+// faultRegions groups the active faults' services by region, in region
+// order. A fault without a region is listed as "all regions".
+func faultRegions(faults []models.ChaosAPIFault) []regionImpact {
+	byRegion := make(map[string]map[string]bool)
+	for _, fault := range faults {
+		region := strings.ToLower(fault.Region)
+		if region == "" {
+			region = "all regions"
+		}
+		if byRegion[region] == nil {
+			byRegion[region] = make(map[string]bool)
+		}
+		byRegion[region][strings.ToLower(fault.Service)] = true
+	}
+
+	regions := make([]regionImpact, 0, len(byRegion))
+	for region, services := range byRegion {
+		regions = append(regions, regionImpact{region: region, services: services})
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].region < regions[j].region
+	})
+	return regions
+}
+
+// renderAffectedRegions lists the regions the faults touch, each colored by
+// how many services are faulted in it
+func renderAffectedRegions(faults []models.ChaosAPIFault) string {
+	var parts []string
+	for _, impact := range faultRegions(faults) {
+		count := fmt.Sprintf("%d services", len(impact.services))
+		style := styles.statusError
+		switch {
+		case impact.services[""]:
+			count = "all services"
+		case len(impact.services) == 1:
+			count = "1 service"
+			style = styles.dim
+		case len(impact.services) == 2:
+			style = styles.statusWarning
+		}
+		parts = append(parts, style.Render(impact.region+" ("+count+")"))
+	}
+	return "Affected regions: " + strings.Join(parts, ", ")
+}
+
+// formatRetries notes how many attempts a check needed, if more than one
+func formatRetries(retries int) string {
+	switch retries {
+	case 0:
+		return ""
+	case 1:
+		return " (1 retry)"
+	default:
+		return fmt.Sprintf(" (%d retries)", retries)
+	}
+}
+
+// renderSortHint describes a non-default table order after a header row
+func renderSortHint(order SortOrder) string {
+	if order.Key == SortNone {
+		return ""
+	}
+	return styles.dim.Render("  (" + order.String() + ")")
+}
+
+// renderImpact shows which rows a fault-based test is affecting
+func renderImpact(test models.ActiveChaosTest) string {
+	if test.Impact == "" {
+		return ""
+	}
+	if len(test.Affected) == 0 {
+		return styles.dim.Render(" → " + test.Impact)
+	}
+	return " → " + styles.statusError.Render(test.Impact)
+}
+
+// rowMarker is the tree branch for a row, or a pointer when it's selected
+func rowMarker(selected bool) string {
+	if selected {
+		return styles.selected.Render("▶ ")
+	}
+	return "├─"
+}
+
+// hasRegionBreakdown reports whether services were probed in multiple regions
+func hasRegionBreakdown(state *models.MonitorState) bool {
+	for _, service := range state.AWSServices {
+		if service.Region != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// renderRegionMatrix shows one row per service and one column per region,
+// with each cell holding the current status and the region's availability
+func renderRegionMatrix(state *models.MonitorState, opts DashboardOptions) string {
 	var content strings.Builder
 
-	content.WriteString(headerStyle.Render("STATISTICS"))
+	var services, regions []string
+	seenService := make(map[string]bool)
+	seenRegion := make(map[string]bool)
+	current := make(map[string]models.ServiceStatus)
+	for _, service := range FilterServices(state.AWSServices, opts.Filter) {
+		if !seenService[service.Name] {
+			seenService[service.Name] = true
+			services = append(services, service.Name)
+		}
+		if !seenRegion[service.Region] {
+			seenRegion[service.Region] = true
+			regions = append(regions, service.Region)
+		}
+		current[service.Name+"|"+service.Region] = service
+	}
+
+	const cellWidth = 16
+	content.WriteString(fmt.Sprintf("%-12s", "Service"))
+	for _, region := range regions {
+		content.WriteString(fmt.Sprintf("%-*s", cellWidth, region))
+	}
+	content.WriteString("\n")
+
+	for _, name := range services {
+		content.WriteString(fmt.Sprintf("%-12s", name))
+		for _, region := range regions {
+			service, ok := current[name+"|"+region]
+			if !ok {
+				content.WriteString(fmt.Sprintf("%-*s", cellWidth, "-"))
+				continue
+			}
+
+			icon, style := getServiceStatusDisplay(service.Status)
+			cell := fmt.Sprintf("%s %s", icon, strings.ToUpper(service.Status))
+			if stats, ok := state.Stats.RegionStats[name][region]; ok {
+				cell += fmt.Sprintf(" %.0f%%", stats.AvailabilityPct)
+			}
+			if opts.Changed.Services[service.Label()] {
+				style = styles.flash
+			}
+			if opts.Selected == "service|"+service.Label() {
+				cell = "▶ " + cell
+				style = style.Underline(true)
+			}
+			content.WriteString(style.Render(fmt.Sprintf("%-*s", cellWidth, cell)))
+		}
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
+func renderStatistics(state *models.MonitorState, opts DashboardOptions, width int) string {
+	var content strings.Builder
+
+	content.WriteString(styles.header.Render("STATISTICS"))
+
+	// The one figure to cite for the whole system
+	if availability := state.SystemAvailability; availability != nil {
+		content.WriteString(availabilityStyle(*availability).Copy().Bold(true).Render(fmt.Sprintf("System availability: %.2f%%", *availability)))
+		content.WriteString(styles.dim.Render(" (weighted mean of endpoints and services)") + "\n")
+	}
 
 	// Nginx stats with colors
 	if len(state.Stats.NginxStats) > 0 {
@@ -285,71 +668,254 @@ func renderStatistics(state *models.MonitorState, width int) string {
 		var nginxParts []string
 		for name, stats := range state.Stats.NginxStats {
 			// Color based on success rate
-			var style lipgloss.Style
-			if stats.SuccessRate >= 90 {
-				style = availHighStyle
-			} else if stats.SuccessRate >= 50 {
-				style = availMedStyle
-			} else {
-				style = availLowStyle
-			}
-			nginxParts = append(nginxParts, style.Render(fmt.Sprintf("%s: %d/%d (%.1f%%)",
-				name, stats.TotalChecks-stats.Failures, stats.TotalChecks, stats.SuccessRate)))
+			style := availabilityStyle(stats.SuccessRate)
+			nginxParts = append(nginxParts, style.Render(fmt.Sprintf("%s: %d/%d (%.1f%%)%s",
+				name, stats.TotalChecks-stats.Failures, stats.TotalChecks, stats.SuccessRate, formatDowntime(stats.Downtime)))+
+				formatRolling(stats.RollingAvailability)+
+				formatRate(stats.Rate())+
+				flappingIndicator(stats.Flapping))
 		}
 		content.WriteString(strings.Join(nginxParts, " | "))
 		content.WriteString("\n")
 	}
 
-	// Service stats with colors
+	// Service availability bars
 	if len(state.Stats.ServiceStats) > 0 {
-		content.WriteString("Services: ")
-		var serviceParts []string
-		for name, stats := range state.Stats.ServiceStats {
-			// Color based on availability
-			var style lipgloss.Style
-			if stats.AvailabilityPct >= 90 {
-				style = availHighStyle
-			} else if stats.AvailabilityPct >= 50 {
-				style = availMedStyle
-			} else {
-				style = availLowStyle
-			}
-			serviceParts = append(serviceParts, style.Render(fmt.Sprintf("%s: %.0f%%", name, stats.AvailabilityPct)))
+		content.WriteString("Services:\n")
+		names := sortedStatNames(state.Stats.ServiceStats)
+
+		// Leave room for the name, percentage, downtime and rate around the bar
+		barWidth := width - 48
+		if barWidth < 10 {
+			barWidth = 10
 		}
-		content.WriteString(strings.Join(serviceParts, " | "))
+		var bars []string
+		for _, name := range names {
+			stats := state.Stats.ServiceStats[name]
+			bars = append(bars, fmt.Sprintf("  %-12s %s %s%s%s%s%s",
+				name,
+				renderAvailabilityBar(stats, barWidth),
+				availabilityStyle(stats.AvailabilityPct).Render(fmt.Sprintf("%3.0f%%", stats.AvailabilityPct)),
+				formatRolling(stats.RollingAvailability),
+				formatDowntime(stats.Downtime),
+				formatRate(stats.Rate()),
+				flappingIndicator(stats.Flapping),
+			))
+		}
+		content.WriteString(strings.Join(bars, "\n"))
+	}
+
+	if opts.Baseline != nil {
+		content.WriteString("\nVs baseline: " + renderBaseline(state, opts.Baseline))
 	}
 
 	// Uptime
 	uptime := time.Since(state.Stats.StartTime)
+
+	slo := opts.SLO
+	if slo.Enabled() && (len(state.Stats.ServiceStats) > 0 || state.SystemAvailability != nil) {
+		content.WriteString(fmt.Sprintf("\nBudget remaining (%.2f%% over %s): ", slo.Target, formatWindow(slo.Window)))
+		var budgetParts []string
+		if state.SystemAvailability != nil {
+			remaining := slo.BudgetRemaining(*state.SystemAvailability, uptime)
+			budgetParts = append(budgetParts, budgetStyle(remaining).Render(fmt.Sprintf("System: %.0f%%", remaining)))
+		}
+		for _, name := range sortedStatNames(state.Stats.ServiceStats) {
+			remaining := slo.BudgetRemaining(state.Stats.ServiceStats[name].AvailabilityPct, uptime)
+			budgetParts = append(budgetParts, budgetStyle(remaining).Render(fmt.Sprintf("%s: %.0f%%", name, remaining)))
+		}
+		content.WriteString(strings.Join(budgetParts, " | "))
+	}
+
 	content.WriteString(fmt.Sprintf("\nUptime: %s", uptime.Round(time.Second)))
 
-	return sectionStyle.Width(width - 2).Render(content.String())
+	return styles.section.Width(width - 2).Render(content.String())
+}
+
+// sortedStatNames returns the service names in stable display order
+func sortedStatNames(stats map[string]*models.ServiceStats) []string {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// budgetStyle colors a remaining error budget percentage
+func budgetStyle(remaining float64) lipgloss.Style {
+	if remaining >= 50 {
+		return styles.availHigh
+	} else if remaining >= 20 {
+		return styles.availMed
+	}
+	return styles.availLow
+}
+
+// formatWindow renders an SLO window in days when it's a whole number of them
+func formatWindow(window time.Duration) string {
+	if window%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", window/(24*time.Hour))
+	}
+	return window.String()
+}
+
+// renderAvailabilityBar draws a horizontal bar filled to the service's
+// availability, colored by the availability thresholds
+func renderAvailabilityBar(stats *models.ServiceStats, width int) string {
+	if stats.TotalChecks == 0 {
+		return styles.dim.Render(strings.Repeat("░", width))
+	}
+
+	filled := int(stats.AvailabilityPct/100*float64(width) + 0.5)
+	if filled > width {
+		filled = width
+	}
+	return availabilityStyle(stats.AvailabilityPct).Render(strings.Repeat("█", filled)) +
+		styles.dim.Render(strings.Repeat("░", width-filled))
+}
+
+// formatCountdown renders the time until the next refresh, rounded up to
+// whole seconds
+func formatCountdown(next time.Time) string {
+	if next.IsZero() {
+		return ""
+	}
+	remaining := time.Until(next)
+	if remaining <= 0 {
+		return "updating…"
+	}
+	return fmt.Sprintf("next update in %ds", int((remaining+time.Second-1)/time.Second))
+}
+
+// formatDowntime renders accumulated downtime as a suffix, or nothing when
+// there has been none
+func formatDowntime(downtime time.Duration) string {
+	if downtime == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" Down: %s", downtime.Round(time.Second))
+}
+
+// formatRolling shows the rolling availabilities like load averages,
+// e.g. " [1m/5m/15m 50 90 97%]"
+func formatRolling(rolling []float64) string {
+	if len(rolling) == 0 {
+		return ""
+	}
+	var spans, values []string
+	for i, span := range models.AvailabilityWindows {
+		if i >= len(rolling) {
+			break
+		}
+		spans = append(spans, strings.TrimSuffix(span.String(), "0s"))
+		values = append(values, availabilityStyle(rolling[i]).Render(fmt.Sprintf("%.0f", rolling[i])))
+	}
+	return styles.dim.Render(" ["+strings.Join(spans, "/")+" ") + strings.Join(values, " ") + styles.dim.Render("%]")
+}
+
+// formatRate shows a check rate in checks per second, once there is one
+func formatRate(rate float64) string {
+	if rate == 0 {
+		return ""
+	}
+	return styles.dim.Render(fmt.Sprintf(" %.2f/s", rate))
+}
+
+// flappingIndicator marks stats whose status keeps changing
+func flappingIndicator(flapping bool) string {
+	if !flapping {
+		return ""
+	}
+	return " " + styles.statusWarning.Render("↯ flapping")
+}
+
+// completedSummary describes when a completed test started, ended and how
+// long it ran
+func completedSummary(test models.ActiveChaosTest) string {
+	if test.EndTime.IsZero() {
+		return "last update " + test.LastSeen.Format("15:04:05")
+	}
+	summary := fmt.Sprintf("%s–%s", test.StartTime.Format("15:04:05"), test.EndTime.Format("15:04:05"))
+	if duration := test.Duration(); duration > 0 {
+		summary += ", ran " + duration.Round(time.Second).String()
+	}
+	return summary
+}
+
+// getTestDisplay returns the icon and style for a chaos test type
+func getTestDisplay(testType string) (string, lipgloss.Style) {
+	switch testType {
+	case "region-failure":
+		return "🔥", styles.statusError
+	case "service-outage":
+		return "⚠️", styles.statusError
+	case "api-throttling":
+		return "🚦", styles.statusWarning
+	case "network-latency":
+		return "🌐", styles.statusWarning
+	case "cascade-failure":
+		return "📉", styles.statusError
+	case "resource-exhaustion":
+		return "💾", styles.statusExhausted
+	default:
+		return "🧪", styles.statusWarning
+	}
+}
+
+// testSeverity ranks chaos test types so the most disruptive can be singled out
+func testSeverity(testType string) int {
+	switch testType {
+	case "region-failure", "cascade-failure":
+		return 3
+	case "service-outage":
+		return 2
+	case "resource-exhaustion", "api-throttling":
+		return 1
+	default:
+		return 0
+	}
 }
 
 func getStatusDisplay(status string) (string, lipgloss.Style) {
 	switch status {
 	case "ok":
-		return "✓", statusOKStyle
+		return "✓", styles.statusOK
 	case "failed":
-		return "✗", statusErrorStyle
+		return "✗", styles.statusError
 	case "timeout":
-		return "⏱", statusWarningStyle
+		return "⏱", styles.statusWarning
+	case "throttled":
+		return "⚠", styles.statusWarning
+	case "serving-fallback":
+		return "⇄", styles.statusWarning
 	default:
-		return "?", dimStyle
+		return "?", styles.dim
+	}
+}
+
+// endpointStatusLabel is the status as shown in the endpoint table, short
+// enough for its column
+func endpointStatusLabel(status string) string {
+	if status == "serving-fallback" {
+		return "FALLBACK"
 	}
+	return strings.ToUpper(status)
 }
 
 func getServiceStatusDisplay(status string) (string, lipgloss.Style) {
 	switch status {
 	case "healthy":
-		return "✓", statusOKStyle
+		return "✓", styles.statusOK
 	case "throttled":
-		return "⚠", statusWarningStyle
+		return "⚠", styles.statusWarning
 	case "outage":
-		return "✗", statusErrorStyle
+		return "✗", styles.statusError
 	case "exhausted":
-		return "◆", statusExhaustedStyle
+		return "◆", styles.statusExhausted
+	case "unavailable":
+		return "⊘", styles.dim
 	default:
-		return "?", dimStyle
+		return "?", styles.dim
 	}
 }
\ No newline at end of file
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DetailView describes the endpoint or service shown in the detail overlay
+type DetailView struct {
+	Kind    string // "endpoint" or "service"
+	Name    string
+	Target  string // Endpoint URL or service region, if any
+	Samples []models.CheckSample
+
+	// Response time distribution, for endpoints
+	LatencyBuckets []time.Duration
+	Histogram      []int
+}
+
+// sparkBlocks are the bar heights used by sparklines, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// RenderDetail renders the detail overlay for the selected row
+func RenderDetail(view DetailView, width, height int) string {
+	var content strings.Builder
+
+	title := styles.title.Copy().Width(width - 2).Render(fmt.Sprintf("🔎 %s%s: %s | 'esc' to close", strings.ToUpper(view.Kind[:1]), view.Kind[1:], view.Name))
+
+	content.WriteString(styles.header.Render(strings.ToUpper(view.Name)))
+	if view.Target != "" {
+		content.WriteString(styles.dim.Render(view.Target) + "\n")
+	}
+
+	if len(view.Samples) == 0 {
+		content.WriteString(styles.dim.Render("No checks recorded yet"))
+		return lipgloss.JoinVertical(lipgloss.Left, title, styles.section.Width(width-2).Render(content.String()))
+	}
+
+	latest := view.Samples[len(view.Samples)-1]
+	icon, style := detailStatusDisplay(view.Kind, latest.Status)
+	content.WriteString(fmt.Sprintf("Status:     %s %s (%.3fs, %s)\n",
+		style.Render(icon), style.Render(strings.ToUpper(latest.Status)), latest.ResponseTime, latest.Time.Format("15:04:05")))
+
+	// Last error message
+	lastError := "none"
+	for i := len(view.Samples) - 1; i >= 0; i-- {
+		if sample := view.Samples[i]; !sample.OK {
+			lastError = fmt.Sprintf("%s at %s", sampleDetail(sample), sample.Time.Format("15:04:05"))
+			break
+		}
+	}
+	content.WriteString(fmt.Sprintf("Last error: %s\n", lastError))
+
+	// Response time sparkline and status history, most recent on the right
+	sparkWidth := width - 20
+	if sparkWidth < 10 {
+		sparkWidth = 10
+	}
+	// Longer histories are merged so the whole of it fits
+	var samples []models.CheckSample
+	downsample(len(view.Samples), sparkWidth, func(from, to int) {
+		samples = append(samples, mergeSamples(view.Samples[from:to]))
+	})
+	minTime, maxTime := responseTimeRange(samples)
+	content.WriteString(fmt.Sprintf("\nResponse:   %s  %s\n", sparkline(samples), styles.dim.Render(fmt.Sprintf("%.3fs–%.3fs", minTime, maxTime))))
+	var history strings.Builder
+	for _, sample := range samples {
+		icon, style := detailStatusDisplay(view.Kind, sample.Status)
+		history.WriteString(style.Render(icon))
+	}
+	content.WriteString(fmt.Sprintf("History:    %s\n", history.String()))
+
+	if len(view.Histogram) > 0 {
+		content.WriteString("\n" + styles.header.Render("LATENCY DISTRIBUTION"))
+		content.WriteString(renderHistogram(view.LatencyBuckets, view.Histogram, width-30) + "\n")
+	}
+
+	// Recent status transitions, newest first
+	content.WriteString("\n" + styles.header.Render("TRANSITIONS"))
+	maxTransitions := height - 16
+	if maxTransitions < 3 {
+		maxTransitions = 3
+	}
+	var transitions []string
+	for i := len(view.Samples) - 1; i > 0 && len(transitions) < maxTransitions; i-- {
+		prev, curr := view.Samples[i-1], view.Samples[i]
+		if prev.Status == curr.Status {
+			continue
+		}
+		_, style := detailStatusDisplay(view.Kind, curr.Status)
+		line := fmt.Sprintf("%s  %s → %s", curr.Time.Format("15:04:05"), prev.Status, style.Render(curr.Status))
+		if !curr.OK && curr.Detail != "" {
+			line += styles.dim.Render(" (" + curr.Detail + ")")
+		}
+		transitions = append(transitions, line)
+	}
+	if len(transitions) == 0 {
+		transitions = append(transitions, styles.dim.Render("No transitions in the last "+fmt.Sprint(len(view.Samples))+" checks"))
+	}
+	content.WriteString(strings.Join(transitions, "\n"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, title, styles.section.Width(width-2).Render(content.String()))
+}
+
+// renderHistogram draws one horizontal bar per latency bucket, scaled to
+// the fullest bucket
+func renderHistogram(bounds []time.Duration, counts []int, barWidth int) string {
+	if barWidth < 10 {
+		barWidth = 10
+	}
+	maxCount := 0
+	for _, count := range counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+
+	var lines []string
+	for i, count := range counts {
+		label := ""
+		if i < len(bounds) {
+			label = "<" + bounds[i].String()
+		} else if len(bounds) > 0 {
+			label = "≥" + bounds[len(bounds)-1].String()
+		}
+
+		filled := 0
+		if maxCount > 0 {
+			filled = count * barWidth / maxCount
+		}
+		if count > 0 && filled == 0 {
+			filled = 1
+		}
+		lines = append(lines, fmt.Sprintf("%8s %s %d",
+			label, styles.statusOK.Render(strings.Repeat("█", filled)), count))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// detailStatusDisplay picks the icon set matching the row kind
+func detailStatusDisplay(kind, status string) (string, lipgloss.Style) {
+	if kind == "service" {
+		return getServiceStatusDisplay(status)
+	}
+	return getStatusDisplay(status)
+}
+
+// sampleDetail describes a failed sample, falling back to its status
+func sampleDetail(sample models.CheckSample) string {
+	if sample.Detail != "" {
+		return sample.Detail
+	}
+	return sample.Status
+}
+
+// mergeSamples combines consecutive checks into one for the sparkline:
+// the slowest response time, and the latest failure if any check failed
+func mergeSamples(samples []models.CheckSample) models.CheckSample {
+	merged := samples[len(samples)-1]
+	for _, sample := range samples {
+		merged.ResponseTime = max(merged.ResponseTime, sample.ResponseTime)
+		if !sample.OK {
+			merged.OK, merged.Status, merged.Detail = false, sample.Status, sample.Detail
+		}
+	}
+	return merged
+}
+
+// responseTimeRange returns the lowest and highest response times
+func responseTimeRange(samples []models.CheckSample) (float64, float64) {
+	minTime, maxTime := samples[0].ResponseTime, samples[0].ResponseTime
+	for _, sample := range samples[1:] {
+		if sample.ResponseTime < minTime {
+			minTime = sample.ResponseTime
+		}
+		if sample.ResponseTime > maxTime {
+			maxTime = sample.ResponseTime
+		}
+	}
+	return minTime, maxTime
+}
+
+// sparkline draws response times scaled between their minimum and maximum
+func sparkline(samples []models.CheckSample) string {
+	minTime, maxTime := responseTimeRange(samples)
+	spread := maxTime - minTime
+
+	var line strings.Builder
+	for _, sample := range samples {
+		level := 0
+		if spread > 0 {
+			level = int((sample.ResponseTime - minTime) / spread * float64(len(sparkBlocks)-1))
+		}
+		style := styles.statusOK
+		if !sample.OK {
+			style = styles.statusError
+		}
+		line.WriteString(style.Render(string(sparkBlocks[level])))
+	}
+	return line.String()
+}
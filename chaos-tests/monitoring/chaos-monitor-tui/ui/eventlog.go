@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"chaos-monitor-tui/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EventLogHeight is the number of event lines visible in the log pane
+const EventLogHeight = 8
+
+// TabLogChrome is the number of lines around the log viewport on the Log tab:
+// title, tab bar, section border and header
+const TabLogChrome = 7
+
+// FormatEvents renders events one per line, oldest first, for the log viewport
+func FormatEvents(events []models.Event) string {
+	if len(events) == 0 {
+		return styles.dim.Render("No events recorded yet")
+	}
+
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		var style lipgloss.Style
+		switch event.Severity {
+		case "error":
+			style = styles.statusError
+		case "warning":
+			style = styles.statusWarning
+		case "recovery":
+			style = styles.statusOK
+		default:
+			style = lipgloss.NewStyle()
+		}
+		lines = append(lines, fmt.Sprintf("%s %s",
+			styles.dim.Render(event.Time.Format("15:04:05")),
+			style.Render(event.Message)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderEventLog(view string, width int, hint string) string {
+	var content strings.Builder
+
+	content.WriteString(styles.header.Render("EVENT LOG"))
+	content.WriteString(styles.dim.Render("  " + hint))
+	content.WriteString("\n")
+	content.WriteString(view)
+
+	return styles.section.Width(width - 2).Render(content.String())
+}
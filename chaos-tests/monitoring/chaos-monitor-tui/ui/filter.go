@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"chaos-monitor-tui/models"
+)
+
+// Filter narrows the endpoint and service tables. It only changes which
+// rows are shown; every row is still probed and counted in the statistics.
+type Filter struct {
+	Text        string // Case-insensitive substring of the row name
+	FailingOnly bool   // Hide rows that are currently healthy
+	Editing     bool   // The text is being typed
+}
+
+// Active reports whether any rows can be hidden
+func (f Filter) Active() bool {
+	return f.Text != "" || f.FailingOnly
+}
+
+// String describes the filter for the title bar
+func (f Filter) String() string {
+	var parts []string
+	if f.Text != "" || f.Editing {
+		text := fmt.Sprintf("%q", f.Text)
+		if f.Editing {
+			text = "/" + f.Text + "▏"
+		}
+		parts = append(parts, "filter "+text)
+	}
+	if f.FailingOnly {
+		parts = append(parts, "failing only")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (f Filter) matchName(name string) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(f.Text))
+}
+
+// MatchEndpoint reports whether an endpoint row is shown
+func (f Filter) MatchEndpoint(endpoint models.EndpointStatus) bool {
+	if f.FailingOnly && endpoint.Status == "ok" {
+		return false
+	}
+	return f.matchName(endpoint.Name)
+}
+
+// MatchService reports whether a service row is shown
+func (f Filter) MatchService(service models.ServiceStatus) bool {
+	if f.FailingOnly && service.Status == "healthy" {
+		return false
+	}
+	return f.matchName(service.Label())
+}
+
+// FilterEndpoints returns the endpoints the filter shows
+func FilterEndpoints(endpoints []models.EndpointStatus, f Filter) []models.EndpointStatus {
+	var shown []models.EndpointStatus
+	for _, endpoint := range endpoints {
+		if f.MatchEndpoint(endpoint) {
+			shown = append(shown, endpoint)
+		}
+	}
+	return shown
+}
+
+// FilterServices returns the services the filter shows
+func FilterServices(services []models.ServiceStatus, f Filter) []models.ServiceStatus {
+	var shown []models.ServiceStatus
+	for _, service := range services {
+		if f.MatchService(service) {
+			shown = append(shown, service)
+		}
+	}
+	return shown
+}
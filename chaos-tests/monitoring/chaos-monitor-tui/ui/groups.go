@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"chaos-monitor-tui/models"
+)
+
+// renderEndpointGroups shows one line per endpoint group, e.g.
+// "Regions  DEGRADED (2/3)  US-EAST-2 failing"
+func renderEndpointGroups(groups []models.EndpointGroupStatus) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	var content strings.Builder
+	content.WriteString("\n" + styles.dim.Render("Groups") + "\n")
+	for _, group := range groups {
+		style := styles.statusOK
+		switch group.Health {
+		case "degraded":
+			style = styles.statusWarning
+		case "down":
+			style = styles.statusError
+		}
+		line := fmt.Sprintf("  %-28s %s", group.Name,
+			style.Render(fmt.Sprintf("%s (%d/%d)", strings.ToUpper(group.Health), group.Up, group.Total)))
+		if len(group.Failing) > 0 && group.Health != "down" {
+			line += styles.dim.Render("  " + strings.Join(group.Failing, ", ") + " failing")
+		}
+		content.WriteString(line + "\n")
+	}
+	return content.String()
+}
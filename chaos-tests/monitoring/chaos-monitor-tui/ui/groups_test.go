@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"testing"
+
+	"chaos-monitor-tui/models"
+)
+
+func TestRenderEndpointGroups(t *testing.T) {
+	if got := renderEndpointGroups(nil); got != "" {
+		t.Errorf("no groups rendered %q", got)
+	}
+
+	got := ansi.ReplaceAllString(renderEndpointGroups([]models.EndpointGroupStatus{
+		{Name: "Regions", Health: "degraded", Up: 2, Total: 3, Failing: []string{"US-EAST-2"}},
+		{Name: "Edge", Health: "healthy", Up: 2, Total: 2},
+		{Name: "Legacy", Health: "down", Total: 2, Failing: []string{"Old 1", "Old 2"}},
+	}), "")
+	want := "\nGroups\n" +
+		"  Regions                      DEGRADED (2/3)  US-EAST-2 failing\n" +
+		"  Edge                         HEALTHY (2/2)\n" +
+		"  Legacy                       DOWN (0/2)\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// heatmapLabelWidth is the width of the row name before the heatmap cells
+const heatmapLabelWidth = 28
+
+// heatCell renders one check of an endpoint or service heatmap. Failures
+// carry a mark as well as a color, so the strip reads without color.
+func heatCell(status string) string {
+	switch status {
+	case "ok", "healthy":
+		return styles.heatOK.Render(" ")
+	case "timeout", "throttled", "serving-fallback":
+		return styles.heatWarning.Render("!")
+	case "failed", "outage":
+		return styles.heatError.Render("x")
+	case "exhausted":
+		return styles.heatExhausted.Render("#")
+	default:
+		return styles.heatUnknown.Render("?")
+	}
+}
+
+// heatRank orders check statuses by how bad they are, so a heatmap cell
+// covering several checks shows the worst
+func heatRank(status string) int {
+	switch status {
+	case "ok", "healthy":
+		return 0
+	case "timeout", "throttled", "serving-fallback":
+		return 2
+	case "exhausted":
+		return 3
+	case "failed", "outage":
+		return 4
+	default:
+		return 1
+	}
+}
+
+// renderHeatmapRow renders a row name followed by cells for the recent
+// checks, oldest first. When there are more checks than fit in width, each
+// cell covers several and shows the worst, so the whole retained history
+// is drawn whatever -history-size is. It's empty when there's no room for
+// any cells.
+func renderHeatmapRow(name string, recent []string, width int) string {
+	cells := width - heatmapLabelWidth - 1
+	if cells <= 0 || len(recent) == 0 {
+		return ""
+	}
+	var strip strings.Builder
+	downsample(len(recent), cells, func(from, to int) {
+		worst := recent[from]
+		for _, status := range recent[from+1 : to] {
+			if heatRank(status) > heatRank(worst) {
+				worst = status
+			}
+		}
+		strip.WriteString(heatCell(worst))
+	})
+	return fmt.Sprintf("%-*s %s\n", heatmapLabelWidth, name, strip.String())
+}
+
+// downsample splits n items, oldest first, into at most cells runs of
+// nearly equal length and calls each with a run's bounds. Each item is its
+// own run when they all fit.
+func downsample(n, cells int, each func(from, to int)) {
+	if n <= cells {
+		for i := 0; i < n; i++ {
+			each(i, i+1)
+		}
+		return
+	}
+	for c := 0; c < cells; c++ {
+		each(c*n/cells, (c+1)*n/cells)
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderIntensity renders the chaos intensity score for the title bar,
+// colored along a gradient from the theme's success to error color
+func renderIntensity(score float64) string {
+	color := blendColors(styles.intensityLow, styles.intensityHigh, score/100)
+	return styles.title.Copy().Foreground(color).Render(fmt.Sprintf("Chaos: %.0f", score))
+}
+
+// renderTitleBar renders the title text with the intensity score at its
+// right end. They're separate blocks so the score's color doesn't cut the
+// title's background short.
+func renderTitleBar(text string, score float64, width int) string {
+	intensity := renderIntensity(score)
+	titleWidth := width - lipgloss.Width(intensity)
+	if titleWidth < 0 {
+		titleWidth = 0
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, styles.title.Copy().Width(titleWidth).Render(text), intensity)
+}
+
+// blendColors interpolates between two "#rrggbb" colors; t is clamped to
+// 0-1. Colors that aren't hex fall back to the nearer endpoint.
+func blendColors(from, to lipgloss.Color, t float64) lipgloss.Color {
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+
+	r1, g1, b1, ok1 := parseHexColor(string(from))
+	r2, g2, b2, ok2 := parseHexColor(string(to))
+	if !ok1 || !ok2 {
+		if t < 0.5 {
+			return from
+		}
+		return to
+	}
+
+	mix := func(a, b int) int {
+		return a + int(float64(b-a)*t+0.5)
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", mix(r1, r2), mix(g1, g2), mix(b1, b2)))
+}
+
+func parseHexColor(s string) (r, g, b int, ok bool) {
+	if len(s) != 7 || s[0] != '#' {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff), true
+}
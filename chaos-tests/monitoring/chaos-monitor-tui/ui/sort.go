@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"chaos-monitor-tui/models"
+)
+
+// Sort keys for the endpoint and service tables
+const (
+	SortNone = iota // Insertion order
+	SortName
+	SortStatus
+	SortResponseTime
+	SortAvailability
+)
+
+// SortNames are the sort key labels, indexed by sort key
+var SortNames = []string{"default", "name", "status", "response time", "availability"}
+
+// SortOrder is the sort applied to the endpoint and service tables
+type SortOrder struct {
+	Key  int  // One of the Sort* constants
+	Desc bool // Reverse the order
+}
+
+// String describes the order for table headers
+func (o SortOrder) String() string {
+	if o.Key == SortNone {
+		return ""
+	}
+	arrow := "↑"
+	if o.Desc {
+		arrow = "↓"
+	}
+	return "sorted by " + SortNames[o.Key] + " " + arrow
+}
+
+// endpointSeverity ranks endpoint statuses from healthy to worst
+var endpointSeverity = map[string]int{"ok": 0, "serving-fallback": 1, "throttled": 1, "timeout": 2, "failed": 3}
+
+// serviceSeverity ranks service statuses from healthy to worst
+var serviceSeverity = map[string]int{"healthy": 0, "unavailable": 1, "throttled": 2, "exhausted": 3, "outage": 4}
+
+// SortEndpoints returns the endpoints in display order. The state's slice is
+// left untouched; ties keep their insertion order.
+func SortEndpoints(state *models.MonitorState, order SortOrder) []models.EndpointStatus {
+	endpoints := append([]models.EndpointStatus(nil), state.NginxEndpoints...)
+	if order.Key == SortNone {
+		return endpoints
+	}
+
+	availability := func(e models.EndpointStatus) float64 {
+		if stats, ok := state.Stats.NginxStats[e.Name]; ok {
+			return stats.SuccessRate
+		}
+		return 0
+	}
+	less := func(a, b models.EndpointStatus) bool {
+		switch order.Key {
+		case SortName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case SortStatus:
+			return endpointSeverity[a.Status] < endpointSeverity[b.Status]
+		case SortResponseTime:
+			return a.ResponseTime < b.ResponseTime
+		case SortAvailability:
+			return availability(a) < availability(b)
+		}
+		return false
+	}
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if order.Desc {
+			return less(endpoints[j], endpoints[i])
+		}
+		return less(endpoints[i], endpoints[j])
+	})
+	return endpoints
+}
+
+// SortServices returns the services in display order. The state's slice is
+// left untouched; ties keep their insertion order.
+func SortServices(state *models.MonitorState, order SortOrder) []models.ServiceStatus {
+	services := append([]models.ServiceStatus(nil), state.AWSServices...)
+	if order.Key == SortNone {
+		return services
+	}
+
+	availability := func(s models.ServiceStatus) float64 {
+		if s.Region != "" {
+			if stats, ok := state.Stats.RegionStats[s.Name][s.Region]; ok {
+				return stats.AvailabilityPct
+			}
+		} else if stats, ok := state.Stats.ServiceStats[s.Name]; ok {
+			return stats.AvailabilityPct
+		}
+		return 0
+	}
+	less := func(a, b models.ServiceStatus) bool {
+		switch order.Key {
+		case SortName:
+			return strings.ToLower(a.Label()) < strings.ToLower(b.Label())
+		case SortStatus:
+			return serviceSeverity[a.Status] < serviceSeverity[b.Status]
+		case SortResponseTime:
+			return a.ResponseTime < b.ResponseTime
+		case SortAvailability:
+			return availability(a) < availability(b)
+		}
+		return false
+	}
+	sort.SliceStable(services, func(i, j int) bool {
+		if order.Desc {
+			return less(services[j], services[i])
+		}
+		return less(services[i], services[j])
+	})
+	return services
+}
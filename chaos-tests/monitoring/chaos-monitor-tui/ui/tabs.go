@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+
+	"chaos-monitor-tui/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Dashboard tabs, in display order
+const (
+	TabOverview = iota
+	TabEndpoints
+	TabServices
+	TabTests
+	TabLog
+)
+
+// WideLayoutWidth is the terminal width from which the overview places
+// sections side by side instead of stacking them
+const WideLayoutWidth = 160
+
+// TabNames are the tab labels, indexed by tab
+var TabNames = []string{"Overview", "Endpoints", "Services", "Tests", "Log"}
+
+// renderTabBar shows every tab with its number key, highlighting the active one
+func renderTabBar(active, width int) string {
+	var tabs []string
+	for i, name := range TabNames {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if i == active {
+			tabs = append(tabs, styles.tabActive.Render(label))
+		} else {
+			tabs = append(tabs, styles.tabInactive.Render(label))
+		}
+	}
+	bar := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+	hint := styles.dim.Render("  tab/shift+tab to switch")
+	if lipgloss.Width(bar)+lipgloss.Width(hint) <= width {
+		bar += hint
+	}
+	return bar
+}
+
+// renderTab renders the sections shown on the active tab, reusing the
+// overview's section renderers
+func renderTab(state *models.MonitorState, opts DashboardOptions, width int) []string {
+	switch opts.Tab {
+	case TabEndpoints:
+		return []string{renderNginxStatus(state, opts, width)}
+	case TabServices:
+		return []string{
+			renderServicesStatus(state, opts, width),
+			renderStatistics(state, opts, width),
+		}
+	case TabTests:
+		return []string{renderChaosAPIStatus(state, opts, width)}
+	case TabLog:
+		return []string{renderEventLog(opts.EventLog, width, "↑/↓ pgup/pgdn to scroll")}
+	}
+
+	var sections []string
+	if width >= WideLayoutWidth {
+		// Chaos and tests on the left, endpoints and services on the right.
+		// Each section's width includes its border, so the columns add up
+		// to exactly width.
+		left := width / 2
+		right := width - left
+		sections = []string{lipgloss.JoinHorizontal(lipgloss.Top,
+			renderChaosAPIStatus(state, opts, left),
+			lipgloss.JoinVertical(lipgloss.Left,
+				renderNginxStatus(state, opts, right),
+				renderServicesStatus(state, opts, right),
+			),
+		)}
+	} else {
+		sections = []string{
+			renderChaosAPIStatus(state, opts, width),
+			renderNginxStatus(state, opts, width),
+			renderServicesStatus(state, opts, width),
+		}
+	}
+	sections = append(sections, renderStatistics(state, opts, width))
+	if opts.EventLog != "" {
+		sections = append(sections, renderEventLog(opts.EventLog, width, "↑/↓ pgup/pgdn to scroll, 'l' to hide"))
+	}
+	return sections
+}
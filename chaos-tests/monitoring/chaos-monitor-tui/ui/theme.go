@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme defines the colors used by the dashboard. Status icons (✓ ✗ ⚠ ⏱ ◆)
+// are rendered in every theme, so status never depends on color alone.
+type Theme struct {
+	Success   lipgloss.Color // Healthy / OK
+	Warning   lipgloss.Color // Throttled, timeouts, medium availability
+	Error     lipgloss.Color // Outages and failures
+	Exhausted lipgloss.Color // Resource exhaustion
+	Info      lipgloss.Color // Section headers
+	Dim       lipgloss.Color // Secondary text
+	TitleFg   lipgloss.Color
+	TitleBg   lipgloss.Color
+	Border    lipgloss.Color
+}
+
+// Themes are the built-in themes selectable by name
+var Themes = map[string]Theme{
+	"default": {
+		Success:   "#00ff00", // Bright green
+		Warning:   "#ffaa00", // Orange/yellow
+		Error:     "#ff0000", // Bright red
+		Exhausted: "#aa00ff", // Purple
+		Info:      "#00aaff", // Light blue
+		Dim:       "#666666", // Gray
+		TitleFg:   "#ffffff",
+		TitleBg:   "#5a56e0",
+		Border:    "#5a56e0",
+	},
+	// Okabe-Ito palette: blue for good, orange for bad, distinguishable
+	// with red-green color blindness
+	"colorblind": {
+		Success:   "#56b4e9", // Sky blue
+		Warning:   "#f0e442", // Yellow
+		Error:     "#e69f00", // Orange
+		Exhausted: "#cc79a7", // Reddish purple
+		Info:      "#0072b2", // Blue
+		Dim:       "#888888",
+		TitleFg:   "#ffffff",
+		TitleBg:   "#0072b2",
+		Border:    "#0072b2",
+	},
+	"high-contrast": {
+		Success:   "#00ff00",
+		Warning:   "#ffff00",
+		Error:     "#ff0000",
+		Exhausted: "#ff00ff",
+		Info:      "#00ffff",
+		Dim:       "#c0c0c0",
+		TitleFg:   "#000000",
+		TitleBg:   "#ffffff",
+		Border:    "#ffffff",
+	},
+	// Darker tones that stay legible on a light background
+	"light": {
+		Success:   "#007a00",
+		Warning:   "#b35900",
+		Error:     "#c00000",
+		Exhausted: "#7a00b3",
+		Info:      "#0055aa",
+		Dim:       "#777777",
+		TitleFg:   "#ffffff",
+		TitleBg:   "#3a36b0",
+		Border:    "#3a36b0",
+	},
+}
+
+// styleSet holds the lipgloss styles derived from the active theme
+type styleSet struct {
+	title           lipgloss.Style
+	section         lipgloss.Style
+	header          lipgloss.Style
+	statusOK        lipgloss.Style
+	statusWarning   lipgloss.Style
+	statusError     lipgloss.Style
+	statusExhausted lipgloss.Style
+	dim             lipgloss.Style
+
+	// Availability styles based on percentage
+	availHigh lipgloss.Style
+	availMed  lipgloss.Style
+	availLow  lipgloss.Style
+
+	flash    lipgloss.Style // Rows that changed since the last tick
+	selected lipgloss.Style // Row marker for keyboard/mouse selection
+
+	tabActive   lipgloss.Style
+	tabInactive lipgloss.Style
+
+	// Availability heatmap cells, by status
+	heatOK        lipgloss.Style
+	heatWarning   lipgloss.Style
+	heatError     lipgloss.Style
+	heatExhausted lipgloss.Style
+	heatUnknown   lipgloss.Style
+
+	// Ends of the chaos intensity gradient
+	intensityLow  lipgloss.Color
+	intensityHigh lipgloss.Color
+}
+
+// styles is the style set for the active theme
+var styles = newStyleSet(Themes["default"])
+
+func newStyleSet(t Theme) styleSet {
+	return styleSet{
+		title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(t.TitleFg).
+			Background(t.TitleBg).
+			Padding(0, 1),
+		section: lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(t.Border).
+			Padding(0, 1),
+		header: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(t.Info).
+			MarginBottom(1),
+		statusOK:        lipgloss.NewStyle().Foreground(t.Success).Bold(true),
+		statusWarning:   lipgloss.NewStyle().Foreground(t.Warning).Bold(true),
+		statusError:     lipgloss.NewStyle().Foreground(t.Error).Bold(true),
+		statusExhausted: lipgloss.NewStyle().Foreground(t.Exhausted).Bold(true),
+		dim:             lipgloss.NewStyle().Foreground(t.Dim),
+		availHigh:       lipgloss.NewStyle().Foreground(t.Success), // At or above Thresholds.AvailHigh
+		availMed:        lipgloss.NewStyle().Foreground(t.Warning), // At or above Thresholds.AvailMed
+		availLow:        lipgloss.NewStyle().Foreground(t.Error),   // Below Thresholds.AvailMed
+		flash:           lipgloss.NewStyle().Reverse(true).Bold(true),
+		selected:        lipgloss.NewStyle().Foreground(t.Info).Bold(true),
+		tabActive:       lipgloss.NewStyle().Bold(true).Foreground(t.TitleFg).Background(t.TitleBg).Padding(0, 1),
+		tabInactive:     lipgloss.NewStyle().Foreground(t.Dim).Padding(0, 1),
+		heatOK:          lipgloss.NewStyle().Background(t.Success),
+		heatWarning:     lipgloss.NewStyle().Background(t.Warning).Foreground(lipgloss.Color("#000000")),
+		heatError:       lipgloss.NewStyle().Background(t.Error).Foreground(lipgloss.Color("#000000")),
+		heatExhausted:   lipgloss.NewStyle().Background(t.Exhausted).Foreground(lipgloss.Color("#000000")),
+		heatUnknown:     lipgloss.NewStyle().Background(t.Dim),
+		intensityLow:    t.Success,
+		intensityHigh:   t.Error,
+	}
+}
+
+// SetTheme activates the named theme
+func SetTheme(name string) error {
+	theme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (available: %v)", name, ThemeNames())
+	}
+	styles = newStyleSet(theme)
+	return nil
+}
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Thresholds are the cutoffs the dashboard colors availability, injected
+// latency and measured response times by
+type Thresholds struct {
+	AvailHigh    float64       // Availability percentage at or above which it's shown as healthy
+	AvailMed     float64       // Availability percentage at or above which it's shown as degraded
+	LatencyWarn  time.Duration // Injected latency at or above which it's shown as a warning
+	LatencyError time.Duration // Injected latency at or above which it's shown as an error
+	SlowWarn     time.Duration // Response time at or above which it's shown as slow
+	SlowError    time.Duration // Response time at or above which it's shown as an error
+}
+
+// DefaultThresholds color availability below 90% as degraded and below 50%
+// as failing, injected latency from 1s as a warning and from 5s as an error,
+// and response times from 1s as slow and from 3s, well before the 5s probe
+// timeout, as an error
+var DefaultThresholds = Thresholds{
+	AvailHigh:    90,
+	AvailMed:     50,
+	LatencyWarn:  time.Second,
+	LatencyError: 5 * time.Second,
+	SlowWarn:     time.Second,
+	SlowError:    3 * time.Second,
+}
+
+// thresholds are the active cutoffs
+var thresholds = DefaultThresholds
+
+// SetThresholds activates t for all render sites
+func SetThresholds(t Thresholds) error {
+	if t.AvailMed < 0 || t.AvailHigh > 100 || t.AvailMed > t.AvailHigh {
+		return fmt.Errorf("availability thresholds must satisfy 0 <= medium (%g) <= high (%g) <= 100", t.AvailMed, t.AvailHigh)
+	}
+	if t.LatencyWarn <= 0 || t.LatencyWarn > t.LatencyError {
+		return fmt.Errorf("latency thresholds must satisfy 0 < warning (%s) <= error (%s)", t.LatencyWarn, t.LatencyError)
+	}
+	if t.SlowWarn <= 0 || t.SlowWarn > t.SlowError {
+		return fmt.Errorf("response time thresholds must satisfy 0 < slow (%s) <= error (%s)", t.SlowWarn, t.SlowError)
+	}
+	thresholds = t
+	return nil
+}
+
+// availabilityStyle colors an availability percentage
+func availabilityStyle(pct float64) lipgloss.Style {
+	if pct >= thresholds.AvailHigh {
+		return styles.availHigh
+	} else if pct >= thresholds.AvailMed {
+		return styles.availMed
+	}
+	return styles.availLow
+}
+
+// AvailabilityLevel grades an availability percentage against the
+// thresholds as "good", "warn" or "bad", for output outside the terminal
+func AvailabilityLevel(pct float64) string {
+	if pct >= thresholds.AvailHigh {
+		return "good"
+	} else if pct >= thresholds.AvailMed {
+		return "warn"
+	}
+	return "bad"
+}
+
+// latencyStyle colors an injected latency in milliseconds
+func latencyStyle(ms int) lipgloss.Style {
+	latency := time.Duration(ms) * time.Millisecond
+	if latency >= thresholds.LatencyError {
+		return styles.statusError
+	} else if latency >= thresholds.LatencyWarn {
+		return styles.statusWarning
+	}
+	return styles.dim
+}
+
+// responseTimeStyle colors a measured response time in seconds
+func responseTimeStyle(seconds float64) lipgloss.Style {
+	responseTime := time.Duration(seconds * float64(time.Second))
+	if responseTime >= thresholds.SlowError {
+		return styles.statusError
+	} else if responseTime >= thresholds.SlowWarn {
+		return styles.statusWarning
+	}
+	return styles.statusOK
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// useThresholds activates th for the rest of the test
+func useThresholds(t *testing.T, th Thresholds) {
+	t.Helper()
+	saved := thresholds
+	if err := SetThresholds(th); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { thresholds = saved })
+}
+
+// sameStyle reports whether two styles render in the same colors
+func sameStyle(a, b lipgloss.Style) bool {
+	return a.GetForeground() == b.GetForeground() && a.GetBold() == b.GetBold()
+}
+
+func TestAvailabilityStyle(t *testing.T) {
+	th := DefaultThresholds
+	th.AvailHigh, th.AvailMed = 99, 95
+	useThresholds(t, th)
+
+	tests := []struct {
+		pct   float64
+		want  lipgloss.Style
+		level string
+	}{
+		{100, styles.availHigh, "good"},
+		{99, styles.availHigh, "good"},
+		{98.99, styles.availMed, "warn"},
+		{95, styles.availMed, "warn"},
+		{94.99, styles.availLow, "bad"},
+		{0, styles.availLow, "bad"},
+	}
+	for _, tt := range tests {
+		if got := availabilityStyle(tt.pct); !sameStyle(got, tt.want) {
+			t.Errorf("availabilityStyle(%v) = %v, want %v", tt.pct, got.GetForeground(), tt.want.GetForeground())
+		}
+		if got := AvailabilityLevel(tt.pct); got != tt.level {
+			t.Errorf("AvailabilityLevel(%v) = %q, want %q", tt.pct, got, tt.level)
+		}
+	}
+}
+
+func TestLatencyStyle(t *testing.T) {
+	th := DefaultThresholds
+	th.LatencyWarn, th.LatencyError = 200*time.Millisecond, 2*time.Second
+	useThresholds(t, th)
+
+	tests := []struct {
+		ms   int
+		want lipgloss.Style
+	}{
+		{0, styles.dim},
+		{199, styles.dim},
+		{200, styles.statusWarning},
+		{1999, styles.statusWarning},
+		{2000, styles.statusError},
+	}
+	for _, tt := range tests {
+		if got := latencyStyle(tt.ms); !sameStyle(got, tt.want) {
+			t.Errorf("latencyStyle(%d) = %v, want %v", tt.ms, got.GetForeground(), tt.want.GetForeground())
+		}
+	}
+}
+
+func TestSetThresholdsRejectsInvalid(t *testing.T) {
+	saved := thresholds
+	t.Cleanup(func() { thresholds = saved })
+
+	for name, modify := range map[string]func(*Thresholds){
+		"medium above high":        func(th *Thresholds) { th.AvailMed, th.AvailHigh = 95, 90 },
+		"high above 100":           func(th *Thresholds) { th.AvailHigh = 101 },
+		"negative medium":          func(th *Thresholds) { th.AvailMed = -1 },
+		"zero latency warning":     func(th *Thresholds) { th.LatencyWarn = 0 },
+		"latency warn above error": func(th *Thresholds) { th.LatencyWarn, th.LatencyError = 3*time.Second, time.Second },
+	} {
+		th := DefaultThresholds
+		modify(&th)
+		if err := SetThresholds(th); err == nil {
+			t.Errorf("%s: accepted %+v", name, th)
+		}
+	}
+	if thresholds != saved {
+		t.Errorf("rejected thresholds were applied: %+v", thresholds)
+	}
+}
+
+func TestResponseTimeStyle(t *testing.T) {
+	th := DefaultThresholds
+	th.SlowWarn, th.SlowError = 250*time.Millisecond, 750*time.Millisecond
+	useThresholds(t, th)
+
+	tests := []struct {
+		seconds float64
+		want    lipgloss.Style
+	}{
+		{0, styles.statusOK},
+		{0.249, styles.statusOK},
+		{0.25, styles.statusWarning},
+		{0.749, styles.statusWarning},
+		{0.75, styles.statusError},
+		{5, styles.statusError},
+	}
+	for _, tt := range tests {
+		if got := responseTimeStyle(tt.seconds); !sameStyle(got, tt.want) {
+			t.Errorf("responseTimeStyle(%v) = %v, want %v", tt.seconds, got.GetForeground(), tt.want.GetForeground())
+		}
+	}
+
+	th.SlowWarn, th.SlowError = time.Second, 500*time.Millisecond
+	if err := SetThresholds(th); err == nil {
+		t.Error("slow threshold above the error threshold accepted")
+	}
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"chaos-monitor-tui/models"
+)
+
+// faultTrendLabel precedes the fault count sparkline
+const faultTrendLabel = "   Faults over time "
+
+// renderFaultTrend draws the number of faults and effects configured at
+// each recent refresh, oldest first, scaled from zero to the peak so a
+// ramp up or down reads at a glance. Only the most recent counts that fit
+// in width are drawn; it's empty before the first refresh.
+func renderFaultTrend(counts []models.FaultCount, width int) string {
+	if len(counts) == 0 {
+		return ""
+	}
+	current := counts[len(counts)-1].Total()
+	summary := fmt.Sprintf(" %d now", current)
+
+	cells := width - len(faultTrendLabel) - len(summary) - len(", peak 000")
+	if cells <= 0 {
+		return ""
+	}
+	if len(counts) > cells {
+		counts = counts[len(counts)-cells:]
+	}
+
+	peak := 0
+	for _, c := range counts {
+		peak = max(peak, c.Total())
+	}
+	if peak > current {
+		summary += fmt.Sprintf(", peak %d", peak)
+	}
+
+	var line strings.Builder
+	for _, c := range counts {
+		if c.Total() == 0 {
+			line.WriteString(styles.dim.Render(string(sparkBlocks[0])))
+			continue
+		}
+		level := c.Total() * (len(sparkBlocks) - 1) / peak
+		line.WriteString(styles.statusWarning.Render(string(sparkBlocks[level])))
+	}
+	return styles.dim.Render(faultTrendLabel) + line.String() + styles.dim.Render(summary)
+}
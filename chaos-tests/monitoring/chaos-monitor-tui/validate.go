@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"chaos-monitor-tui/models"
+)
+
+// endpointProblem is a configuration error found before monitoring starts
+type endpointProblem struct {
+	name  string
+	url   string
+	err   string
+	fatal bool // The URL can't be probed at all, as opposed to not resolving
+}
+
+// validateEndpoints checks that each endpoint URL parses and its host
+// resolves, so misconfigured endpoints aren't mistaken for injected failures
+func validateEndpoints(ctx context.Context, endpoints []endpointDef) []endpointProblem {
+	var problems []endpointProblem
+	for _, ep := range endpoints {
+		host, err := endpointHost(ep)
+		if err != nil {
+			problems = append(problems, endpointProblem{name: ep.name, url: ep.url, err: err.Error(), fatal: true})
+			continue
+		}
+		if net.ParseIP(host) != nil {
+			continue
+		}
+
+		lookupCtx, cancel := context.WithTimeout(ctx, ep.timeoutOrDefault())
+		_, err = net.DefaultResolver.LookupHost(lookupCtx, host)
+		cancel()
+		if err != nil {
+			problems = append(problems, endpointProblem{name: ep.name, url: ep.url, err: "host doesn't resolve: " + err.Error()})
+		}
+	}
+	return problems
+}
+
+// endpointHost returns the host an endpoint probes, or why its URL is invalid
+func endpointHost(ep endpointDef) (string, error) {
+	switch ep.probeKind() {
+	case probeTCP:
+		host, _, err := net.SplitHostPort(strings.TrimPrefix(ep.url, "tcp://"))
+		if err != nil {
+			return "", fmt.Errorf("invalid tcp address: %v", err)
+		}
+		return host, nil
+	case probeGRPC:
+		addr, _ := grpcTarget(ep.url)
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return "", fmt.Errorf("invalid grpc address: %v", err)
+		}
+		return host, nil
+	case probeDNS:
+		host := strings.TrimPrefix(ep.url, "dns://")
+		if host == "" {
+			return "", fmt.Errorf("missing host name")
+		}
+		return host, nil
+	}
+
+	u, err := url.Parse(ep.url)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("missing host")
+	}
+	return u.Hostname(), nil
+}
+
+// writeProblems prints endpoint problems as warnings
+func writeProblems(w io.Writer, problems []endpointProblem) {
+	for _, p := range problems {
+		fmt.Fprintf(w, "Warning: endpoint %s (%s): %s\n", p.name, p.url, p.err)
+	}
+}
+
+// hasFatalProblem reports whether any endpoint can't be probed at all
+func hasFatalProblem(problems []endpointProblem) bool {
+	for _, p := range problems {
+		if p.fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// problemEvents turns endpoint problems into event log warnings
+func problemEvents(problems []endpointProblem) []models.Event {
+	var events []models.Event
+	for _, p := range problems {
+		events = append(events, models.Event{
+			Time:     time.Now(),
+			Severity: "warning",
+			Kind:     "config",
+			Message:  fmt.Sprintf("Endpoint %s misconfigured: %s", p.name, p.err),
+		})
+	}
+	return events
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Build metadata, set with -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=..."
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+func versionString() string {
+	return fmt.Sprintf("chaos-monitor-tui %s (commit %s, built %s, %s %s/%s)",
+		version, commit, buildDate, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}